@@ -58,7 +58,35 @@ type action struct {
 
 // NewActionFromProto converts a proto message into a corresponding action struct
 func NewActionFromProto(pbAct *iproto.ActionPb) Action {
-	// TODO: implement the logic
+	switch {
+	case pbAct.GetTransfer() != nil:
+		tsf := &Transfer{}
+		tsf.ConvertFromActionPb(pbAct)
+		return tsf
+	case pbAct.GetVote() != nil:
+		vote := &Vote{}
+		vote.ConvertFromActionPb(pbAct)
+		return vote
+	case pbAct.GetExecution() != nil:
+		execution := &Execution{}
+		execution.ConvertFromActionPb(pbAct)
+		return execution
+	case pbAct.GetSecretProposal() != nil:
+		secretProposal := &SecretProposal{}
+		secretProposal.ConvertFromActionPb(pbAct)
+		return secretProposal
+	case pbAct.GetSecretWitness() != nil:
+		secretWitness := &SecretWitness{}
+		secretWitness.ConvertFromActionPb(pbAct)
+		return secretWitness
+	case pbAct.GetStartSubChain() != nil:
+		return NewStartSubChainFromProto(pbAct)
+	case pbAct.GetStopSubChain() != nil:
+		stop := &StopSubChain{}
+		stop.ConvertFromActionPb(pbAct)
+		return stop
+	}
+	// TODO: support put block once it could be converted to and from proto
 	return nil
 }
 
@@ -7,6 +7,8 @@
 package action
 
 import (
+	"math/big"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/blake2b"
@@ -114,3 +116,6 @@ func (sp *SecretProposal) Hash() hash.Hash32B {
 
 // IntrinsicGas returns the intrinsic gas of a secret proposal
 func (sp *SecretProposal) IntrinsicGas() (uint64, error) { return 0, nil }
+
+// Cost returns the total cost of a secret proposal
+func (sp *SecretProposal) Cost() (*big.Int, error) { return big.NewInt(0), nil }
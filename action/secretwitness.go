@@ -7,6 +7,8 @@
 package action
 
 import (
+	"math/big"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/blake2b"
@@ -108,3 +110,6 @@ func (sw *SecretWitness) Hash() hash.Hash32B {
 
 // IntrinsicGas returns the intrinsic gas of a secret witness
 func (sw *SecretWitness) IntrinsicGas() (uint64, error) { return 0, nil }
+
+// Cost returns the total cost of a secret witness
+func (sw *SecretWitness) Cost() (*big.Int, error) { return big.NewInt(0), nil }
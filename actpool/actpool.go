@@ -7,10 +7,17 @@
 package actpool
 
 import (
+	"container/heap"
+	"context"
 	"fmt"
+	"math/big"
 	"sync"
+	"time"
 
+	"github.com/facebookgo/clock"
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/blockchain"
@@ -18,6 +25,7 @@ import (
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 )
 
 const (
@@ -46,10 +54,37 @@ var (
 	ErrVotee = errors.New("votee is not a candidate")
 	// ErrHash indicates the error of action's hash
 	ErrHash = errors.New("invalid hash")
+	// ErrGasPrice indicates the error of gas price
+	ErrGasPrice = errors.New("invalid gas price")
+
+	evictionMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_actpool_eviction",
+			Help: "IoTeX actpool eviction counter.",
+		},
+		[]string{"reason"},
+	)
 )
 
+const (
+	// evictedForExpiry is the reason of evicting an action which has waited behind a nonce gap for too long
+	evictedForExpiry = "expiry"
+	// evictedForGasPrice is the reason of evicting an action of the lowest gas price to make room for a new one
+	evictedForGasPrice = "gasPrice"
+	// evictedForReplacement is the reason of evicting an action replaced by one of a higher gas price
+	evictedForReplacement = "replacement"
+	// evictedForInvalidity is the reason of evicting an action which becomes unpayable
+	evictedForInvalidity = "invalidity"
+)
+
+func init() {
+	prometheus.MustRegister(evictionMtc)
+}
+
 // ActPool is the interface of actpool
 type ActPool interface {
+	lifecycle.StartStopper
+
 	// Reset resets actpool state
 	Reset()
 	// PickActs returns all currently accepted transfers and votes in actpool
@@ -72,6 +107,10 @@ type ActPool interface {
 	GetSize() uint64
 	// GetCapacity returns the act pool capacity
 	GetCapacity() uint64
+	// MarkLocal marks the action of the given hash as submitted to this node, which is rebroadcast while in pool
+	MarkLocal(hash hash.Hash32B)
+	// LocalActsToRebroadcast returns the actions submitted to this node which are due to be rebroadcast
+	LocalActsToRebroadcast() []action.Action
 }
 
 // ActionValidator is the interface of validating an action
@@ -86,7 +125,54 @@ type actPool struct {
 	bc          blockchain.Blockchain
 	accountActs map[string]ActQueue
 	allActions  map[hash.Hash32B]action.Action
-	validators  []ActionValidator
+	// timestamps records when each action entered the pool
+	timestamps map[hash.Hash32B]time.Time
+	// bytes is the total size of the actions in pool
+	bytes      uint64
+	validators []ActionValidator
+	clk        clock.Clock
+	journal    *journal
+	localActs  map[hash.Hash32B]*localAct
+}
+
+// localAct is the rebroadcast status of an action submitted to this node
+type localAct struct {
+	attempts      uint64
+	nextBroadcast time.Time
+}
+
+// pendingActs is the pending actions of an account that are not picked yet, in nonce order
+type pendingActs struct {
+	sender string
+	acts   []action.Action
+}
+
+// gasPriceQueue is a max heap of accounts by the gas price of their next pending actions, with ties broken by sender
+type gasPriceQueue []*pendingActs
+
+func (h gasPriceQueue) Len() int { return len(h) }
+func (h gasPriceQueue) Less(i, j int) bool {
+	if c := h[i].acts[0].GasPrice().Cmp(h[j].acts[0].GasPrice()); c != 0 {
+		return c > 0
+	}
+	return h[i].sender < h[j].sender
+}
+func (h gasPriceQueue) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *gasPriceQueue) Push(x interface{}) {
+	in, ok := x.(*pendingActs)
+	if !ok {
+		return
+	}
+	*h = append(*h, in)
+}
+
+func (h *gasPriceQueue) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
 }
 
 // NewActPool constructs a new actpool
@@ -99,11 +185,51 @@ func NewActPool(bc blockchain.Blockchain, cfg config.ActPool, validators ...Acti
 		bc:          bc,
 		accountActs: make(map[string]ActQueue),
 		allActions:  make(map[hash.Hash32B]action.Action),
+		timestamps:  make(map[hash.Hash32B]time.Time),
 		validators:  validators,
+		clk:         clock.New(),
+		localActs:   make(map[hash.Hash32B]*localAct),
+	}
+	if cfg.JournalPath != "" {
+		ap.journal = newJournal(cfg.JournalPath)
 	}
 	return ap, nil
 }
 
+// Start reloads the actions from the journal, which are validated again as new ones, and then starts journaling
+func (ap *actPool) Start(_ context.Context) error {
+	if ap.journal == nil {
+		return nil
+	}
+	if err := ap.journal.load(func(act action.Action) error {
+		switch act := act.(type) {
+		case *action.Transfer:
+			return ap.AddTsf(act)
+		case *action.Vote:
+			return ap.AddVote(act)
+		case *action.Execution:
+			return ap.AddExecution(act)
+		default:
+			return ap.Add(act)
+		}
+	}); err != nil {
+		return err
+	}
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	return ap.journal.rotate(ap.allActs())
+}
+
+// Stop closes the journal
+func (ap *actPool) Stop(_ context.Context) error {
+	if ap.journal == nil {
+		return nil
+	}
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	return ap.journal.close()
+}
+
 // Reset resets actpool state
 // Step I: remove all the actions in actpool that have already been committed to block
 // Step II: update pending balance of each account if it still exists in pool
@@ -112,9 +238,13 @@ func NewActPool(bc blockchain.Blockchain, cfg config.ActPool, validators ...Acti
 // unconfirmed but pending actions in pool after update of pending balance
 // Then starting from the current confirmed nonce, iteratively update pending nonce if nonces are consecutive and pending
 // balance is sufficient, and remove all the subsequent actions once the pending balance becomes insufficient
+// Step IV: remove the actions which have waited behind nonce gaps for longer than the expiry
+// Step V: rewrite the journal with the remaining actions, if it has grown enough since the last rewrite
 func (ap *actPool) Reset() {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
+	defer ap.rotateJournal()
+	defer ap.removeExpiredActs()
 
 	// Remove confirmed actions in actpool
 	ap.removeConfirmedActs()
@@ -140,36 +270,64 @@ func (ap *actPool) Reset() {
 	}
 }
 
-// PickActs returns all currently accepted transfers and votes for all accounts
+// PickActs returns the currently accepted transfers and votes for all accounts. The pending actions are picked in the
+// order of gas price across accounts, and in the order of nonce within an account. Once the next action of an account
+// does not fit in the gas to pick, no more action of the account is picked
 func (ap *actPool) PickActs() ([]*action.Transfer, []*action.Vote, []*action.Execution, []action.Action) {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
 
 	numActs := uint64(0)
+	gasLeft := ap.cfg.MaxGasToPick
 	transfers := make([]*action.Transfer, 0)
 	votes := make([]*action.Vote, 0)
 	executions := make([]*action.Execution, 0)
 	actions := make([]action.Action, 0)
-	for _, queue := range ap.accountActs {
-		for _, act := range queue.PendingActs() {
-			switch act.(type) {
-			case *action.Transfer:
-				transfers = append(transfers, act.(*action.Transfer))
-			case *action.Vote:
-				votes = append(votes, act.(*action.Vote))
-			case *action.Execution:
-				executions = append(executions, act.(*action.Execution))
-
-			default:
-				actions = append(actions, act)
-			}
-			numActs++
-			if ap.cfg.MaxNumActsToPick > 0 && numActs >= ap.cfg.MaxNumActsToPick {
+	queue := make(gasPriceQueue, 0, len(ap.accountActs))
+	for sender, actQueue := range ap.accountActs {
+		if acts := actQueue.PendingActs(); len(acts) > 0 {
+			queue = append(queue, &pendingActs{sender: sender, acts: acts})
+		}
+	}
+	heap.Init(&queue)
+	for queue.Len() > 0 {
+		if ap.cfg.MaxNumActsToPick > 0 && numActs >= ap.cfg.MaxNumActsToPick {
+			logger.Debug().
+				Uint64("limit", ap.cfg.MaxNumActsToPick).
+				Msg("reach the max number of actions to pick")
+			break
+		}
+		next := queue[0]
+		act := next.acts[0]
+		if ap.cfg.MaxGasToPick > 0 {
+			gas, err := blockchain.ActionGas(act)
+			if err != nil || gas > gasLeft {
+				actHash := act.Hash()
 				logger.Debug().
-					Uint64("limit", ap.cfg.MaxNumActsToPick).
-					Msg("reach the max number of actions to pick")
-				return transfers, votes, executions, actions
+					Hex("hash", actHash[:]).
+					Uint64("gasLeft", gasLeft).
+					Msg("skip the rest actions of the account as the next one does not fit in the gas to pick")
+				heap.Pop(&queue)
+				continue
 			}
+			gasLeft -= gas
+		}
+		switch act.(type) {
+		case *action.Transfer:
+			transfers = append(transfers, act.(*action.Transfer))
+		case *action.Vote:
+			votes = append(votes, act.(*action.Vote))
+		case *action.Execution:
+			executions = append(executions, act.(*action.Execution))
+
+		default:
+			actions = append(actions, act)
+		}
+		numActs++
+		if next.acts = next.acts[1:]; len(next.acts) == 0 {
+			heap.Pop(&queue)
+		} else {
+			heap.Fix(&queue, 0)
 		}
 	}
 	return transfers, votes, executions, actions
@@ -196,14 +354,6 @@ func (ap *actPool) AddTsf(tsf *action.Transfer) error {
 			Msg("Rejecting invalid transfer")
 		return err
 	}
-	// Reject transfer if pool space is full
-	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
-		logger.Warn().
-			Hex("hash", hash[:]).
-			Msg("Rejecting transfer due to insufficient space")
-		return errors.Wrapf(ErrActPool, "insufficient space for transfer")
-	}
-
 	return ap.enqueueAction(tsf.Sender(), tsf, hash, tsf.Nonce())
 }
 
@@ -228,14 +378,6 @@ func (ap *actPool) AddVote(vote *action.Vote) error {
 			Msg("Rejecting invalid vote")
 		return err
 	}
-	// Reject vote if pool space is full
-	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
-		logger.Warn().
-			Hex("hash", hash[:]).
-			Msg("Rejecting vote due to insufficient space")
-		return errors.Wrapf(ErrActPool, "insufficient space for vote")
-	}
-
 	return ap.enqueueAction(vote.Voter(), vote, hash, vote.Nonce())
 }
 
@@ -259,24 +401,12 @@ func (ap *actPool) AddExecution(exec *action.Execution) error {
 			Msg("Rejecting invalid execution")
 		return err
 	}
-	// Reject execution if pool space is full
-	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
-		logger.Warn().
-			Hex("hash", hash[:]).
-			Msg("Rejecting execution due to insufficient space")
-		return errors.Wrapf(ErrActPool, "insufficient space for execution")
-	}
-
 	return ap.enqueueAction(exec.Executor(), exec, hash, exec.Nonce())
 }
 
 func (ap *actPool) Add(act action.Action) error {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
-	// Reject action if pool space is full
-	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
-		return errors.Wrapf(ErrActPool, "insufficient space for execution")
-	}
 	hash := act.Hash()
 	// Reject action if it already exists in pool
 	if ap.allActions[hash] != nil {
@@ -340,6 +470,47 @@ func (ap *actPool) GetCapacity() uint64 {
 	return ap.cfg.MaxNumActsPerPool
 }
 
+// MarkLocal marks the action of the given hash as submitted to this node, which is rebroadcast while in pool. The
+// action may not be in pool yet as it is added asynchronously
+func (ap *actPool) MarkLocal(hash hash.Hash32B) {
+	if ap.cfg.RebroadcastDelay == 0 {
+		return
+	}
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	ap.localActs[hash] = &localAct{nextBroadcast: ap.clk.Now().Add(ap.cfg.RebroadcastDelay)}
+}
+
+// LocalActsToRebroadcast returns the actions submitted to this node which are still in pool when their rebroadcast is
+// due. The delay of rebroadcasting an action doubles every time, until it has been rebroadcast for the max times
+func (ap *actPool) LocalActsToRebroadcast() []action.Action {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	now := ap.clk.Now()
+	acts := make([]action.Action, 0)
+	for hash, local := range ap.localActs {
+		if now.Before(local.nextBroadcast) {
+			continue
+		}
+		act, ok := ap.allActions[hash]
+		if !ok {
+			// the action has been rejected by pool
+			delete(ap.localActs, hash)
+			continue
+		}
+		acts = append(acts, act)
+		local.attempts++
+		if local.attempts >= ap.cfg.MaxRebroadcasts {
+			delete(ap.localActs, hash)
+			continue
+		}
+		local.nextBroadcast = now.Add(ap.cfg.RebroadcastDelay << local.attempts)
+	}
+	return acts
+}
+
 //======================================
 // private functions
 //======================================
@@ -554,10 +725,7 @@ func (ap *actPool) enqueueAction(sender string, act action.Action, hash hash.Has
 	}
 	if queue.Overlaps(act) {
 		// Nonce already exists
-		logger.Error().
-			Hex("hash", hash[:]).
-			Msg("Rejecting action because replacement action is not supported")
-		return errors.Wrapf(ErrNonce, "duplicate nonce")
+		return ap.replaceAction(queue, act, hash)
 	}
 
 	if actNonce-queue.StartNonce() >= ap.cfg.MaxNumActsPerAcct {
@@ -605,6 +773,16 @@ func (ap *actPool) enqueueAction(sender string, act action.Action, hash hash.Has
 		}
 	}
 
+	// Reject action if pool space is full and no action pays a lower gas price to make room for it. Actions are only
+	// evicted for the one passing all the other checks
+	if !ap.makeRoom(act) {
+		logger.Warn().
+			Hex("hash", hash[:]).
+			Msg("Rejecting action due to insufficient space")
+		return errors.Wrapf(ErrActPool, "insufficient space for action")
+	}
+	// the queue is deleted from pool if all its actions are evicted
+	ap.accountActs[sender] = queue
 	err := queue.Put(act)
 	if err != nil {
 		logger.Warn().
@@ -613,7 +791,7 @@ func (ap *actPool) enqueueAction(sender string, act action.Action, hash hash.Has
 			Msg("cannot put act into ActQueue")
 		return errors.Wrap(err, "cannot put act into ActQueue")
 	}
-	ap.allActions[hash] = act
+	ap.putAction(hash, act)
 	// If the pending nonce equals this nonce, update queue
 	nonce := queue.PendingNonce()
 	if actNonce == nonce {
@@ -622,6 +800,54 @@ func (ap *actPool) enqueueAction(sender string, act action.Action, hash hash.Has
 	return nil
 }
 
+// replaceAction replaces the action of the same nonce in the queue, if the new action pays a gas price high enough
+// than the old one and the account can afford it
+func (ap *actPool) replaceAction(queue ActQueue, act action.Action, hash hash.Hash32B) error {
+	old := queue.Get(act.Nonce())
+	oldHash := old.Hash()
+	minGasPrice := new(big.Int).Mul(old.GasPrice(), new(big.Int).SetUint64(100+ap.cfg.GasPriceBumpToReplace))
+	if new(big.Int).Mul(act.GasPrice(), big.NewInt(100)).Cmp(minGasPrice) <= 0 {
+		logger.Warn().
+			Hex("hash", hash[:]).
+			Hex("replacedHash", oldHash[:]).
+			Msg("Rejecting replacement action due to insufficient gas price")
+		return errors.Wrapf(ErrGasPrice, "gas price too low to replace action %x", oldHash)
+	}
+	cost, err := act.Cost()
+	if err != nil {
+		return errors.Wrap(err, "failed to get cost of replacement action")
+	}
+	// the pending balance has been charged with the cost of the old action if it is pending
+	balance := new(big.Int).Set(queue.PendingBalance())
+	if act.Nonce() < queue.PendingNonce() {
+		oldCost, err := old.Cost()
+		if err != nil {
+			return errors.Wrap(err, "failed to get cost of replaced action")
+		}
+		balance.Add(balance, oldCost)
+	}
+	if balance.Cmp(cost) < 0 {
+		logger.Warn().
+			Hex("hash", hash[:]).
+			Msg("Rejecting replacement action due to insufficient balance")
+		return errors.Wrapf(ErrBalance, "insufficient balance for replacement action")
+	}
+	if _, err := queue.Replace(act); err != nil {
+		return errors.Wrap(err, "cannot replace act in ActQueue")
+	}
+	if act.Nonce() < queue.PendingNonce() {
+		queue.SetPendingBalance(balance.Sub(balance, cost))
+	}
+	ap.deleteAction(oldHash)
+	evictionMtc.WithLabelValues(evictedForReplacement).Inc()
+	ap.putAction(hash, act)
+	logger.Debug().
+		Hex("hash", hash[:]).
+		Hex("replacedHash", oldHash[:]).
+		Msg("Replaced pending action")
+	return nil
+}
+
 // removeConfirmedActs removes processed (committed to block) actions from pool
 func (ap *actPool) removeConfirmedActs() {
 	for from, queue := range ap.accountActs {
@@ -632,8 +858,9 @@ func (ap *actPool) removeConfirmedActs() {
 		}
 		pendingNonce := confirmedNonce + 1
 		// Remove all actions that are committed to new block
-		acts := queue.FilterNonce(pendingNonce)
-		ap.removeInvalidActs(acts)
+		for _, act := range queue.FilterNonce(pendingNonce) {
+			ap.deleteAction(act.Hash())
+		}
 
 		// Delete the queue entry if it becomes empty
 		if queue.Empty() {
@@ -648,7 +875,8 @@ func (ap *actPool) removeInvalidActs(acts []action.Action) {
 		logger.Debug().
 			Hex("hash", hash[:]).
 			Msg("Removed invalidated action")
-		delete(ap.allActions, hash)
+		ap.deleteAction(hash)
+		evictionMtc.WithLabelValues(evictedForInvalidity).Inc()
 	}
 }
 
@@ -665,3 +893,131 @@ func (ap *actPool) updateAccount(sender string) {
 		delete(ap.accountActs, sender)
 	}
 }
+
+// putAction adds the action to the index of all the actions in pool
+func (ap *actPool) putAction(hash hash.Hash32B, act action.Action) {
+	ap.allActions[hash] = act
+	ap.timestamps[hash] = ap.clk.Now()
+	ap.bytes += actionSize(act)
+	if ap.journal != nil {
+		if err := ap.journal.insert(act); err != nil {
+			logger.Error().Err(err).Msg("Error when journaling action")
+		}
+	}
+}
+
+// deleteAction removes the action from the index of all the actions in pool
+func (ap *actPool) deleteAction(hash hash.Hash32B) {
+	act, ok := ap.allActions[hash]
+	if !ok {
+		return
+	}
+	ap.bytes -= actionSize(act)
+	delete(ap.allActions, hash)
+	delete(ap.timestamps, hash)
+	delete(ap.localActs, hash)
+}
+
+// isFull returns whether the pool has no room for an action of the given size
+func (ap *actPool) isFull(size uint64) bool {
+	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
+		return true
+	}
+	return ap.cfg.MaxBytesPerPool > 0 && ap.bytes+size > ap.cfg.MaxBytesPerPool
+}
+
+// makeRoom returns whether there is room for the action in pool. If the pool is full, the expired actions are removed
+// first, and then the last actions of the accounts paying the lowest gas price are evicted one by one, as long as they
+// pay a lower gas price than the new action. Only the last action of an account is evicted so that no nonce gap is
+// left behind
+func (ap *actPool) makeRoom(act action.Action) bool {
+	size := actionSize(act)
+	if !ap.isFull(size) {
+		return true
+	}
+	ap.removeExpiredActs()
+	for ap.isFull(size) {
+		var victim action.Action
+		var victimSender string
+		for sender, queue := range ap.accountActs {
+			acts := queue.AllActs()
+			if len(acts) == 0 {
+				continue
+			}
+			last := acts[len(acts)-1]
+			if victim == nil || last.GasPrice().Cmp(victim.GasPrice()) < 0 {
+				victim = last
+				victimSender = sender
+			}
+		}
+		if victim == nil || victim.GasPrice().Cmp(act.GasPrice()) >= 0 {
+			return false
+		}
+		queue := ap.accountActs[victimSender]
+		queue.Remove(victim.Nonce())
+		victimHash := victim.Hash()
+		ap.deleteAction(victimHash)
+		evictionMtc.WithLabelValues(evictedForGasPrice).Inc()
+		logger.Debug().
+			Hex("hash", victimHash[:]).
+			Msg("Evicted action of low gas price")
+		if queue.Empty() {
+			delete(ap.accountActs, victimSender)
+		}
+	}
+	return true
+}
+
+// removeExpiredActs removes the actions which cannot be picked because of a nonce gap, and have been in pool for
+// longer than the expiry
+func (ap *actPool) removeExpiredActs() {
+	if ap.cfg.ActionExpiry == 0 {
+		return
+	}
+	now := ap.clk.Now()
+	for sender, queue := range ap.accountActs {
+		for _, act := range queue.AllActs() {
+			if act.Nonce() < queue.PendingNonce() {
+				continue
+			}
+			hash := act.Hash()
+			if now.Sub(ap.timestamps[hash]) <= ap.cfg.ActionExpiry {
+				continue
+			}
+			queue.Remove(act.Nonce())
+			ap.deleteAction(hash)
+			evictionMtc.WithLabelValues(evictedForExpiry).Inc()
+			logger.Debug().
+				Hex("hash", hash[:]).
+				Msg("Evicted expired action")
+		}
+		if queue.Empty() {
+			delete(ap.accountActs, sender)
+		}
+	}
+}
+
+// rotateJournal rewrites the journal with the actions in pool, which prunes the removed ones. The journal is only
+// rewritten once it has grown by journalGrowthRatio, rather than on every reset
+func (ap *actPool) rotateJournal() {
+	if ap.journal == nil || !ap.journal.needRotate() {
+		return
+	}
+	if err := ap.journal.rotate(ap.allActs()); err != nil {
+		logger.Error().Err(err).Msg("Error when rotating actpool journal")
+	}
+}
+
+// allActs returns all the actions in pool, in the order of nonce for each account
+func (ap *actPool) allActs() []action.Action {
+	acts := make([]action.Action, 0, len(ap.allActions))
+	for _, queue := range ap.accountActs {
+		acts = append(acts, queue.AllActs()...)
+	}
+	return acts
+}
+
+// actionSize returns the size of the action in bytes
+func actionSize(act action.Action) uint64 {
+	return uint64(proto.Size(act.Proto()))
+}
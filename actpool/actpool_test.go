@@ -10,9 +10,13 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"math/rand"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/facebookgo/clock"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
@@ -287,23 +291,25 @@ func TestActPool_AddActs(t *testing.T) {
 		require.NoError(err)
 		ap2.allActions[nTsf.Hash()] = nTsf
 	}
-	mockBC.EXPECT().Nonce(gomock.Any()).Times(2).Return(uint64(0), nil)
+	// the pool is only found to be full after the transfer passes all the other checks
+	mockBC.EXPECT().Nonce(gomock.Any()).Times(3).Return(uint64(0), nil)
+	mockBC.EXPECT().Balance(gomock.Any()).Times(1).Return(big.NewInt(1000000), nil)
 	mockBC.EXPECT().StateByAddr(gomock.Any()).Times(1).Return(nil, nil)
 	err = ap2.AddTsf(tsf1)
 	require.Equal(ErrActPool, errors.Cause(err))
 	err = ap2.AddVote(vote4)
 	require.Equal(ErrActPool, errors.Cause(err))
-	// Case III: Nonce already exists
+	// Case III: Nonce already exists without a higher gas price
 	replaceTsf, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(1),
 		[]byte{}, uint64(100000), big.NewInt(0))
 	require.NoError(err)
 	err = ap.AddTsf(replaceTsf)
-	require.Equal(ErrNonce, errors.Cause(err))
+	require.Equal(ErrGasPrice, errors.Cause(err))
 	replaceVote, err := action.NewVote(4, addr1.RawAddress, "", uint64(100000), big.NewInt(0))
 	require.NoError(err)
 	require.NoError(action.Sign(replaceVote, addr1.PrivateKey))
 	err = ap.AddVote(replaceVote)
-	require.Equal(ErrNonce, errors.Cause(err))
+	require.Equal(ErrGasPrice, errors.Cause(err))
 	// Case IV: Nonce is too large
 	outOfBoundsTsf, err := testutil.SignedTransfer(addr1, addr1, ap.cfg.MaxNumActsPerAcct+1, big.NewInt(1),
 		[]byte{}, uint64(100000), big.NewInt(0))
@@ -433,6 +439,379 @@ func TestActPool_PickActs(t *testing.T) {
 	})
 }
 
+func TestActPool_PickActsByGasPrice(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	for _, addr := range []string{addr1.RawAddress, addr2.RawAddress, addr3.RawAddress} {
+		_, err := bc.CreateState(addr, uint64(100000000))
+		require.NoError(err)
+	}
+	_, err := bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+
+	// addr1 pays the highest price in its second transfer, which cannot be picked before its first one
+	tsf1, err := testutil.SignedTransfer(addr1, addr1, uint64(1), big.NewInt(10),
+		[]byte("payload"), uint64(100000), big.NewInt(4))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr1, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(10))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr2, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(5))
+	require.NoError(err)
+	tsf4, err := testutil.SignedTransfer(addr3, addr3, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(3))
+	require.NoError(err)
+	createActPool := func(cfg config.ActPool) ActPool {
+		ap, err := NewActPool(bc, cfg)
+		require.NoError(err)
+		for _, tsf := range []*action.Transfer{tsf1, tsf2, tsf3, tsf4} {
+			require.NoError(ap.AddTsf(tsf))
+		}
+		return ap
+	}
+
+	apConfig := getActPoolCfg()
+	pickedTsfs, _, _, _ := createActPool(apConfig).PickActs()
+	require.Equal([]*action.Transfer{tsf3, tsf1, tsf2, tsf4}, pickedTsfs)
+
+	apConfig.MaxNumActsToPick = 2
+	pickedTsfs, _, _, _ = createActPool(apConfig).PickActs()
+	require.Equal([]*action.Transfer{tsf3, tsf1}, pickedTsfs)
+
+	// the transfer with payload does not fit in the gas left, so that addr1 is skipped
+	apConfig.MaxNumActsToPick = 0
+	apConfig.MaxGasToPick = 2*action.TransferBaseIntrinsicGas + 1
+	pickedTsfs, _, _, _ = createActPool(apConfig).PickActs()
+	require.Equal([]*action.Transfer{tsf3, tsf4}, pickedTsfs)
+}
+
+func TestActPool_ReplaceAction(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(100000))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	apConfig := getActPoolCfg()
+	apConfig.GasPriceBumpToReplace = 10
+	Ap, err := NewActPool(bc, apConfig)
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(2))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf1))
+	tsf2, err := testutil.SignedTransfer(addr1, addr2, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf2))
+	// the gas price should be more than 10% higher than the replaced one
+	tsf3, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(20),
+		[]byte{}, uint64(100000), big.NewInt(2))
+	require.NoError(err)
+	require.Equal(ErrGasPrice, errors.Cause(ap.AddTsf(tsf3)))
+	tsf4, err := testutil.SignedTransfer(addr1, addr2, uint64(2), big.NewInt(20),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	require.Equal(ErrGasPrice, errors.Cause(ap.AddTsf(tsf4)))
+	// the account cannot afford the replacement
+	tsf5, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(70000),
+		[]byte{}, uint64(100000), big.NewInt(3))
+	require.NoError(err)
+	require.Equal(ErrBalance, errors.Cause(ap.AddTsf(tsf5)))
+
+	tsf6, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(20),
+		[]byte{}, uint64(100000), big.NewInt(3))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf6))
+	_, err = ap.GetActionByHash(tsf1.Hash())
+	require.Equal(ErrHash, errors.Cause(err))
+	act, err := ap.GetActionByHash(tsf6.Hash())
+	require.NoError(err)
+	require.Equal(tsf6, act)
+	require.Equal(uint64(2), ap.GetSize())
+	pickedTsfs, _, _, _ := ap.PickActs()
+	require.Equal([]*action.Transfer{tsf6, tsf2}, pickedTsfs)
+	cost6, err := tsf6.Cost()
+	require.NoError(err)
+	cost2, err := tsf2.Cost()
+	require.NoError(err)
+	pBalance, err := ap.getPendingBalance(addr1.RawAddress)
+	require.NoError(err)
+	require.Equal(new(big.Int).Sub(big.NewInt(100000), new(big.Int).Add(cost6, cost2)), pBalance)
+}
+
+func TestActPool_EvictActs(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(1000000))
+	require.NoError(err)
+	_, err = bc.CreateState(addr2.RawAddress, uint64(1000000))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	apConfig := getActPoolCfg()
+	apConfig.MaxNumActsPerPool = 3
+	Ap, err := NewActPool(bc, apConfig)
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr2, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr2, addr1, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(3))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf1))
+	require.NoError(ap.AddTsf(tsf2))
+	require.NoError(ap.AddTsf(tsf3))
+	require.Equal(uint64(3), ap.GetSize())
+
+	// no action is evicted for the ones failing the other checks
+	overspend, err := testutil.SignedTransfer(addr2, addr1, uint64(2), big.NewInt(2000000),
+		[]byte{}, uint64(100000), big.NewInt(2))
+	require.NoError(err)
+	require.Equal(ErrBalance, errors.Cause(ap.AddTsf(overspend)))
+	farNonce, err := testutil.SignedTransfer(addr2, addr1, apConfig.MaxNumActsPerAcct+2, big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(2))
+	require.NoError(err)
+	require.Equal(ErrNonce, errors.Cause(ap.AddTsf(farNonce)))
+	require.Error(ap.Add(tsf1))
+	require.Equal(uint64(3), ap.GetSize())
+	require.Equal([]action.Action{tsf1, tsf2}, ap.GetUnconfirmedActs(addr1.RawAddress))
+
+	// the last action of the account paying the lowest gas price is evicted
+	tsf4, err := testutil.SignedTransfer(addr2, addr1, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(2))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf4))
+	require.Equal(uint64(3), ap.GetSize())
+	_, err = ap.GetActionByHash(tsf2.Hash())
+	require.Equal(ErrHash, errors.Cause(err))
+	require.Equal([]action.Action{tsf1}, ap.GetUnconfirmedActs(addr1.RawAddress))
+	pNonce, err := ap.getPendingNonce(addr1.RawAddress)
+	require.NoError(err)
+	require.Equal(uint64(2), pNonce)
+	cost1, err := tsf1.Cost()
+	require.NoError(err)
+	pBalance, err := ap.getPendingBalance(addr1.RawAddress)
+	require.NoError(err)
+	require.Equal(new(big.Int).Sub(big.NewInt(1000000), cost1), pBalance)
+
+	// no action pays a lower gas price than the new one
+	tsf5, err := testutil.SignedTransfer(addr2, addr1, uint64(3), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	require.Equal(ErrActPool, errors.Cause(ap.AddTsf(tsf5)))
+	require.Equal(uint64(3), ap.GetSize())
+
+	// the pool is full of bytes
+	apConfig = getActPoolCfg()
+	apConfig.MaxBytesPerPool = actionSize(tsf1) + actionSize(tsf3)
+	Ap, err = NewActPool(bc, apConfig)
+	require.NoError(err)
+	ap, ok = Ap.(*actPool)
+	require.True(ok)
+	require.NoError(ap.AddTsf(tsf1))
+	require.NoError(ap.AddTsf(tsf3))
+	require.Equal(ErrActPool, errors.Cause(ap.AddTsf(tsf5)))
+	require.NoError(ap.AddTsf(tsf4))
+	require.Equal(uint64(2), ap.GetSize())
+	require.Equal(actionSize(tsf3)+actionSize(tsf4), ap.bytes)
+	require.Empty(ap.GetUnconfirmedActs(addr1.RawAddress))
+}
+
+func TestActPool_ExpireActs(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(1000000))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	apConfig := getActPoolCfg()
+	apConfig.ActionExpiry = time.Minute
+	Ap, err := NewActPool(bc, apConfig)
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	clk := clock.NewMock()
+	ap.clk = clk
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr1, addr2, uint64(3), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf4, err := testutil.SignedTransfer(addr1, addr2, uint64(4), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf1))
+	require.NoError(ap.AddTsf(tsf3))
+	clk.Add(30 * time.Second)
+	require.NoError(ap.AddTsf(tsf4))
+	clk.Add(45 * time.Second)
+
+	// only the action behind the nonce gap which has waited for longer than the expiry is removed
+	ap.Reset()
+	require.Equal([]action.Action{tsf1, tsf4}, ap.GetUnconfirmedActs(addr1.RawAddress))
+	require.Equal(actionSize(tsf1)+actionSize(tsf4), ap.bytes)
+	clk.Add(time.Minute)
+	ap.Reset()
+	require.Equal([]action.Action{tsf1}, ap.GetUnconfirmedActs(addr1.RawAddress))
+	require.Equal(uint64(1), ap.GetSize())
+}
+
+func TestActPool_Journal(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(1000000))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	apConfig := getActPoolCfg()
+	apConfig.JournalPath = "/tmp/test-actpool-journal-" + strconv.Itoa(rand.Int())
+	testutil.CleanupPath(t, apConfig.JournalPath)
+	defer testutil.CleanupPath(t, apConfig.JournalPath)
+
+	Ap, err := NewActPool(bc, apConfig)
+	require.NoError(err)
+	require.NoError(Ap.Start(ctx))
+	tsf1, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr2, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	vote3, err := testutil.SignedVote(addr1, addr1, uint64(3), uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	require.NoError(Ap.AddTsf(tsf1))
+	require.NoError(Ap.AddTsf(tsf2))
+	require.NoError(Ap.AddVote(vote3))
+	require.NoError(Ap.Stop(ctx))
+
+	// the actions are reloaded after restart
+	Ap, err = NewActPool(bc, apConfig)
+	require.NoError(err)
+	require.NoError(Ap.Start(ctx))
+	acts := Ap.GetUnconfirmedActs(addr1.RawAddress)
+	require.Equal(3, len(acts))
+	require.Equal(tsf1.Hash(), acts[0].Hash())
+	require.Equal(tsf2.Hash(), acts[1].Hash())
+	require.Equal(vote3.Hash(), acts[2].Hash())
+
+	// the confirmed actions are pruned from the journal on reset, once the journal has grown enough
+	Ap.(*actPool).journal.minRotateSize = 0
+	countJournal := func() int {
+		loaded := 0
+		require.NoError(newJournal(apConfig.JournalPath).load(func(action.Action) error {
+			loaded++
+			return nil
+		}))
+		return loaded
+	}
+	_, err = bc.GetFactory().RunActions(0, []*action.Transfer{tsf1}, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	Ap.Reset()
+	require.Equal(3, countJournal())
+	for nonce := uint64(4); nonce <= 7; nonce++ {
+		tsf, err := testutil.SignedTransfer(addr1, addr2, nonce, big.NewInt(10),
+			[]byte{}, uint64(100000), big.NewInt(1))
+		require.NoError(err)
+		require.NoError(Ap.AddTsf(tsf))
+	}
+	require.Equal(7, countJournal())
+	Ap.Reset()
+	require.NoError(Ap.Stop(ctx))
+	require.Equal(6, countJournal())
+
+	// the actions confirmed while the node is down are dropped when reloading
+	_, err = bc.GetFactory().RunActions(0, []*action.Transfer{tsf2}, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	Ap, err = NewActPool(bc, apConfig)
+	require.NoError(err)
+	require.NoError(Ap.Start(ctx))
+	acts = Ap.GetUnconfirmedActs(addr1.RawAddress)
+	require.Equal(5, len(acts))
+	require.Equal(vote3.Hash(), acts[0].Hash())
+	require.NoError(Ap.Stop(ctx))
+}
+
+func TestActPool_LocalActsToRebroadcast(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(1000000))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	apConfig := getActPoolCfg()
+	apConfig.RebroadcastDelay = 10 * time.Second
+	apConfig.MaxRebroadcasts = 3
+	Ap, err := NewActPool(bc, apConfig)
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	clk := clock.NewMock()
+	ap.clk = clk
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr2, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr1, addr2, uint64(3), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	// tsf2 is submitted to this node but rejected by pool, and tsf3 is not submitted to this node
+	ap.MarkLocal(tsf1.Hash())
+	ap.MarkLocal(tsf2.Hash())
+	require.NoError(ap.AddTsf(tsf1))
+	require.NoError(ap.AddTsf(tsf3))
+	require.Empty(ap.LocalActsToRebroadcast())
+
+	// the delay of rebroadcast doubles every time until the max times
+	for _, delay := range []time.Duration{10, 20, 40} {
+		clk.Add(delay*time.Second - time.Second)
+		require.Empty(ap.LocalActsToRebroadcast())
+		clk.Add(time.Second)
+		require.Equal([]action.Action{tsf1}, ap.LocalActsToRebroadcast())
+	}
+	require.Empty(ap.localActs)
+	clk.Add(time.Hour)
+	require.Empty(ap.LocalActsToRebroadcast())
+
+	// the action is not rebroadcast once it is removed from pool
+	ap.MarkLocal(tsf3.Hash())
+	_, err = bc.GetFactory().RunActions(0, []*action.Transfer{tsf1, tsf3}, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	ap.Reset()
+	require.Empty(ap.localActs)
+}
+
 func TestActPool_removeConfirmedActs(t *testing.T) {
 	require := require.New(t)
 	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
@@ -42,6 +42,9 @@ func (h *noncePriorityQueue) Pop() interface{} {
 type ActQueue interface {
 	Overlaps(action.Action) bool
 	Put(action.Action) error
+	Get(uint64) action.Action
+	Replace(action.Action) (action.Action, error)
+	Remove(uint64) action.Action
 	FilterNonce(uint64) []action.Action
 	SetStartNonce(uint64)
 	StartNonce() uint64
@@ -97,6 +100,46 @@ func (q *actQueue) Put(act action.Action) error {
 	return nil
 }
 
+// Get returns the action of the given nonce in the queue, or nil if not found
+func (q *actQueue) Get(nonce uint64) action.Action {
+	return q.items[nonce]
+}
+
+// Replace replaces the action of the same nonce in the queue with the given action, and returns the replaced one
+func (q *actQueue) Replace(act action.Action) (action.Action, error) {
+	nonce := act.Nonce()
+	old := q.items[nonce]
+	if old == nil {
+		return nil, errors.Wrapf(ErrNonce, "no action to replace")
+	}
+	q.items[nonce] = act
+	return old, nil
+}
+
+// Remove removes the action of the given nonce from the queue, and returns it, or nil if not found. If the action is
+// pending, the actions following it are no longer pending, and their costs are returned to the pending balance
+func (q *actQueue) Remove(nonce uint64) action.Action {
+	act := q.items[nonce]
+	if act == nil {
+		return nil
+	}
+	if nonce < q.pendingNonce {
+		for n := nonce; n < q.pendingNonce; n++ {
+			cost, _ := q.items[n].Cost()
+			q.pendingBalance.Add(q.pendingBalance, cost)
+		}
+		q.pendingNonce = nonce
+	}
+	for i, n := range q.index {
+		if n == nonce {
+			heap.Remove(&q.index, i)
+			break
+		}
+	}
+	delete(q.items, nonce)
+	return act
+}
+
 // FilterNonce removes all actions from the map with a nonce lower than the given threshold
 func (q *actQueue) FilterNonce(threshold uint64) []action.Action {
 	var removed []action.Action
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/action"
@@ -71,6 +72,25 @@ func TestActQueue_Put(t *testing.T) {
 	require.NotNil(err)
 }
 
+func TestActQueue_Replace(t *testing.T) {
+	require := require.New(t)
+	q := NewActQueue().(*actQueue)
+	tsf1, err := action.NewTransfer(uint64(1), big.NewInt(100), "1", "2", nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	_, err = q.Replace(tsf1)
+	require.Equal(ErrNonce, errors.Cause(err))
+	require.NoError(q.Put(tsf1))
+	require.Equal(tsf1, q.Get(uint64(1)))
+	require.Nil(q.Get(uint64(2)))
+	tsf2, err := action.NewTransfer(uint64(1), big.NewInt(1000), "1", "2", nil, uint64(0), big.NewInt(1))
+	require.NoError(err)
+	old, err := q.Replace(tsf2)
+	require.NoError(err)
+	require.Equal(tsf1, old)
+	require.Equal(tsf2, q.Get(uint64(1)))
+	require.Equal(1, q.Len())
+}
+
 func TestActQueue_FilterNonce(t *testing.T) {
 	require := require.New(t)
 	q := NewActQueue().(*actQueue)
@@ -203,3 +223,34 @@ func TestActQueue_removeActs(t *testing.T) {
 	require.Equal(1, len(q.items))
 	require.Equal([]action.Action{tsf5, vote6}, removed)
 }
+
+func TestActQueue_Remove(t *testing.T) {
+	require := require.New(t)
+	q := NewActQueue().(*actQueue)
+	q.pendingBalance = big.NewInt(1000)
+	for nonce := uint64(1); nonce <= 4; nonce++ {
+		tsf, err := action.NewTransfer(nonce, big.NewInt(100), "1", "2", nil, uint64(0), big.NewInt(0))
+		require.NoError(err)
+		require.NoError(q.Put(tsf))
+	}
+	q.UpdateQueue(q.pendingNonce)
+	require.Equal(uint64(5), q.pendingNonce)
+	require.Equal(big.NewInt(600), q.pendingBalance)
+
+	require.Nil(q.Remove(uint64(5)))
+	tsf := q.Remove(uint64(2))
+	require.NotNil(tsf)
+	require.Equal(uint64(2), tsf.Nonce())
+	require.Equal(3, q.Len())
+	require.Equal([]uint64{1, 3, 4}, func() []uint64 {
+		nonces := []uint64{}
+		for _, act := range q.AllActs() {
+			nonces = append(nonces, act.Nonce())
+		}
+		return nonces
+	}())
+	// the actions following the removed one are no longer pending
+	require.Equal(uint64(2), q.pendingNonce)
+	require.Equal(big.NewInt(900), q.pendingBalance)
+	require.Equal(1, len(q.PendingActs()))
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/enc"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	iproto "github.com/iotexproject/iotex-core/proto"
+)
+
+const (
+	// maxJournalRecordSize is the maximum size of a record in the journal, which is larger than any action accepted by
+	// actpool. A larger size prefix is corrupt and is not allocated
+	maxJournalRecordSize = 4 * ExecutionSizeLimit
+	// journalGrowthRatio is the ratio by which the journal grows since the last rotation before it is rotated again
+	journalGrowthRatio = 2
+	// minJournalRotateSize is the default size below which the journal is not rotated, so that a small or empty journal
+	// is not rotated on every reset
+	minJournalRotateSize = 1 << 20
+)
+
+// journal is an append-only file of the actions accepted by actpool, so that they survive node restarts. Each record
+// is the serialized ActionPb of an action prefixed by its size
+type journal struct {
+	path          string
+	writer        *os.File
+	size          int64 // size of the journal being written
+	rotatedSize   int64 // size of the journal right after the last rotation
+	minRotateSize int64 // size below which the journal is not rotated
+}
+
+func newJournal(path string) *journal {
+	return &journal{path: path, minRotateSize: minJournalRotateSize}
+}
+
+// load reads the actions from the journal and passes them to add. A truncated record at the end, which is left by an
+// interrupted write, is ignored. A corrupt record is skipped, and the rest of the journal is ignored if the size of the
+// record is corrupt
+func (j *journal) load(add func(action.Action) error) error {
+	file, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to open actpool journal %s", j.path)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	sizeBytes := make([]byte, 4)
+	loaded, dropped := 0, 0
+	for {
+		if _, err := io.ReadFull(reader, sizeBytes); err != nil {
+			break
+		}
+		size := enc.MachineEndian.Uint32(sizeBytes)
+		if size > maxJournalRecordSize {
+			logger.Warn().
+				Str("path", j.path).
+				Uint32("size", size).
+				Msg("Ignoring the rest of actpool journal after a record of corrupt size")
+			break
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			break
+		}
+		pbAct := &iproto.ActionPb{}
+		if err := proto.Unmarshal(data, pbAct); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("path", j.path).
+				Msg("Skipping corrupt record in actpool journal")
+			dropped++
+			continue
+		}
+		act := action.NewActionFromProto(pbAct)
+		if act == nil {
+			dropped++
+			continue
+		}
+		// the actions which have been confirmed or become invalid since journaled are rejected by actpool
+		if err := add(act); err != nil {
+			dropped++
+			continue
+		}
+		loaded++
+	}
+	logger.Info().
+		Str("path", j.path).
+		Int("loaded", loaded).
+		Int("dropped", dropped).
+		Msg("Loaded actions from actpool journal")
+	return nil
+}
+
+// insert appends the action to the journal. The actions are not journaled until the journal is opened by rotate
+func (j *journal) insert(act action.Action) error {
+	if j.writer == nil {
+		return nil
+	}
+	data, err := proto.Marshal(act.Proto())
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal action")
+	}
+	n, err := j.writer.Write(append(byteutil.Uint32ToBytes(uint32(len(data))), data...))
+	j.size += int64(n)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write actpool journal %s", j.path)
+	}
+	return nil
+}
+
+// needRotate returns whether the journal has grown by journalGrowthRatio since the last rotation and beyond the min
+// rotate size, so that most of it might be the actions removed from pool since then
+func (j *journal) needRotate() bool {
+	if j.writer == nil {
+		return false
+	}
+	threshold := j.rotatedSize * journalGrowthRatio
+	if threshold < j.minRotateSize {
+		threshold = j.minRotateSize
+	}
+	return j.size > threshold
+}
+
+// rotate rewrites the journal with the given actions, which drops the actions removed from pool, and opens it for
+// appending new actions. The new journal is synced to disk before replacing the old one
+func (j *journal) rotate(acts []action.Action) error {
+	if err := j.close(); err != nil {
+		return err
+	}
+	newPath := j.path + ".new"
+	file, err := os.OpenFile(newPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create actpool journal %s", newPath)
+	}
+	j.writer = file
+	j.size = 0
+	for _, act := range acts {
+		if err := j.insert(act); err != nil {
+			j.close()
+			return err
+		}
+	}
+	if err := j.writer.Sync(); err != nil {
+		j.close()
+		return errors.Wrapf(err, "failed to sync actpool journal %s", newPath)
+	}
+	if err := j.close(); err != nil {
+		return err
+	}
+	if err := os.Rename(newPath, j.path); err != nil {
+		return errors.Wrapf(err, "failed to replace actpool journal %s", j.path)
+	}
+	if j.writer, err = os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+		return errors.Wrapf(err, "failed to open actpool journal %s", j.path)
+	}
+	j.rotatedSize = j.size
+	return nil
+}
+
+// close closes the journal if it is open
+func (j *journal) close() error {
+	if j.writer == nil {
+		return nil
+	}
+	err := j.writer.Close()
+	j.writer = nil
+	if err != nil {
+		return errors.Wrapf(err, "failed to close actpool journal %s", j.path)
+	}
+	return nil
+}
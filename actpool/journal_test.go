@@ -0,0 +1,137 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"math/big"
+	"math/rand"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestJournal(t *testing.T) {
+	require := require.New(t)
+	path := "/tmp/test-actpool-journal-" + strconv.Itoa(rand.Int())
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+
+	j := newJournal(path)
+	loaded := []action.Action{}
+	load := func(act action.Action) error {
+		loaded = append(loaded, act)
+		return nil
+	}
+	// loading a journal which does not exist yet is a no-op
+	require.NoError(j.load(load))
+	require.Empty(loaded)
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr2, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	vote3, err := testutil.SignedVote(addr2, addr2, uint64(1), uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	// the actions are not journaled before the journal is opened
+	require.NoError(j.insert(tsf1))
+	require.NoError(j.rotate([]action.Action{tsf1, tsf2}))
+	require.NoError(j.insert(vote3))
+	require.NoError(j.close())
+	require.NoError(j.load(load))
+	require.Equal(3, len(loaded))
+	require.Equal(tsf1.Hash(), loaded[0].Hash())
+	require.Equal(tsf2.Hash(), loaded[1].Hash())
+	require.Equal(vote3.Hash(), loaded[2].Hash())
+
+	// the removed actions are dropped by rotation, and a truncated record at the end is ignored
+	require.NoError(j.rotate([]action.Action{vote3}))
+	require.NoError(j.close())
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(err)
+	_, err = file.Write([]byte{100, 0})
+	require.NoError(err)
+	require.NoError(file.Close())
+	loaded = []action.Action{}
+	require.NoError(j.load(load))
+	require.Equal(1, len(loaded))
+	require.Equal(vote3.Hash(), loaded[0].Hash())
+
+	// a corrupt record is skipped, and the rest of the journal is ignored after a record of corrupt size
+	require.NoError(j.rotate([]action.Action{vote3}))
+	require.NoError(j.close())
+	file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(err)
+	_, err = file.Write(append(byteutil.Uint32ToBytes(2), 0xff, 0xff))
+	require.NoError(err)
+	j.writer = file
+	require.NoError(j.insert(tsf2))
+	require.NoError(j.close())
+	loaded = []action.Action{}
+	require.NoError(j.load(load))
+	require.Equal(2, len(loaded))
+	require.Equal(vote3.Hash(), loaded[0].Hash())
+	require.Equal(tsf2.Hash(), loaded[1].Hash())
+	require.NoError(j.rotate(nil))
+	require.NoError(j.insert(tsf1))
+	_, err = j.writer.Write(byteutil.Uint32ToBytes(maxJournalRecordSize + 1))
+	require.NoError(err)
+	require.NoError(j.insert(tsf2))
+	require.NoError(j.close())
+	loaded = []action.Action{}
+	require.NoError(j.load(load))
+	require.Equal(1, len(loaded))
+	require.Equal(tsf1.Hash(), loaded[0].Hash())
+}
+
+func TestJournal_NeedRotate(t *testing.T) {
+	require := require.New(t)
+	path := "/tmp/test-actpool-journal-" + strconv.Itoa(rand.Int())
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr2, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr1, addr2, uint64(3), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	j := newJournal(path)
+	// a closed journal is not rotated
+	require.False(j.needRotate())
+	// nor is a small one
+	require.NoError(j.rotate([]action.Action{}))
+	require.NoError(j.insert(tsf1))
+	require.False(j.needRotate())
+	require.NoError(j.rotate([]action.Action{tsf1}))
+	require.NoError(j.insert(tsf2))
+	require.NoError(j.insert(tsf3))
+	require.True(j.size > j.rotatedSize*journalGrowthRatio)
+	require.False(j.needRotate())
+	// the journal is rotated once it has grown by the ratio since the last rotation and beyond the min size
+	for j.size <= j.minRotateSize {
+		require.False(j.needRotate())
+		require.NoError(j.insert(tsf2))
+	}
+	require.True(j.needRotate())
+	require.NoError(j.rotate([]action.Action{tsf3}))
+	require.False(j.needRotate())
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(j.size, info.Size())
+	require.NoError(j.close())
+}
@@ -8,10 +8,10 @@ package blockchain
 
 import (
 	"bytes"
-	"errors"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 	"golang.org/x/crypto/blake2b"
 
 	"github.com/iotexproject/iotex-core/action"
@@ -31,6 +31,10 @@ type Payee struct {
 	Amount  uint64
 }
 
+// receiptRootHeight is the height from which the log bloom is part of the block hash, which is set to
+// chain.receiptRootHeight when the blockchain is created
+var receiptRootHeight uint64
+
 // BlockHeader defines the struct of block header
 // make sure the variable type and order of this struct is same as "BlockHeaderPb" in blockchain.pb.go
 type BlockHeader struct {
@@ -42,6 +46,7 @@ type BlockHeader struct {
 	txRoot        hash.Hash32B      // merkle root of all transactions
 	stateRoot     hash.Hash32B      // root of state trie
 	receiptRoot   hash.Hash32B      // root of receipt trie
+	logBloom      LogBloom          // bloom filter of contract addresses and topics of logs
 	blockSig      []byte            // block signature
 	Pubkey        keypair.PublicKey // block producer's public key
 	DKGID         []byte            // dkg ID of producer
@@ -54,6 +59,58 @@ func (bh *BlockHeader) Timestamp() time.Time {
 	return time.Unix(int64(bh.timestamp), 0)
 }
 
+// Height returns the height in the block header
+func (bh *BlockHeader) Height() uint64 {
+	return bh.height
+}
+
+// TxRoot returns the merkle root of all txs and actions in the block header
+func (bh *BlockHeader) TxRoot() hash.Hash32B {
+	return bh.txRoot
+}
+
+// ReceiptRoot returns the merkle root of all receipts in the block header
+func (bh *BlockHeader) ReceiptRoot() hash.Hash32B {
+	return bh.receiptRoot
+}
+
+// LogBloom returns the bloom filter of the contract addresses and topics of the logs in the block header
+func (bh *BlockHeader) LogBloom() LogBloom {
+	return bh.logBloom
+}
+
+// ByteStream returns a byte stream of the block header
+func (bh *BlockHeader) ByteStream() []byte {
+	stream := make([]byte, 4)
+	enc.MachineEndian.PutUint32(stream, bh.version)
+	tmp4B := make([]byte, 4)
+	enc.MachineEndian.PutUint32(tmp4B, bh.chainID)
+	stream = append(stream, tmp4B...)
+	tmp8B := make([]byte, 8)
+	enc.MachineEndian.PutUint64(tmp8B, bh.height)
+	stream = append(stream, tmp8B...)
+	// TODO: exclude timestamp from block hash because dummy block needs to have a consistent hash no matter which
+	// node produces it at a given height. Once we get rid of the dummy block concept, we need to include it into
+	// the hash block hash again
+	//enc.MachineEndian.PutUint64(tmp8B, bh.timestamp)
+	stream = append(stream, tmp8B...)
+	stream = append(stream, bh.prevBlockHash[:]...)
+	stream = append(stream, bh.txRoot[:]...)
+	stream = append(stream, bh.stateRoot[:]...)
+	stream = append(stream, bh.receiptRoot[:]...)
+	// the log bloom is not part of the hash of the genesis block and the blocks produced before it was introduced
+	if bh.height > 0 && bh.height >= receiptRootHeight {
+		stream = append(stream, bh.logBloom[:]...)
+	}
+	stream = append(stream, bh.Pubkey[:]...)
+	return stream
+}
+
+// HashHeader returns the hash of the block header, which is the hash of the block
+func (bh *BlockHeader) HashHeader() hash.Hash32B {
+	return blake2b.Sum256(bh.ByteStream())
+}
+
 // Block defines the struct of block
 type Block struct {
 	Header          *BlockHeader
@@ -67,6 +124,34 @@ type Block struct {
 	workingSet      state.WorkingSet
 }
 
+// ActionProof is the Merkle proof of an action being included in a block, which consists of the block header and the
+// audit path from the action to the tx root in the header
+type ActionProof struct {
+	BlockHeader *BlockHeader
+	ActionHash  hash.Hash32B
+	Index       int
+	Path        []hash.Hash32B
+}
+
+// Verify verifies that the action is included in the tx root of the block header
+func (p *ActionProof) Verify() bool {
+	return crypto.VerifyAuditPath(p.BlockHeader.txRoot, p.ActionHash, p.Index, p.Path)
+}
+
+// ReceiptProof is the Merkle proof of a receipt being included in a block, which consists of the block header, the
+// receipt and the audit path from the receipt to the receipt root in the header
+type ReceiptProof struct {
+	BlockHeader *BlockHeader
+	Receipt     *Receipt
+	Index       int
+	Path        []hash.Hash32B
+}
+
+// Verify verifies that the receipt is included in the receipt root of the block header
+func (p *ReceiptProof) Verify() bool {
+	return crypto.VerifyAuditPath(p.BlockHeader.receiptRoot, p.Receipt.HashReceipt(), p.Index, p.Path)
+}
+
 // NewBlock returns a new block
 func NewBlock(
 	chainID uint32,
@@ -147,25 +232,7 @@ func (b *Block) PrevHash() hash.Hash32B {
 
 // ByteStreamHeader returns a byte stream of the block header
 func (b *Block) ByteStreamHeader() []byte {
-	stream := make([]byte, 4)
-	enc.MachineEndian.PutUint32(stream, b.Header.version)
-	tmp4B := make([]byte, 4)
-	enc.MachineEndian.PutUint32(tmp4B, b.Header.chainID)
-	stream = append(stream, tmp4B...)
-	tmp8B := make([]byte, 8)
-	enc.MachineEndian.PutUint64(tmp8B, b.Header.height)
-	stream = append(stream, tmp8B...)
-	// TODO: exclude timestamp from block hash because dummy block needs to have a consistent hash no matter which
-	// node produces it at a given height. Once we get rid of the dummy block concept, we need to include it into
-	// the hash block hash again
-	//enc.MachineEndian.PutUint64(tmp8B, b.Header.timestamp)
-	stream = append(stream, tmp8B...)
-	stream = append(stream, b.Header.prevBlockHash[:]...)
-	stream = append(stream, b.Header.txRoot[:]...)
-	stream = append(stream, b.Header.stateRoot[:]...)
-	stream = append(stream, b.Header.receiptRoot[:]...)
-	stream = append(stream, b.Header.Pubkey[:]...)
-	return stream
+	return b.Header.ByteStream()
 }
 
 // ByteStream returns a byte stream of the block
@@ -211,6 +278,10 @@ func (b *Block) ConvertToBlockHeaderPb() *iproto.BlockHeaderPb {
 	pbHeader.TxRoot = b.Header.txRoot[:]
 	pbHeader.StateRoot = b.Header.stateRoot[:]
 	pbHeader.ReceiptRoot = b.Header.receiptRoot[:]
+	if b.Header.logBloom != (LogBloom{}) {
+		// the bloom filter of a block without any log is omitted
+		pbHeader.LogBloom = b.Header.logBloom[:]
+	}
 	pbHeader.Signature = b.Header.blockSig[:]
 	pbHeader.Pubkey = b.Header.Pubkey[:]
 	pbHeader.DkgID = b.Header.DKGID[:]
@@ -260,6 +331,7 @@ func (b *Block) ConvertFromBlockHeaderPb(pbBlock *iproto.BlockPb) {
 	copy(b.Header.txRoot[:], pbBlock.GetHeader().GetTxRoot())
 	copy(b.Header.stateRoot[:], pbBlock.GetHeader().GetStateRoot())
 	copy(b.Header.receiptRoot[:], pbBlock.GetHeader().GetReceiptRoot())
+	copy(b.Header.logBloom[:], pbBlock.GetHeader().GetLogBloom())
 	b.Header.blockSig = pbBlock.GetHeader().GetSignature()
 	copy(b.Header.Pubkey[:], pbBlock.GetHeader().GetPubkey())
 	b.Header.DKGID = pbBlock.GetHeader().GetDkgID()
@@ -325,34 +397,155 @@ func (b *Block) Deserialize(buf []byte) error {
 
 // TxRoot returns the Merkle root of all txs and actions in this block.
 func (b *Block) TxRoot() hash.Hash32B {
+	h := b.actionHashes()
+	if len(h) == 0 {
+		return hash.ZeroHash32B
+	}
+	return crypto.NewMerkleTree(h).HashTree()
+}
+
+// ProveAction returns the index of an action in the Merkle tree of txs and actions in this block, and the audit path
+// from the action to the tx root
+func (b *Block) ProveAction(actHash hash.Hash32B) (int, []hash.Hash32B, error) {
+	h := b.actionHashes()
+	for i := range h {
+		if h[i] == actHash {
+			path, err := crypto.NewMerkleTree(h).AuditPath(i)
+			return i, path, err
+		}
+	}
+	return 0, nil, errors.Errorf("block %x does not have action %x", b.HashBlock(), actHash)
+}
+
+// ReceiptRoot returns the Merkle root of the receipts of the actions in this block
+func (b *Block) ReceiptRoot() hash.Hash32B {
+	h := b.receiptHashes()
+	if len(h) == 0 {
+		return hash.ZeroHash32B
+	}
+	return crypto.NewMerkleTree(h).HashTree()
+}
+
+// ProveReceipt returns the index of the receipt of an action in the Merkle tree of receipts in this block, and the
+// audit path from the receipt to the receipt root
+func (b *Block) ProveReceipt(actHash hash.Hash32B) (int, []hash.Hash32B, error) {
+	receipt, ok := b.receipts[actHash]
+	if !ok {
+		return 0, nil, errors.Errorf("block %x does not have receipt of action %x", b.HashBlock(), actHash)
+	}
+	h := b.receiptHashes()
+	receiptHash := receipt.HashReceipt()
+	for i := range h {
+		if h[i] == receiptHash {
+			path, err := crypto.NewMerkleTree(h).AuditPath(i)
+			return i, path, err
+		}
+	}
+	return 0, nil, errors.Errorf("block %x does not have receipt of action %x", b.HashBlock(), actHash)
+}
+
+// LogBloom returns the bloom filter of the contract addresses and topics of the logs in the receipts of this block
+func (b *Block) LogBloom() LogBloom {
+	var bloom LogBloom
+	for _, receipt := range b.receipts {
+		for _, log := range receipt.Logs {
+			bloom.Add([]byte(log.Address))
+			for _, topic := range log.Topics {
+				bloom.Add(topic[:])
+			}
+		}
+	}
+	return bloom
+}
+
+// receiptHashes returns the hashes of the receipts in the order of actions. Actions without a receipt, such as coinbase
+// transfers and executions failed to run, are skipped
+func (b *Block) receiptHashes() []hash.Hash32B {
+	var h []hash.Hash32B
+	for _, act := range b.allActions() {
+		if receipt, ok := b.receipts[act.Hash()]; ok {
+			h = append(h, receipt.HashReceipt())
+		}
+	}
+	return h
+}
+
+// actionHashes returns the hashes of all txs and actions in this block, in the order of the leaves of tx Merkle tree
+func (b *Block) actionHashes() []hash.Hash32B {
 	var h []hash.Hash32B
+	for _, act := range b.allActions() {
+		h = append(h, act.Hash())
+	}
+	return h
+}
+
+// allActions returns all txs and actions in this block, in the order of the leaves of tx Merkle tree
+func (b *Block) allActions() []action.Action {
+	var acts []action.Action
 	for _, t := range b.Transfers {
-		h = append(h, t.Hash())
+		acts = append(acts, t)
 	}
 	for _, v := range b.Votes {
-		h = append(h, v.Hash())
+		acts = append(acts, v)
 	}
 	for _, e := range b.Executions {
-		h = append(h, e.Hash())
+		acts = append(acts, e)
 	}
 	for _, sp := range b.SecretProposals {
-		h = append(h, sp.Hash())
+		acts = append(acts, sp)
 	}
 	if b.SecretWitness != nil {
-		h = append(h, b.SecretWitness.Hash())
+		acts = append(acts, b.SecretWitness)
 	}
-	for _, act := range b.Actions {
-		h = append(h, act.Hash())
+	return append(acts, b.Actions...)
+}
+
+// gas returns the total gas of the actions in this block, which is bounded by the block gas limit
+func (b *Block) gas() (uint64, error) {
+	total := uint64(0)
+	for _, act := range b.allActions() {
+		gas, err := ActionGas(act)
+		if err != nil {
+			return 0, err
+		}
+		if total+gas < total {
+			return 0, action.ErrOutOfGas
+		}
+		total += gas
 	}
-	if len(h) == 0 {
-		return hash.ZeroHash32B
+	return total, nil
+}
+
+// actionsSize returns the total byte size of the serialized actions in this block, which is bounded by the max block
+// size
+func (b *Block) actionsSize() uint64 {
+	size := uint64(0)
+	for _, act := range b.allActions() {
+		size += uint64(proto.Size(act.Proto()))
 	}
-	return crypto.NewMerkleTree(h).HashTree()
+	return size
+}
+
+// ActionGas returns the gas of an action counted against the block gas limit. It is the intrinsic gas charged for a
+// transfer or vote, and the gas limit reserved for an execution. Coinbase and contract transfers cost no gas
+func ActionGas(act action.Action) (uint64, error) {
+	switch act := act.(type) {
+	case *action.Transfer:
+		if act.IsCoinbase() || act.IsContract() {
+			return 0, nil
+		}
+		return act.IntrinsicGas()
+	case *action.Vote:
+		return act.IntrinsicGas()
+	case *action.Execution:
+		return act.GasLimit(), nil
+	}
+	return 0, nil
 }
 
 // HashBlock return the hash of this block (actually hash of block header)
 func (b *Block) HashBlock() hash.Hash32B {
-	return blake2b.Sum256(b.ByteStreamHeader())
+	return b.Header.HashHeader()
 }
 
 // VerifyStateRoot verifies the state root in header
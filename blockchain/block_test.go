@@ -138,6 +138,97 @@ func TestMerkle(t *testing.T) {
 	require.Equal(hash07[:], hash[:])
 
 	t.Log("Merkle root match pass\n")
+
+	// verify the audit path of each tx
+	for i, tsf := range block.Transfers {
+		index, path, err := block.ProveAction(tsf.Hash())
+		require.NoError(err)
+		require.Equal(i, index)
+		proof := &ActionProof{block.Header, tsf.Hash(), index, path}
+		require.True(proof.Verify())
+		proof.Index = (i + 1) % len(block.Transfers)
+		require.False(proof.Verify())
+	}
+	_, _, err := block.ProveAction(hash)
+	require.Error(err)
+}
+
+func TestReceiptRoot(t *testing.T) {
+	require := require.New(t)
+
+	var executions []*action.Execution
+	receipts := make(map[hash.Hash32B]*Receipt)
+	for i := uint64(1); i <= 3; i++ {
+		execution, err := action.NewExecution(
+			ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, i, big.NewInt(0), uint64(100000),
+			big.NewInt(10), []byte{byte(i)})
+		require.NoError(err)
+		executions = append(executions, execution)
+		receipts[execution.Hash()] = &Receipt{
+			ReturnValue: []byte{byte(i)},
+			Status:      1,
+			Hash:        execution.Hash(),
+			GasConsumed: 10000 + i,
+			Logs:        []*Log{{Address: ta.Addrinfo["alfa"].RawAddress, Data: []byte{byte(i)}}},
+		}
+	}
+	block := NewBlock(0, 1, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, executions, nil)
+	require.Equal(hash.ZeroHash32B, block.ReceiptRoot())
+	block.receipts = receipts
+	block.Header.receiptRoot = block.ReceiptRoot()
+	require.NotEqual(hash.ZeroHash32B, block.Header.receiptRoot)
+	require.Error(verifyReceiptRoot(block))
+	block.Header.logBloom = block.LogBloom()
+	require.NoError(verifyReceiptRoot(block))
+	logBloom := block.Header.LogBloom()
+	require.True(logBloom.Test([]byte(ta.Addrinfo["alfa"].RawAddress)))
+
+	// log bloom is serialized in the block header
+	serialized, err := block.Serialize()
+	require.NoError(err)
+	deserialized := &Block{}
+	require.NoError(deserialized.Deserialize(serialized))
+	require.Equal(block.Header.logBloom, deserialized.Header.logBloom)
+	require.Equal(block.HashBlock(), deserialized.HashBlock())
+	// the log bloom is part of the hash of the blocks from the receipt root height
+	deserialized.Header.logBloom = LogBloom{}
+	require.NotEqual(block.HashBlock(), deserialized.HashBlock())
+	receiptRootHeight = 2
+	defer func() {
+		receiptRootHeight = 0
+	}()
+	require.Equal(block.HashBlock(), deserialized.HashBlock())
+	receiptRootHeight = 0
+
+	// block hash in logs is not part of the receipt root
+	for _, receipt := range receipts {
+		receipt.Logs[0].BlockHash = block.HashBlock()
+	}
+	require.NoError(verifyReceiptRoot(block))
+
+	// verify the audit path of each receipt
+	for i, execution := range executions {
+		index, path, err := block.ProveReceipt(execution.Hash())
+		require.NoError(err)
+		require.Equal(i, index)
+		proof := &ReceiptProof{block.Header, receipts[execution.Hash()], index, path}
+		require.True(proof.Verify())
+		proof.Receipt = receipts[executions[(i+1)%len(executions)].Hash()]
+		require.False(proof.Verify())
+	}
+	_, _, err = block.ProveReceipt(hash.ZeroHash32B)
+	require.Error(err)
+
+	receipts[executions[0].Hash()].GasConsumed++
+	require.Error(verifyReceiptRoot(block))
+
+	// moving bytes across the boundary of variable-size fields changes the hash of the receipt
+	receipt1 := &Receipt{ContractAddress: "ab", ReturnValue: []byte("c")}
+	receipt2 := &Receipt{ContractAddress: "a", ReturnValue: []byte("bc")}
+	require.NotEqual(receipt1.HashReceipt(), receipt2.HashReceipt())
+	receipt1 = &Receipt{Logs: []*Log{{Topics: []hash.Hash32B{hash.ZeroHash32B}}}}
+	receipt2 = &Receipt{Logs: []*Log{{Data: hash.ZeroHash32B[:]}}}
+	require.NotEqual(receipt1.HashReceipt(), receipt2.HashReceipt())
 }
 
 func TestConvertFromBlockPb(t *testing.T) {
@@ -198,7 +289,10 @@ func TestConvertFromBlockPb(t *testing.T) {
 
 func TestWrongRootHash(t *testing.T) {
 	require := require.New(t)
-	val := validator{nil, ""}
+	val := validator{
+		gasLimit:     config.Default.Chain.BlockGasLimit,
+		maxBlockSize: config.Default.Chain.MaxBlockSize,
+	}
 	tsf1, err := action.NewTransfer(1, big.NewInt(20), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	require.NoError(err)
 	require.NoError(action.Sign(tsf1, ta.Addrinfo["producer"].PrivateKey))
@@ -217,7 +311,10 @@ func TestWrongRootHash(t *testing.T) {
 
 func TestSignBlock(t *testing.T) {
 	require := require.New(t)
-	val := validator{nil, ""}
+	val := validator{
+		gasLimit:     config.Default.Chain.BlockGasLimit,
+		maxBlockSize: config.Default.Chain.MaxBlockSize,
+	}
 	tsf1, err := action.NewTransfer(1, big.NewInt(20), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	require.NoError(err)
 	require.NoError(action.Sign(tsf1, ta.Addrinfo["producer"].PrivateKey))
@@ -243,7 +340,11 @@ func TestWrongNonce(t *testing.T) {
 	require.NoError(sf.Start(context.Background()))
 	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
 	require.NoError(err)
-	val := validator{sf, ""}
+	val := validator{
+		sf:           sf,
+		gasLimit:     config.Default.Chain.BlockGasLimit,
+		maxBlockSize: config.Default.Chain.MaxBlockSize,
+	}
 	_, err = sf.RunActions(0, nil, nil, nil, nil)
 	require.Nil(err)
 	require.Nil(sf.Commit(nil))
@@ -355,7 +456,11 @@ func TestWrongCoinbaseTsf(t *testing.T) {
 	require.NoError(sf.Start(context.Background()))
 	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
 	require.Nil(err)
-	val := validator{sf, ""}
+	val := validator{
+		sf:           sf,
+		gasLimit:     config.Default.Chain.BlockGasLimit,
+		maxBlockSize: config.Default.Chain.MaxBlockSize,
+	}
 	_, err = sf.RunActions(0, nil, nil, nil, nil)
 	require.Nil(err)
 
@@ -396,7 +501,10 @@ func TestWrongCoinbaseTsf(t *testing.T) {
 }
 
 func TestWrongAddress(t *testing.T) {
-	val := validator{}
+	val := validator{
+		gasLimit:     config.Default.Chain.BlockGasLimit,
+		maxBlockSize: config.Default.Chain.MaxBlockSize,
+	}
 	invalidRecipient := "io1qyqsyqcyq5narhapakcsrhksfajfcpl24us3xp38zwvsep"
 	tsf, err := action.NewTransfer(1, big.NewInt(1), ta.Addrinfo["producer"].RawAddress, invalidRecipient, []byte{}, uint64(100000), big.NewInt(10))
 	require.NoError(t, err)
@@ -447,7 +555,10 @@ func TestCoinbaseTransferValidation(t *testing.T) {
 	}
 	blk, err := chain.MintNewBlock(nil, nil, nil, nil, &iotxAddr, "")
 	require.NoError(t, err)
-	validator := validator{}
+	validator := validator{
+		gasLimit:     config.Default.Chain.BlockGasLimit,
+		maxBlockSize: config.Default.Chain.MaxBlockSize,
+	}
 	require.NoError(t, validator.verifyActions(blk, true))
 }
 
@@ -497,7 +608,12 @@ func TestValidateSecretBlock(t *testing.T) {
 	err = blk.SignBlock(ta.Addrinfo["producer"])
 	require.NoError(err)
 
-	val := validator{sf, delegates[1]}
+	val := validator{
+		sf:            sf,
+		validatorAddr: delegates[1],
+		gasLimit:      config.Default.Chain.BlockGasLimit,
+		maxBlockSize:  config.Default.Chain.MaxBlockSize,
+	}
 	require.NoError(val.Validate(blk, 2, hash, false))
 
 	// Falsify secret proposal
@@ -10,9 +10,11 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/facebookgo/clock"
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/action"
@@ -59,6 +61,8 @@ type Blockchain interface {
 	GetTransfersFromAddress(address string) ([]hash.Hash32B, error)
 	// GetTransfersToAddress returns transaction to address
 	GetTransfersToAddress(address string) ([]hash.Hash32B, error)
+	// GetTransfersByAddress returns a page of transfers from or to address, and the cursor of the next page
+	GetTransfersByAddress(address string, query *HistoryQuery) ([]hash.Hash32B, string, error)
 	// GetTransfersByTransferHash returns transfer by transfer hash
 	GetTransferByTransferHash(h hash.Hash32B) (*action.Transfer, error)
 	// GetBlockHashByTransferHash returns Block hash by transfer hash
@@ -67,6 +71,8 @@ type Blockchain interface {
 	GetVotesFromAddress(address string) ([]hash.Hash32B, error)
 	// GetVoteToAddress returns vote to address
 	GetVotesToAddress(address string) ([]hash.Hash32B, error)
+	// GetVotesByAddress returns a page of votes from or to address, and the cursor of the next page
+	GetVotesByAddress(address string, query *HistoryQuery) ([]hash.Hash32B, string, error)
 	// GetVotesByVoteHash returns vote by vote hash
 	GetVoteByVoteHash(h hash.Hash32B) (*action.Vote, error)
 	// GetBlockHashByVoteHash returns Block hash by vote hash
@@ -75,12 +81,29 @@ type Blockchain interface {
 	GetExecutionsFromAddress(address string) ([]hash.Hash32B, error)
 	// GetExecutionsToAddress returns executions to address
 	GetExecutionsToAddress(address string) ([]hash.Hash32B, error)
+	// GetExecutionsByAddress returns a page of executions from or to address, and the cursor of the next page
+	GetExecutionsByAddress(address string, query *HistoryQuery) ([]hash.Hash32B, string, error)
 	// GetExecutionByExecutionHash returns execution by execution hash
 	GetExecutionByExecutionHash(h hash.Hash32B) (*action.Execution, error)
 	// GetBlockHashByExecutionHash returns Block hash by execution hash
 	GetBlockHashByExecutionHash(h hash.Hash32B) (hash.Hash32B, error)
 	// GetReceiptByExecutionHash returns the receipt by execution hash
 	GetReceiptByExecutionHash(h hash.Hash32B) (*Receipt, error)
+	// GetReceiptByActionHash returns the receipt by the hash of tx or action of any type
+	GetReceiptByActionHash(h hash.Hash32B) (*Receipt, error)
+	// GetActionByHash returns tx or action of any type by hash
+	GetActionByHash(h hash.Hash32B) (action.Action, error)
+	// GetBlockHashByActionHash returns Block hash by the hash of tx or action of any type
+	GetBlockHashByActionHash(h hash.Hash32B) (hash.Hash32B, error)
+	// GetActionsByAddress returns a page of txs and actions of any type from or to address, and the cursor of the next
+	// page
+	GetActionsByAddress(address string, query *HistoryQuery) ([]hash.Hash32B, string, error)
+	// GetActionProof returns the Merkle proof of a tx or action of any type being included in a block
+	GetActionProof(h hash.Hash32B) (*ActionProof, error)
+	// GetReceiptProof returns the Merkle proof of the receipt of an execution being included in a block
+	GetReceiptProof(h hash.Hash32B) (*ReceiptProof, error)
+	// GetLogs returns the logs satisfying the filter, in the order they are emitted
+	GetLogs(filter *LogFilter) ([]*Log, error)
 	// GetFactory returns the State Factory
 	GetFactory() state.Factory
 	// GetChainID returns the chain ID
@@ -91,6 +114,10 @@ type Blockchain interface {
 	TipHeight() uint64
 	// StateByAddr returns state of a given address
 	StateByAddr(address string) (*state.State, error)
+	// StateAtHeight returns state of a given address at a given height
+	StateAtHeight(address string, height uint64) (*state.State, error)
+	// BalanceAtHeight returns balance of a given address at a given height
+	BalanceAtHeight(address string, height uint64) (*big.Int, error)
 
 	// For block operations
 	// MintNewBlock creates a new block with given actions
@@ -124,6 +151,8 @@ type Blockchain interface {
 	CommitBlock(blk *Block) error
 	// ValidateBlock validates a new block before adding it to the blockchain
 	ValidateBlock(blk *Block, containCoinbase bool) error
+	// RollbackTo rolls the chain and the states back to a given height, and deletes all blocks after it
+	RollbackTo(height uint64) error
 
 	// For action operations
 	// Validator returns the current validator object
@@ -135,6 +164,11 @@ type Blockchain interface {
 	// ExecuteContractRead runs a read-only smart contract operation, this is done off the network since it does not
 	// cause any state change
 	ExecuteContractRead(*action.Execution) ([]byte, error)
+	// EstimateGas returns the minimal gas limit with which the execution succeeds, by running it off the network with
+	// different gas limits
+	EstimateGas(*action.Execution) (uint64, error)
+	// TraceExecution re-runs an execution in the chain off the network, and returns the opcode-level trace of it
+	TraceExecution(h hash.Hash32B) (*ExecutionTrace, error)
 
 	// SubscribeBlockCreation make you listen to every single produced block
 	SubscribeBlockCreation(ch chan *Block) error
@@ -242,6 +276,7 @@ func ClockOption(clk clock.Clock) Option {
 
 // NewBlockchain creates a new blockchain and DB instance
 func NewBlockchain(cfg *config.Config, opts ...Option) Blockchain {
+	receiptRootHeight = cfg.Chain.ReceiptRootHeight
 	// create the Blockchain
 	chain := &blockchain{
 		config:  cfg,
@@ -266,7 +301,13 @@ func NewBlockchain(cfg *config.Config, opts ...Option) Blockchain {
 		logger.Error().Err(err).Msg("Failed to get producer's address by public key")
 		return nil
 	}
-	chain.validator = &validator{sf: chain.sf, validatorAddr: address.IotxAddress()}
+	chain.validator = &validator{
+		sf:            chain.sf,
+		validatorAddr: address.IotxAddress(),
+		gasLimit:      cfg.Chain.BlockGasLimit,
+		maxBlockSize:  cfg.Chain.MaxBlockSize,
+		limitHeight:   cfg.Chain.BlockLimitHeight,
+	}
 
 	if chain.dao != nil {
 		chain.lifecycle.Add(chain.dao)
@@ -293,12 +334,29 @@ func (bc *blockchain) Start(ctx context.Context) (err error) {
 		return err
 	}
 	if bc.tipHeight == 0 {
-		return bc.startEmptyBlockchain()
+		if err := bc.startEmptyBlockchain(); err != nil {
+			return err
+		}
+		return bc.bootstrapFromSnapshot()
+	}
+	// finish the rollback interrupted last time before replaying the blocks to the states
+	rollbackHeight, ok, err := bc.dao.getRollbackHeight()
+	if err != nil {
+		return err
+	}
+	if ok {
+		logger.Warn().Uint64("height", rollbackHeight).Msg("Resuming interrupted rollback")
+		if err := bc.rollbackTo(rollbackHeight); err != nil {
+			return err
+		}
 	}
 	// get blockchain tip hash
 	if bc.tipHash, err = bc.dao.getBlockHash(bc.tipHeight); err != nil {
 		return err
 	}
+	if err := bc.bootstrapFromSnapshot(); err != nil {
+		return err
+	}
 	recoveryHeight, _ := ctx.Value(RecoveryHeightKey).(uint64)
 	return bc.startExistingBlockchain(recoveryHeight)
 }
@@ -491,6 +549,14 @@ func (bc *blockchain) GetTransfersToAddress(address string) ([]hash.Hash32B, err
 	return bc.dao.getTransfersByRecipientAddress(address)
 }
 
+// GetTransfersByAddress returns a page of transfers from or to address, and the cursor of the next page
+func (bc *blockchain) GetTransfersByAddress(address string, query *HistoryQuery) ([]hash.Hash32B, string, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, "", errors.New("explorer not enabled")
+	}
+	return bc.dao.getActionsByAddress(transferIndices, address, query)
+}
+
 // GetTransferByTransferHash returns transfer by transfer hash
 func (bc *blockchain) GetTransferByTransferHash(h hash.Hash32B) (*action.Transfer, error) {
 	if !bc.config.Explorer.Enabled {
@@ -536,6 +602,14 @@ func (bc *blockchain) GetVotesToAddress(address string) ([]hash.Hash32B, error)
 	return bc.dao.getVotesByRecipientAddress(address)
 }
 
+// GetVotesByAddress returns a page of votes from or to address, and the cursor of the next page
+func (bc *blockchain) GetVotesByAddress(address string, query *HistoryQuery) ([]hash.Hash32B, string, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, "", errors.New("explorer not enabled")
+	}
+	return bc.dao.getActionsByAddress(voteIndices, address, query)
+}
+
 // GetVotesByVoteHash returns vote by vote hash
 func (bc *blockchain) GetVoteByVoteHash(h hash.Hash32B) (*action.Vote, error) {
 	if !bc.config.Explorer.Enabled {
@@ -581,6 +655,14 @@ func (bc *blockchain) GetExecutionsToAddress(address string) ([]hash.Hash32B, er
 	return bc.dao.getExecutionsByContractAddress(address)
 }
 
+// GetExecutionsByAddress returns a page of executions from or to address, and the cursor of the next page
+func (bc *blockchain) GetExecutionsByAddress(address string, query *HistoryQuery) ([]hash.Hash32B, string, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, "", errors.New("explorer not enabled")
+	}
+	return bc.dao.getActionsByAddress(executionIndices, address, query)
+}
+
 // GetExecutionByExecutionHash returns execution by execution hash
 func (bc *blockchain) GetExecutionByExecutionHash(h hash.Hash32B) (*action.Execution, error) {
 	if !bc.config.Explorer.Enabled {
@@ -612,10 +694,158 @@ func (bc *blockchain) GetBlockHashByExecutionHash(h hash.Hash32B) (hash.Hash32B,
 
 // GetReceiptByExecutionHash returns the receipt by execution hash
 func (bc *blockchain) GetReceiptByExecutionHash(h hash.Hash32B) (*Receipt, error) {
+	return bc.GetReceiptByActionHash(h)
+}
+
+// GetReceiptByActionHash returns the receipt by the hash of tx or action of any type
+func (bc *blockchain) GetReceiptByActionHash(h hash.Hash32B) (*Receipt, error) {
 	if !bc.config.Explorer.Enabled {
 		return nil, errors.New("explorer not enabled")
 	}
-	return bc.dao.getReceiptByExecutionHash(h)
+	return bc.dao.getReceiptByActionHash(h)
+}
+
+// GetActionByHash returns tx or action of any type by hash
+func (bc *blockchain) GetActionByHash(h hash.Hash32B) (action.Action, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, errors.New("explorer not enabled")
+	}
+	blkHash, err := bc.dao.getBlockHashByActionHash(h)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := bc.dao.getBlock(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	for _, act := range blk.allActions() {
+		if act.Hash() == h {
+			return act, nil
+		}
+	}
+	return nil, errors.Errorf("block %x does not have action %x", blkHash, h)
+}
+
+// GetBlockHashByActionHash returns Block hash by the hash of tx or action of any type
+func (bc *blockchain) GetBlockHashByActionHash(h hash.Hash32B) (hash.Hash32B, error) {
+	if !bc.config.Explorer.Enabled {
+		return hash.ZeroHash32B, errors.New("explorer not enabled")
+	}
+	return bc.dao.getBlockHashByActionHash(h)
+}
+
+// GetActionsByAddress returns a page of txs and actions of any type from or to address, and the cursor of the next page
+func (bc *blockchain) GetActionsByAddress(address string, query *HistoryQuery) ([]hash.Hash32B, string, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, "", errors.New("explorer not enabled")
+	}
+	return bc.dao.getActionsByAddress(actionIndices, address, query)
+}
+
+// GetActionProof returns the Merkle proof of a tx or action of any type being included in a block
+func (bc *blockchain) GetActionProof(h hash.Hash32B) (*ActionProof, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, errors.New("explorer not enabled")
+	}
+	blkHash, err := bc.dao.getBlockHashByActionHash(h)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := bc.dao.getBlock(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	index, path, err := blk.ProveAction(h)
+	if err != nil {
+		return nil, err
+	}
+	return &ActionProof{
+		BlockHeader: blk.Header,
+		ActionHash:  h,
+		Index:       index,
+		Path:        path,
+	}, nil
+}
+
+// GetReceiptProof returns the Merkle proof of the receipt of an execution being included in a block
+func (bc *blockchain) GetReceiptProof(h hash.Hash32B) (*ReceiptProof, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, errors.New("explorer not enabled")
+	}
+	blkHash, err := bc.dao.getBlockHashByExecutionHash(h)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := bc.dao.getBlock(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	// receipts are stored separately from the block
+	blk.receipts = make(map[hash.Hash32B]*Receipt)
+	for _, act := range blk.allActions() {
+		if receipt, err := bc.dao.getReceiptByActionHash(act.Hash()); err == nil {
+			blk.receipts[act.Hash()] = receipt
+		}
+	}
+	index, path, err := blk.ProveReceipt(h)
+	if err != nil {
+		return nil, err
+	}
+	return &ReceiptProof{
+		BlockHeader: blk.Header,
+		Receipt:     blk.receipts[h],
+		Index:       index,
+		Path:        path,
+	}, nil
+}
+
+// GetLogs returns the logs satisfying the filter, in the order they are emitted. If explorer is enabled, only the blocks
+// in the log index of the addresses and topics are read, otherwise the blocks in the range are skipped by the log bloom
+// in the header
+func (bc *blockchain) GetLogs(filter *LogFilter) ([]*Log, error) {
+	to := filter.ToHeight
+	if tip := bc.TipHeight(); to == 0 || to > tip {
+		to = tip
+	}
+	if filter.FromHeight > to {
+		return nil, errors.Errorf("invalid height range [%d, %d]", filter.FromHeight, filter.ToHeight)
+	}
+	if to-filter.FromHeight >= bc.config.Explorer.MaxLogQueryRange {
+		return nil, errors.Errorf("height range [%d, %d] exceeds the limit of %d blocks", filter.FromHeight, to,
+			bc.config.Explorer.MaxLogQueryRange)
+	}
+	heights, err := bc.logHeights(filter, to)
+	if err != nil {
+		return nil, err
+	}
+	logs := []*Log{}
+	for _, height := range heights {
+		blk, err := bc.GetBlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		// the blocks produced before the log bloom is introduced have a zero bloom, which cannot rule them out
+		if height >= bc.config.Chain.ReceiptRootHeight && !filter.MayMatch(&blk.Header.logBloom) {
+			continue
+		}
+		blkHash := blk.HashBlock()
+		for _, execution := range blk.Executions {
+			receipt, err := bc.dao.getReceiptByActionHash(execution.Hash())
+			if err != nil {
+				// failed executions do not have receipt
+				continue
+			}
+			for _, log := range receipt.Logs {
+				if !filter.Match(log) {
+					continue
+				}
+				log.BlockNumber = height
+				log.BlockHash = blkHash
+				logs = append(logs, log)
+			}
+		}
+	}
+	return logs, nil
 }
 
 // GetFactory returns the State Factory
@@ -658,11 +888,14 @@ func (bc *blockchain) MintNewBlock(
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
 
-	tsf = append(tsf, action.NewCoinBaseTransfer(big.NewInt(int64(bc.genesis.BlockReward)), producer.RawAddress))
+	coinbase := action.NewCoinBaseTransfer(big.NewInt(int64(bc.genesis.BlockReward)), producer.RawAddress)
+	tsf, vote, executions, actions = bc.pickActions(coinbase, tsf, vote, executions, actions)
 	blk := NewBlock(bc.config.Chain.ID, bc.tipHeight+1, bc.tipHash, bc.now(), tsf, vote, executions, actions)
 	blk.Header.DKGID = []byte{}
 	blk.Header.DKGPubkey = []byte{}
 	blk.Header.DKGBlockSig = []byte{}
+	// the producer is paid for gas fees in running actions, before the block is signed
+	blk.Header.Pubkey = producer.PublicKey
 	// run execution and update state trie root hash
 	ws, err := bc.sf.NewWorkingSet()
 	if err != nil {
@@ -673,6 +906,7 @@ func (bc *blockchain) MintNewBlock(
 		return nil, errors.Wrapf(err, "Failed to update state changes in new block %d", blk.Height())
 	}
 	blk.Header.stateRoot = root
+	bc.setReceiptRoot(blk)
 	if err := blk.SignBlock(producer); err != nil {
 		return blk, err
 	}
@@ -681,6 +915,16 @@ func (bc *blockchain) MintNewBlock(
 	return blk, nil
 }
 
+// setReceiptRoot sets the receipt root and log bloom of the receipts produced in running the block into its header.
+// The blocks lower than chain.receiptRootHeight are produced without them
+func (bc *blockchain) setReceiptRoot(blk *Block) {
+	if blk.Height() < bc.config.Chain.ReceiptRootHeight {
+		return
+	}
+	blk.Header.receiptRoot = blk.ReceiptRoot()
+	blk.Header.logBloom = blk.LogBloom()
+}
+
 // MintNewDKGBlock creates a new block with given actions and dkg keys
 // Note: the coinbase transfer will be added to the given transfers
 // when minting a new block
@@ -697,7 +941,8 @@ func (bc *blockchain) MintNewDKGBlock(
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
 
-	tsf = append(tsf, action.NewCoinBaseTransfer(big.NewInt(int64(bc.genesis.BlockReward)), producer.RawAddress))
+	coinbase := action.NewCoinBaseTransfer(big.NewInt(int64(bc.genesis.BlockReward)), producer.RawAddress)
+	tsf, vote, executions, actions = bc.pickActions(coinbase, tsf, vote, executions, actions)
 	blk := NewBlock(bc.config.Chain.ID, bc.tipHeight+1, bc.tipHash, bc.now(), tsf, vote, executions, actions)
 	blk.Header.DKGID = []byte{}
 	blk.Header.DKGPubkey = []byte{}
@@ -710,6 +955,8 @@ func (bc *blockchain) MintNewDKGBlock(
 			return nil, errors.Wrap(err, "Failed to do DKG sign")
 		}
 	}
+	// the producer is paid for gas fees in running actions, before the block is signed
+	blk.Header.Pubkey = producer.PublicKey
 	// run execution and update state trie root hash
 	ws, err := bc.sf.NewWorkingSet()
 	if err != nil {
@@ -720,6 +967,7 @@ func (bc *blockchain) MintNewDKGBlock(
 		return nil, errors.Wrapf(err, "Failed to update state changes in new DKG block %d", blk.Height())
 	}
 	blk.Header.stateRoot = root
+	bc.setReceiptRoot(blk)
 	if err := blk.SignBlock(producer); err != nil {
 		return blk, err
 	}
@@ -738,6 +986,8 @@ func (bc *blockchain) MintNewSecretBlock(
 	defer bc.mu.RUnlock()
 
 	blk := NewSecretBlock(bc.config.Chain.ID, bc.tipHeight+1, bc.tipHash, bc.now(), secretProposals, secretWitness)
+	// the producer is paid for gas fees in running actions, before the block is signed
+	blk.Header.Pubkey = producer.PublicKey
 	// run execution and update state trie root hash
 	ws, err := bc.sf.NewWorkingSet()
 	if err != nil {
@@ -748,6 +998,7 @@ func (bc *blockchain) MintNewSecretBlock(
 		return nil, errors.Wrapf(err, "Failed to update state changes in new block %d", blk.Height())
 	}
 	blk.Header.stateRoot = root
+	bc.setReceiptRoot(blk)
 	if err := blk.SignBlock(producer); err != nil {
 		return blk, err
 	}
@@ -787,6 +1038,63 @@ func (bc *blockchain) CommitBlock(blk *Block) error {
 	return bc.commitBlock(blk)
 }
 
+// RollbackTo rolls the chain and the states back to a given height, and deletes all blocks after it
+func (bc *blockchain) RollbackTo(height uint64) error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if height > bc.tipHeight {
+		return errors.Errorf("cannot roll back to height %d higher than the tip height %d", height, bc.tipHeight)
+	}
+	// the states of past heights are needed to restore the states
+	if bc.sf != nil && !bc.config.Chain.EnableHistoryState {
+		factoryHeight, err := bc.sf.Height()
+		if err != nil {
+			return errors.Wrap(err, "failed to get factory's height")
+		}
+		if factoryHeight > height {
+			return errors.Wrapf(state.ErrHistoryStateNotEnabled, "failed to roll back states to height %d", height)
+		}
+	}
+	// the height is recorded first, so that an interrupted rollback is resumed when the blockchain starts next time,
+	// before the deleted blocks are replayed to the rolled back states
+	if err := bc.dao.putRollbackHeight(height); err != nil {
+		return err
+	}
+	return bc.rollbackTo(height)
+}
+
+// rollbackTo rolls the states and then the blocks back to the given height, and clears the ongoing rollback
+func (bc *blockchain) rollbackTo(height uint64) error {
+	// roll back the states first, so that no block is deleted if the states cannot be restored
+	if bc.sf != nil {
+		factoryHeight, err := bc.sf.Height()
+		if err != nil {
+			return errors.Wrap(err, "failed to get factory's height")
+		}
+		if factoryHeight > height {
+			if err := bc.sf.RollbackTo(height); err != nil {
+				return errors.Wrapf(err, "failed to roll back states to height %d", height)
+			}
+		}
+	}
+	for bc.tipHeight > height {
+		if err := bc.dao.deleteTipBlock(); err != nil {
+			return errors.Wrapf(err, "failed to delete block %d", bc.tipHeight)
+		}
+		bc.tipHeight--
+	}
+	tipHash, err := bc.dao.getBlockHash(bc.tipHeight)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the hash of block %d", bc.tipHeight)
+	}
+	bc.tipHash = tipHash
+	if err := bc.dao.deleteRollbackHeight(); err != nil {
+		return err
+	}
+	logger.Info().Uint64("height", bc.tipHeight).Msg("Rolled back blockchain")
+	return nil
+}
+
 // StateByAddr returns the state of an address
 func (bc *blockchain) StateByAddr(address string) (*state.State, error) {
 	if bc.sf != nil {
@@ -800,6 +1108,26 @@ func (bc *blockchain) StateByAddr(address string) (*state.State, error) {
 	return nil, errors.New("state factory is nil")
 }
 
+// StateAtHeight returns the state of an address at a given height
+func (bc *blockchain) StateAtHeight(address string, height uint64) (*state.State, error) {
+	if bc.sf == nil {
+		return nil, errors.New("state factory is nil")
+	}
+	if height > bc.TipHeight() {
+		return nil, errors.Errorf("height %d is higher than the tip height %d", height, bc.TipHeight())
+	}
+	return bc.sf.StateAtHeight(address, height)
+}
+
+// BalanceAtHeight returns the balance of an address at a given height
+func (bc *blockchain) BalanceAtHeight(address string, height uint64) (*big.Int, error) {
+	s, err := bc.StateAtHeight(address, height)
+	if err != nil {
+		return nil, err
+	}
+	return s.Balance, nil
+}
+
 // SetValidator sets the current validator object
 func (bc *blockchain) SetValidator(val Validator) {
 	bc.validator = val
@@ -813,34 +1141,249 @@ func (bc *blockchain) Validator() Validator {
 // ExecuteContractRead runs a read-only smart contract operation, this is done off the network since it does not
 // cause any state change
 func (bc *blockchain) ExecuteContractRead(ex *action.Execution) ([]byte, error) {
-	// use latest block as carrier to run the offline execution
+	receipt, err := bc.executeOffline(ex)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run execution in ExecuteContractRead")
+	}
+	return receipt.ReturnValue, nil
+}
+
+// EstimateGas returns the minimal gas limit with which the execution succeeds, by running it off the network with
+// different gas limits in a binary search. The gas limit, gas price and nonce of the given execution are not used
+func (bc *blockchain) EstimateGas(ex *action.Execution) (uint64, error) {
+	// the execution with the block gas limit must succeed, and it consumes no less gas than the minimal gas limit
+	maxGas := bc.config.Chain.BlockGasLimit
+	receipt, err := bc.executeWithGasLimit(ex, maxGas)
+	if err != nil {
+		return 0, err
+	}
+	if receipt.Status != SuccessStatus {
+		return 0, errors.Errorf("execution fails with the max gas limit %d", maxGas)
+	}
+	low, high := receipt.GasConsumed, maxGas
+	if low > 0 {
+		low--
+	}
+	// the execution fails with gas limit low, and succeeds with gas limit high
+	for low+1 < high {
+		mid := low + (high-low)/2
+		receipt, err := bc.executeWithGasLimit(ex, mid)
+		if err != nil {
+			return 0, err
+		}
+		if receipt.Status == SuccessStatus {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	return high, nil
+}
+
+// TraceExecution re-runs an execution in the chain off the network, and returns the opcode-level trace of it. The
+// executions before it in the same block are re-run on the states of the previous height, which requires history
+// state to be enabled unless the previous height is the tip
+func (bc *blockchain) TraceExecution(h hash.Hash32B) (*ExecutionTrace, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, errors.New("explorer not enabled")
+	}
+	blkHash, err := bc.dao.getBlockHashByExecutionHash(h)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := bc.dao.getBlock(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	if blk.Height() == 0 {
+		return nil, errors.Errorf("execution %x in genesis block cannot be traced", h)
+	}
+	ws, err := bc.sf.NewWorkingSetAtHeight(blk.Height() - 1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to obtain working set on height %d", blk.Height()-1)
+	}
+	return traceExecution(blk, ws, bc, h, bc.config)
+}
+
+//======================================
+// private functions
+//=====================================
+
+// pickActions picks the actions into a block together with the coinbase transfer, within the block gas limit and the
+// max block size. Actions are picked in the incoming order, and once an action of an account is dropped, the ones of
+// higher nonces of the account are dropped as well, so that the nonces in the block remain consecutive
+func (bc *blockchain) pickActions(
+	coinbase *action.Transfer,
+	tsf []*action.Transfer,
+	vote []*action.Vote,
+	executions []*action.Execution,
+	actions []action.Action,
+) ([]*action.Transfer, []*action.Vote, []*action.Execution, []action.Action) {
+	var acts []action.Action
+	for _, t := range tsf {
+		acts = append(acts, t)
+	}
+	for _, v := range vote {
+		acts = append(acts, v)
+	}
+	for _, e := range executions {
+		acts = append(acts, e)
+	}
+	acts = append(acts, actions...)
+
+	// an action picked before another one of the same account and a lower nonce is dropped is unpicked afterwards
+	gas := uint64(0)
+	size := uint64(proto.Size(coinbase.Proto()))
+	dropped := make(map[string]uint64)
+	var candidates []action.Action
+	for _, act := range acts {
+		if nonce, ok := dropped[act.SrcAddr()]; ok && act.Nonce() >= nonce {
+			continue
+		}
+		actHash := act.Hash()
+		actGas, err := ActionGas(act)
+		actSize := uint64(proto.Size(act.Proto()))
+		if err != nil || gas+actGas < gas || gas+actGas > bc.config.Chain.BlockGasLimit ||
+			size+actSize > bc.config.Chain.MaxBlockSize {
+			logger.Debug().Hex("hash", actHash[:]).Msg("Drop the action exceeding the block limit")
+			dropped[act.SrcAddr()] = act.Nonce()
+			continue
+		}
+		gas += actGas
+		size += actSize
+		candidates = append(candidates, act)
+	}
+	picked := make(map[hash.Hash32B]bool)
+	for _, act := range candidates {
+		if nonce, ok := dropped[act.SrcAddr()]; ok && act.Nonce() >= nonce {
+			continue
+		}
+		picked[act.Hash()] = true
+	}
+
+	var pickedTsf []*action.Transfer
+	for _, t := range tsf {
+		if picked[t.Hash()] {
+			pickedTsf = append(pickedTsf, t)
+		}
+	}
+	var pickedVote []*action.Vote
+	for _, v := range vote {
+		if picked[v.Hash()] {
+			pickedVote = append(pickedVote, v)
+		}
+	}
+	var pickedExecutions []*action.Execution
+	for _, e := range executions {
+		if picked[e.Hash()] {
+			pickedExecutions = append(pickedExecutions, e)
+		}
+	}
+	var pickedActions []action.Action
+	for _, act := range actions {
+		if picked[act.Hash()] {
+			pickedActions = append(pickedActions, act)
+		}
+	}
+	return append(pickedTsf, coinbase), pickedVote, pickedExecutions, pickedActions
+}
+
+// logHeights returns the heights of the blocks which may have logs satisfying the filter, up to height to. With the
+// log index, these are the heights indexed for any address and, for each topic position, any topic of the filter
+func (bc *blockchain) logHeights(filter *LogFilter, to uint64) ([]uint64, error) {
+	var criteria [][][]byte
+	if len(filter.Addresses) > 0 {
+		var keys [][]byte
+		for _, addr := range filter.Addresses {
+			keys = append(keys, logAddressKey(addr))
+		}
+		criteria = append(criteria, keys)
+	}
+	for _, topics := range filter.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		var keys [][]byte
+		for _, topic := range topics {
+			keys = append(keys, logTopicKey(topic))
+		}
+		criteria = append(criteria, keys)
+	}
+	if !bc.config.Explorer.Enabled || len(criteria) == 0 {
+		heights := make([]uint64, 0, to-filter.FromHeight+1)
+		for h := filter.FromHeight; h <= to; h++ {
+			heights = append(heights, h)
+		}
+		return heights, nil
+	}
+
+	// count[h] is the number of criteria satisfied by the block at height h
+	count := make(map[uint64]int)
+	for _, keys := range criteria {
+		satisfied := make(map[uint64]struct{})
+		for _, key := range keys {
+			heights, err := bc.dao.getLogHeights(key, filter.FromHeight, to)
+			if err != nil {
+				return nil, err
+			}
+			for _, h := range heights {
+				satisfied[h] = struct{}{}
+			}
+		}
+		for h := range satisfied {
+			count[h]++
+		}
+	}
+	heights := []uint64{}
+	for h, c := range count {
+		if c == len(criteria) {
+			heights = append(heights, h)
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
+
+// executeWithGasLimit runs a copy of the execution with the given gas limit off the network. The nonce of the copy is
+// the one next to the executor's confirmed nonce, so that the execution is not rejected for stale nonce, and the gas
+// price is zero, so that the executor does not need to afford the gas deposit of a large gas limit
+func (bc *blockchain) executeWithGasLimit(ex *action.Execution, gasLimit uint64) (*Receipt, error) {
+	nonce, err := bc.Nonce(ex.Executor())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get nonce of executor %s", ex.Executor())
+	}
+	copied, err := action.NewExecution(
+		ex.Executor(), ex.Contract(), nonce+1, ex.Amount(), gasLimit, big.NewInt(0), ex.Data())
+	if err != nil {
+		return nil, err
+	}
+	return bc.executeOffline(copied)
+}
+
+// executeOffline runs the execution against a throwaway working set, using the latest block as the carrier, and
+// returns the receipt. No state change is committed
+func (bc *blockchain) executeOffline(ex *action.Execution) (*Receipt, error) {
 	// the block itself is not used
 	h := bc.TipHeight()
 	blk, err := bc.GetBlockByHeight(h)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get block in ExecuteContractRead")
+		return nil, errors.Wrapf(err, "failed to get block %d", h)
 	}
-	blk.Executions = nil
 	blk.Executions = []*action.Execution{ex}
 	blk.receipts = nil
 	ws, err := bc.sf.NewWorkingSet()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to obtain working set from state factory")
 	}
-	ExecuteContracts(blk, ws, bc)
+	ExecuteContracts(blk, ws, bc, bc.config)
 	// pull the results from receipt
-	exHash := ex.Hash()
-	receipt, ok := blk.receipts[exHash]
+	receipt, ok := blk.receipts[ex.Hash()]
 	if !ok {
-		return nil, errors.Wrap(err, "failed to get receipt in ExecuteContractRead")
+		return nil, errors.Errorf("failed to get receipt of execution %x", ex.Hash())
 	}
-	return receipt.ReturnValue, nil
+	return receipt, nil
 }
 
-//======================================
-// private functions
-//=====================================
-
 func (bc *blockchain) validateBlock(blk *Block, containCoinbase bool) error {
 	if bc.validator == nil {
 		logger.Panic().Msg("no block validator")
@@ -861,7 +1404,15 @@ func (bc *blockchain) validateBlock(blk *Block, containCoinbase bool) error {
 	}
 	// TODO: disable validation before resolve the state root doesn't match issue
 	if _, err := bc.runActions(blk, ws, false); err != nil {
-		logger.Panic().Err(err).Msgf("Failed to update state on height %d", tipHeight)
+		return errors.Wrapf(err, "Failed to update state on height %d", blk.Height())
+	}
+	if !blk.IsDummyBlock() && blk.Height() >= bc.config.Chain.ReceiptRootHeight {
+		if err := verifyReceiptRoot(blk); err != nil {
+			return errors.Wrapf(err, "Failed to verify receipts on height %d", blk.Height())
+		}
+	} else if blk.Header.logBloom != (LogBloom{}) {
+		// the log bloom of a block lower than chain.receiptRootHeight is not part of the block hash
+		return errors.Wrapf(ErrInvalidBlock, "unexpected log bloom on height %d", blk.Height())
 	}
 	// attach working set to be committed to state factory
 	blk.workingSet = ws
@@ -900,12 +1451,27 @@ func (bc *blockchain) runActions(blk *Block, ws state.WorkingSet, verify bool) (
 	}
 	// run executions
 	if blk.Executions != nil {
-		ExecuteContracts(blk, ws, bc)
+		ExecuteContracts(blk, ws, bc, bc.config)
+	}
+	// charge gas fees of transfers and votes right before running them
+	gasFee := blk.Height() > 0 && blk.Height() >= bc.config.Chain.GasFeeHeight
+	if gasFee {
+		ws.EnableGasFees(blk.ProducerAddress())
 	}
 	// update state factory
 	if root, err = ws.RunActions(blk.Height(), blk.Transfers, blk.Votes, blk.Executions, blk.Actions); err != nil {
 		return root, err
 	}
+	if err = putActionReceipts(blk, gasFee); err != nil {
+		return root, err
+	}
+	// mark the receipts of the actions failed in the state factory
+	for h, actErr := range ws.FailedActions() {
+		if receipt, ok := blk.receipts[h]; ok {
+			receipt.Status = FailureStatus
+			receipt.ErrorCode = errorCode(actErr)
+		}
+	}
 	if verify {
 		// verify state root hash match
 		if err = blk.VerifyStateRoot(root); err != nil {
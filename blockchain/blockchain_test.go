@@ -17,6 +17,8 @@ import (
 	"time"
 
 	"github.com/facebookgo/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/action"
@@ -38,6 +40,7 @@ const (
 )
 
 func addTestingTsfBlocks(bc Blockchain) error {
+	// the accounts are funded with the gas fees of the actions they send, which are 100000 each
 	// Add block 0
 	tsf0, _ := action.NewTransfer(
 		1,
@@ -66,15 +69,15 @@ func addTestingTsfBlocks(bc Blockchain) error {
 	}
 	// Add block 1
 	// test --> A, B, C, D, E, F
-	tsf1, _ := action.NewTransfer(1, big.NewInt(20), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	tsf1, _ := action.NewTransfer(1, big.NewInt(100020), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	_ = action.Sign(tsf1, ta.Addrinfo["producer"].PrivateKey)
 	tsf2, _ := action.NewTransfer(2, big.NewInt(30), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["bravo"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	_ = action.Sign(tsf2, ta.Addrinfo["producer"].PrivateKey)
-	tsf3, _ := action.NewTransfer(3, big.NewInt(50), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["charlie"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	tsf3, _ := action.NewTransfer(3, big.NewInt(600050), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["charlie"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	_ = action.Sign(tsf3, ta.Addrinfo["producer"].PrivateKey)
-	tsf4, _ := action.NewTransfer(4, big.NewInt(70), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["delta"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	tsf4, _ := action.NewTransfer(4, big.NewInt(400070), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["delta"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	_ = action.Sign(tsf4, ta.Addrinfo["producer"].PrivateKey)
-	tsf5, _ := action.NewTransfer(5, big.NewInt(110), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["echo"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	tsf5, _ := action.NewTransfer(5, big.NewInt(600110), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["echo"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	_ = action.Sign(tsf5, ta.Addrinfo["producer"].PrivateKey)
 	tsf6, _ := action.NewTransfer(6, big.NewInt(50<<20), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["foxtrot"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	_ = action.Sign(tsf6, ta.Addrinfo["producer"].PrivateKey)
@@ -393,6 +396,10 @@ func TestLoadBlockchainfromDB(t *testing.T) {
 		transfer1, err := bc.GetTransferByTransferHash(transferHash)
 		require.Nil(err)
 		require.Equal(transfer1.Hash(), transferHash)
+		proof, err := bc.GetActionProof(transferHash)
+		require.Nil(err)
+		require.Equal(hash5, proof.BlockHeader.HashHeader())
+		require.True(proof.Verify())
 	}
 
 	for _, vote := range blk.Votes {
@@ -403,6 +410,10 @@ func TestLoadBlockchainfromDB(t *testing.T) {
 		vote1, err := bc.GetVoteByVoteHash(voteHash)
 		require.Nil(err)
 		require.Equal(vote1.Hash(), voteHash)
+		proof, err := bc.GetActionProof(voteHash)
+		require.Nil(err)
+		require.Equal(hash5, proof.BlockHeader.HashHeader())
+		require.True(proof.Verify())
 	}
 
 	fromTransfers, err := bc.GetTransfersFromAddress(ta.Addrinfo["charlie"].RawAddress)
@@ -595,6 +606,8 @@ func TestLoadBlockchainfromDBWithoutExplorer(t *testing.T) {
 		require.NotNil(err)
 		_, err = bc.GetVoteByVoteHash(voteHash)
 		require.NotNil(err)
+		_, err = bc.GetActionProof(voteHash)
+		require.NotNil(err)
 	}
 	_, err = bc.GetTransfersFromAddress(ta.Addrinfo["charlie"].RawAddress)
 	require.NotNil(err)
@@ -650,7 +663,11 @@ func TestBlockchain_MintNewDummyBlock(t *testing.T) {
 	sf, err := state.NewFactory(cfg, state.DefaultTrieOption())
 	require.NoError(err)
 	require.NoError(sf.Start(context.Background()))
-	val := validator{sf, ""}
+	val := validator{
+		sf:           sf,
+		gasLimit:     config.Default.Chain.BlockGasLimit,
+		maxBlockSize: config.Default.Chain.MaxBlockSize,
+	}
 
 	ctx := context.Background()
 	bc := NewBlockchain(cfg, InMemDaoOption(), InMemStateFactoryOption())
@@ -674,6 +691,78 @@ func TestBlockchain_MintNewDummyBlock(t *testing.T) {
 	)
 }
 
+func TestBlockchain_MintNewBlockWithinLimits(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	cfg := config.Default
+	// room for 3 transfers or votes without payload
+	cfg.Chain.BlockGasLimit = 3 * action.TransferBaseIntrinsicGas
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	producer := ta.Addrinfo["producer"]
+	alfa := ta.Addrinfo["alfa"]
+	_, err := bc.CreateState(producer.RawAddress, 100)
+	require.NoError(err)
+	_, err = bc.CreateState(alfa.RawAddress, 100)
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(bc.GetFactory().Commit(nil))
+
+	var tsfs []*action.Transfer
+	for nonce := uint64(1); nonce <= 5; nonce++ {
+		tsf, err := action.NewTransfer(nonce, big.NewInt(1), producer.RawAddress, alfa.RawAddress, []byte{},
+			uint64(100000), big.NewInt(0))
+		require.NoError(err)
+		require.NoError(action.Sign(tsf, producer.PrivateKey))
+		tsfs = append(tsfs, tsf)
+	}
+	vote, err := action.NewVote(1, alfa.RawAddress, alfa.RawAddress, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(action.Sign(vote, alfa.PrivateKey))
+	alfaTsf, err := action.NewTransfer(2, big.NewInt(1), alfa.RawAddress, producer.RawAddress, []byte{},
+		uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(action.Sign(alfaTsf, alfa.PrivateKey))
+
+	// actions are picked in the incoming order until the limits are reached
+	blk, err := bc.MintNewBlock(tsfs[:4], []*action.Vote{vote}, nil, nil, producer, "")
+	require.NoError(err)
+	require.Equal(tsfs[:3], blk.Transfers[:3])
+	require.True(blk.Transfers[3].IsCoinbase())
+	require.Empty(blk.Votes)
+	require.NoError(bc.ValidateBlock(blk, true))
+	require.NoError(bc.CommitBlock(blk))
+
+	// the actions of an account picked before a dropped one of a lower nonce are dropped as well
+	blk, err = bc.MintNewBlock(
+		[]*action.Transfer{tsfs[3], alfaTsf, tsfs[4]}, []*action.Vote{vote}, nil, nil, producer, "")
+	require.NoError(err)
+	require.Equal([]*action.Transfer{tsfs[3], tsfs[4]}, blk.Transfers[:2])
+	require.True(blk.Transfers[2].IsCoinbase())
+	require.Empty(blk.Votes)
+	require.NoError(bc.ValidateBlock(blk, true))
+	require.NoError(bc.CommitBlock(blk))
+
+	// a block exceeding the limits is rejected
+	val := &validator{gasLimit: action.TransferBaseIntrinsicGas, maxBlockSize: cfg.Chain.MaxBlockSize}
+	blk = NewBlock(cfg.Chain.ID, 3, blk.HashBlock(), 0, tsfs[2:4], nil, nil, nil)
+	err = val.verifyLimits(blk)
+	require.Equal(ErrExceedBlockLimit, errors.Cause(err))
+	// unless it is lower than the limit height
+	val.limitHeight = 4
+	require.NoError(val.verifyLimits(blk))
+	val = &validator{gasLimit: cfg.Chain.BlockGasLimit, maxBlockSize: uint64(proto.Size(tsfs[2].Proto()))}
+	err = val.verifyLimits(blk)
+	require.Equal(ErrExceedBlockLimit, errors.Cause(err))
+	val = &validator{gasLimit: 2 * action.TransferBaseIntrinsicGas, maxBlockSize: blk.actionsSize()}
+	require.NoError(val.verifyLimits(blk))
+}
+
 func TestBlockchainInitialCandidate(t *testing.T) {
 	require := require.New(t)
 
@@ -773,6 +862,113 @@ func TestBlockchain_StateByAddr(t *testing.T) {
 	require.Equal(map[string]*big.Int(map[string]*big.Int(nil)), s.Voters)
 }
 
+func TestBlockchain_RollbackTo(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Disable block reward to make bookkeeping easier
+	Gen.BlockReward = uint64(0)
+	cfg := config.Default
+	cfg.Chain.EnableHistoryState = true
+	cfg.Explorer.Enabled = true
+	sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(ctx))
+	bc := NewBlockchain(&cfg, PrecreatedStateFactoryOption(sf), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	require.NoError(addTestingTsfBlocks(bc))
+	require.Equal(uint64(5), bc.TipHeight())
+
+	hash2, err := bc.GetHashByHeight(2)
+	require.NoError(err)
+	balance2, err := bc.BalanceAtHeight(ta.Addrinfo["charlie"].RawAddress, 2)
+	require.NoError(err)
+	blk, err := bc.GetBlockByHeight(3)
+	require.NoError(err)
+	tsfHash := blk.Transfers[0].Hash()
+
+	require.Error(bc.RollbackTo(6))
+	require.NoError(bc.RollbackTo(2))
+	require.Equal(uint64(2), bc.TipHeight())
+	require.Equal(hash2, bc.TipHash())
+	height, err := sf.Height()
+	require.NoError(err)
+	require.Equal(uint64(2), height)
+	balance, err := bc.Balance(ta.Addrinfo["charlie"].RawAddress)
+	require.NoError(err)
+	require.Equal(balance2, balance)
+	_, err = bc.GetBlockByHeight(3)
+	require.Error(err)
+	_, err = bc.GetTransferByTransferHash(tsfHash)
+	require.Error(err)
+
+	// the rolled back chain can grow again
+	blk, err = bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.NoError(bc.ValidateBlock(blk, true))
+	require.NoError(bc.CommitBlock(blk))
+	require.Equal(uint64(3), bc.TipHeight())
+}
+
+func TestBlockchain_ResumeRollback(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	testutil.CleanupPath(t, testTriePath)
+	defer testutil.CleanupPath(t, testTriePath)
+	testutil.CleanupPath(t, testDBPath)
+	defer testutil.CleanupPath(t, testDBPath)
+
+	// Disable block reward to make bookkeeping easier
+	Gen.BlockReward = uint64(0)
+	cfg := config.Default
+	cfg.Chain.TrieDBPath = testTriePath
+	cfg.Chain.ChainDBPath = testDBPath
+	cfg.Explorer.Enabled = true
+
+	// the states cannot be rolled back without history
+	bc := NewBlockchain(&cfg, DefaultStateFactoryOption(), BoltDBDaoOption())
+	require.NoError(bc.Start(ctx))
+	require.NoError(addTestingTsfBlocks(bc))
+	require.Equal(state.ErrHistoryStateNotEnabled, errors.Cause(bc.RollbackTo(2)))
+	require.Equal(uint64(5), bc.TipHeight())
+	require.NoError(bc.Stop(ctx))
+	testutil.CleanupPath(t, testTriePath)
+	testutil.CleanupPath(t, testDBPath)
+
+	cfg.Chain.EnableHistoryState = true
+	bc = NewBlockchain(&cfg, DefaultStateFactoryOption(), BoltDBDaoOption())
+	require.NoError(bc.Start(ctx))
+	require.NoError(addTestingTsfBlocks(bc))
+	hash2, err := bc.GetHashByHeight(2)
+	require.NoError(err)
+	balance2, err := bc.BalanceAtHeight(ta.Addrinfo["charlie"].RawAddress, 2)
+	require.NoError(err)
+	// the rollback is interrupted after the states are rolled back
+	chain := bc.(*blockchain)
+	require.NoError(chain.dao.putRollbackHeight(2))
+	require.NoError(chain.sf.RollbackTo(2))
+	require.NoError(bc.Stop(ctx))
+
+	// the rollback is resumed instead of replaying the blocks to be deleted
+	bc = NewBlockchain(&cfg, DefaultStateFactoryOption(), BoltDBDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	require.Equal(uint64(2), bc.TipHeight())
+	require.Equal(hash2, bc.TipHash())
+	balance, err := bc.Balance(ta.Addrinfo["charlie"].RawAddress)
+	require.NoError(err)
+	require.Equal(balance2, balance)
+	_, ok, err := bc.(*blockchain).dao.getRollbackHeight()
+	require.NoError(err)
+	require.False(ok)
+}
+
 func TestBlocks(t *testing.T) {
 	// This test is used for committing block verify benchmark purpose
 	t.Skip()
@@ -839,7 +1035,11 @@ func TestActions(t *testing.T) {
 	sf.LoadOrCreateState(a.RawAddress, uint64(100000))
 	sf.LoadOrCreateState(c.RawAddress, uint64(100000))
 
-	val := validator{sf, ""}
+	val := validator{
+		sf:           sf,
+		gasLimit:     config.Default.Chain.BlockGasLimit,
+		maxBlockSize: config.Default.Chain.MaxBlockSize,
+	}
 	tsfs := []*action.Transfer{}
 	votes := []*action.Vote{}
 	for i := 0; i < 5000; i++ {
@@ -1025,3 +1225,51 @@ func TestMintDKGBlock(t *testing.T) {
 	require.NoError(err)
 	require.Equal(21, len(candidates))
 }
+
+func TestBlockchain_ReceiptRootHeight(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	cfg := config.Default
+	cfg.Chain.ReceiptRootHeight = 3
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	require.NoError(addTestingTsfBlocks(bc))
+	require.Equal(uint64(5), bc.TipHeight())
+	for height := uint64(1); height <= 5; height++ {
+		blk, err := bc.GetBlockByHeight(height)
+		require.NoError(err)
+		if height < cfg.Chain.ReceiptRootHeight {
+			require.Equal(hash.ZeroHash32B, blk.Header.ReceiptRoot())
+			require.Equal(LogBloom{}, blk.Header.LogBloom())
+			// the log bloom is not part of the hash of a block lower than the activation height
+			blkHash := blk.HashBlock()
+			blk.Header.logBloom[0] = 1
+			require.Equal(blkHash, blk.HashBlock())
+		} else {
+			require.NotEqual(hash.ZeroHash32B, blk.Header.ReceiptRoot())
+		}
+	}
+
+	// a block produced before the receipt root is introduced is only valid below the activation height
+	cfg.Chain.ReceiptRootHeight = 7
+	nonce, err := bc.Nonce(ta.Addrinfo["producer"].RawAddress)
+	require.NoError(err)
+	tsf, err := action.NewTransfer(nonce+1, big.NewInt(1), ta.Addrinfo["producer"].RawAddress,
+		ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(action.Sign(tsf, ta.Addrinfo["producer"].PrivateKey))
+	blk, err := bc.MintNewBlock([]*action.Transfer{tsf}, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.Equal(hash.ZeroHash32B, blk.Header.ReceiptRoot())
+	require.NoError(bc.ValidateBlock(blk, true))
+	// and without a log bloom
+	blk.Header.logBloom[0] = 1
+	require.Error(bc.ValidateBlock(blk, true))
+	blk.Header.logBloom = LogBloom{}
+	cfg.Chain.ReceiptRootHeight = 6
+	require.Error(bc.ValidateBlock(blk, true))
+}
@@ -8,9 +8,12 @@ package blockchain
 
 import (
 	"context"
+	"encoding/hex"
+	"sort"
 
 	"github.com/pkg/errors"
 
+	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/pkg/enc"
@@ -25,6 +28,7 @@ const (
 	blockTransferBlockMappingNS         = "transfer<->block"
 	blockVoteBlockMappingNS             = "vote<->block"
 	blockExecutionBlockMappingNS        = "execution<->block"
+	blockActionBlockMappingNS           = "action<->block"
 	blockExecutionReceiptMappingNS      = "ex<->receipt"
 	blockAddressTransferMappingNS       = "address<->transfer"
 	blockAddressTransferCountMappingNS  = "address<->transfercount"
@@ -32,6 +36,10 @@ const (
 	blockAddressVoteCountMappingNS      = "address<->votecount"
 	blockAddressExecutionMappingNS      = "address<->execution"
 	blockAddressExecutionCountMappingNS = "address<->executioncount"
+	blockAddressActionMappingNS         = "address<->action"
+	blockAddressActionCountMappingNS    = "address<->actioncount"
+	blockLogHeightMappingNS             = "log<->height"
+	blockLogHeightCountMappingNS        = "log<->heightcount"
 )
 
 var (
@@ -39,9 +47,12 @@ var (
 	transferPrefix  = []byte("transfer.")
 	votePrefix      = []byte("vote.")
 	executionPrefix = []byte("execution.")
+	actionPrefix    = []byte("action.")
 	heightPrefix    = []byte("height.")
 	// mutate this field is not thread safe, pls only mutate it in putBlock!
 	topHeightKey = []byte("top-height")
+	// rollbackHeightKey is the key of the height being rolled back to, which exists only while a rollback is ongoing
+	rollbackHeightKey = []byte("rollback-height")
 	// mutate this field is not thread safe, pls only mutate it in putBlock!
 	totalTransfersKey   = []byte("total-transfers")
 	totalVotesKey       = []byte("total-votes")
@@ -52,6 +63,10 @@ var (
 	voteToPrefix        = []byte("vote-to.")
 	executionFromPrefix = []byte("execution-from")
 	executionToPrefix   = []byte("execution-to")
+	actionFromPrefix    = []byte("action-from.")
+	actionToPrefix      = []byte("action-to.")
+	logAddressPrefix    = []byte("log-address.")
+	logTopicPrefix      = []byte("log-topic.")
 )
 
 var _ lifecycle.StartStopper = (*blockDAO)(nil)
@@ -193,6 +208,32 @@ func (dao *blockDAO) getBlockHashByExecutionHash(h hash.Hash32B) (hash.Hash32B,
 	return blkHash, nil
 }
 
+// getBlockHashByActionHash returns the block hash by the hash of a tx or an action of any type. The blocks committed
+// before the action index was introduced are only indexed by the hashes of transfers, votes and executions, which are
+// looked up if the action index misses the hash
+func (dao *blockDAO) getBlockHashByActionHash(h hash.Hash32B) (hash.Hash32B, error) {
+	blkHash := hash.ZeroHash32B
+	key := append(actionPrefix, h[:]...)
+	value, err := dao.kvstore.Get(blockActionBlockMappingNS, key)
+	if err == nil && len(value) == 0 {
+		err = errors.Wrapf(db.ErrNotExist, "action %x missing", h)
+	}
+	if err == nil {
+		copy(blkHash[:], value)
+		return blkHash, nil
+	}
+	for _, getBlockHash := range []func(hash.Hash32B) (hash.Hash32B, error){
+		dao.getBlockHashByTransferHash,
+		dao.getBlockHashByVoteHash,
+		dao.getBlockHashByExecutionHash,
+	} {
+		if legacyHash, legacyErr := getBlockHash(h); legacyErr == nil {
+			return legacyHash, nil
+		}
+	}
+	return blkHash, errors.Wrapf(err, "failed to get action %x", h)
+}
+
 // getTransfersBySenderAddress returns transfers for sender
 func (dao *blockDAO) getTransfersBySenderAddress(address string) ([]hash.Hash32B, error) {
 	// get transfers count for sender
@@ -434,6 +475,233 @@ func (dao *blockDAO) getExecutionCountByContractAddress(address string) (uint64,
 	return enc.MachineEndian.Uint64(value), nil
 }
 
+// HistoryQuery is the query of a page of the actions of an address. Actions are returned in reverse chronological
+// order, starting from the one right before Cursor, or from the latest one if Cursor is empty. The cursor of the next
+// page is returned along with the page. Zero value of a height or time bound means unbounded
+type HistoryQuery struct {
+	Cursor      string
+	Limit       uint64
+	StartHeight uint64
+	EndHeight   uint64
+	StartTime   uint64
+	EndTime     uint64
+}
+
+// addressIndex is the index of the actions of addresses in one role, which are numbered in chronological order
+type addressIndex struct {
+	ns          string // namespace of address -> action hash mapping
+	countNS     string // namespace of address -> action count mapping
+	prefix      []byte // key prefix of the role
+	blockNS     string // namespace of action hash -> block hash mapping
+	blockPrefix []byte // key prefix of action hash -> block hash mapping
+}
+
+var (
+	transferIndices = []addressIndex{
+		{blockAddressTransferMappingNS, blockAddressTransferCountMappingNS, transferFromPrefix,
+			blockTransferBlockMappingNS, transferPrefix},
+		{blockAddressTransferMappingNS, blockAddressTransferCountMappingNS, transferToPrefix,
+			blockTransferBlockMappingNS, transferPrefix},
+	}
+	voteIndices = []addressIndex{
+		{blockAddressVoteMappingNS, blockAddressVoteCountMappingNS, voteFromPrefix, blockVoteBlockMappingNS, votePrefix},
+		{blockAddressVoteMappingNS, blockAddressVoteCountMappingNS, voteToPrefix, blockVoteBlockMappingNS, votePrefix},
+	}
+	executionIndices = []addressIndex{
+		{blockAddressExecutionMappingNS, blockAddressExecutionCountMappingNS, executionFromPrefix,
+			blockExecutionBlockMappingNS, executionPrefix},
+		{blockAddressExecutionMappingNS, blockAddressExecutionCountMappingNS, executionToPrefix,
+			blockExecutionBlockMappingNS, executionPrefix},
+	}
+	actionIndices = []addressIndex{
+		{blockAddressActionMappingNS, blockAddressActionCountMappingNS, actionFromPrefix, blockActionBlockMappingNS,
+			actionPrefix},
+		{blockAddressActionMappingNS, blockAddressActionCountMappingNS, actionToPrefix, blockActionBlockMappingNS,
+			actionPrefix},
+	}
+)
+
+// getActionsByAddress returns a page of the actions of an address in all roles of the indices, merged in reverse
+// chronological order. Only the actions in the page are read from DB, and height and time range is located by
+// binary search, given that actions in an index are in the order of height and timestamp
+func (dao *blockDAO) getActionsByAddress(
+	indices []addressIndex,
+	address string,
+	query *HistoryQuery,
+) ([]hash.Hash32B, string, error) {
+	// cursors[i] is the number of actions in indices[i] which are not returned yet
+	cursors := make([]uint64, len(indices))
+	if query.Cursor != "" {
+		value, err := hex.DecodeString(query.Cursor)
+		if err != nil || len(value) != 8*len(indices) {
+			return nil, "", errors.Errorf("invalid cursor %s", query.Cursor)
+		}
+		for i := range cursors {
+			cursors[i] = byteutil.BytesToUint64(value[8*i : 8*i+8])
+		}
+	}
+	starts := make([]uint64, len(indices))
+	for i := range indices {
+		start, end, err := dao.getIndexRange(&indices[i], address, query)
+		if err != nil {
+			return nil, "", err
+		}
+		starts[i] = start
+		if query.Cursor == "" || cursors[i] > end {
+			cursors[i] = end
+		}
+		if cursors[i] < starts[i] {
+			cursors[i] = starts[i]
+		}
+	}
+
+	res := []hash.Hash32B{}
+	// latest action not returned yet in each index
+	latest := make([]*indexEntry, len(indices))
+	for uint64(len(res)) < query.Limit {
+		next := -1
+		for i := range indices {
+			if cursors[i] == starts[i] {
+				continue
+			}
+			if latest[i] == nil {
+				entry, err := dao.getIndexEntry(&indices[i], address, cursors[i]-1)
+				if err != nil {
+					return nil, "", err
+				}
+				latest[i] = entry
+			}
+			if next < 0 || latest[i].height > latest[next].height {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		res = append(res, latest[next].hash)
+		cursors[next]--
+		latest[next] = nil
+	}
+
+	more := false
+	value := make([]byte, 0, 8*len(cursors))
+	for i := range cursors {
+		more = more || cursors[i] > starts[i]
+		value = append(value, byteutil.Uint64ToBytes(cursors[i])...)
+	}
+	if !more {
+		return res, "", nil
+	}
+	return res, hex.EncodeToString(value), nil
+}
+
+// indexEntry is an action in an address index
+type indexEntry struct {
+	hash   hash.Hash32B
+	height uint64
+}
+
+// getIndexEntry returns the i-th action of an address in the index
+func (dao *blockDAO) getIndexEntry(idx *addressIndex, address string, i uint64) (*indexEntry, error) {
+	key := append(idx.prefix, address...)
+	key = append(key, byteutil.Uint64ToBytes(i)...)
+	value, err := dao.kvstore.Get(idx.ns, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get action for index %x", i)
+	}
+	if len(value) != hash.HashSize {
+		return nil, errors.Wrapf(db.ErrNotExist, "action for index %x missing", i)
+	}
+	entry := &indexEntry{}
+	copy(entry.hash[:], value)
+	blkHash, err := dao.getIndexEntryBlockHash(idx, entry.hash)
+	if err != nil {
+		return nil, err
+	}
+	if entry.height, err = dao.getBlockHeight(blkHash); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// getIndexEntryBlockHash returns the hash of the block containing the action
+func (dao *blockDAO) getIndexEntryBlockHash(idx *addressIndex, h hash.Hash32B) (hash.Hash32B, error) {
+	blkHash := hash.ZeroHash32B
+	value, err := dao.kvstore.Get(idx.blockNS, append(idx.blockPrefix, h[:]...))
+	if err != nil {
+		return blkHash, errors.Wrapf(err, "failed to get block of action %x", h)
+	}
+	if len(value) != hash.HashSize {
+		return blkHash, errors.Wrapf(db.ErrNotExist, "block of action %x missing", h)
+	}
+	copy(blkHash[:], value)
+	return blkHash, nil
+}
+
+// getIndexRange returns the range [start, end) of the actions of an address in the index, which are in the height and
+// time range of the query
+func (dao *blockDAO) getIndexRange(idx *addressIndex, address string, query *HistoryQuery) (uint64, uint64, error) {
+	var count uint64
+	value, err := dao.kvstore.Get(idx.countNS, append(idx.prefix, address...))
+	if err == nil {
+		if len(value) == 0 {
+			return 0, 0, errors.Errorf("count of actions of %s is broken", address)
+		}
+		count = enc.MachineEndian.Uint64(value)
+	}
+	start, end := uint64(0), count
+	if query.StartHeight > 0 || query.StartTime > 0 {
+		// first action not earlier than the start
+		if start, err = dao.searchIndex(idx, address, count, func(blk *Block) bool {
+			return blk.Height() >= query.StartHeight && blk.Header.timestamp >= query.StartTime
+		}); err != nil {
+			return 0, 0, err
+		}
+	}
+	if query.EndHeight > 0 || query.EndTime > 0 {
+		// first action later than the end
+		if end, err = dao.searchIndex(idx, address, count, func(blk *Block) bool {
+			return (query.EndHeight > 0 && blk.Height() > query.EndHeight) ||
+				(query.EndTime > 0 && blk.Header.timestamp > query.EndTime)
+		}); err != nil {
+			return 0, 0, err
+		}
+	}
+	if end < start {
+		end = start
+	}
+	return start, end, nil
+}
+
+// searchIndex returns the first action of an address in the index whose block satisfies pred, or count if none does.
+// pred must be false for some prefix of the actions and true for the remainder
+func (dao *blockDAO) searchIndex(idx *addressIndex, address string, count uint64, pred func(*Block) bool) (uint64, error) {
+	lo, hi := uint64(0), count
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		key := append(idx.prefix, address...)
+		key = append(key, byteutil.Uint64ToBytes(mid)...)
+		value, err := dao.kvstore.Get(idx.ns, key)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to get action for index %x", mid)
+		}
+		blkHash, err := dao.getIndexEntryBlockHash(idx, byteutil.BytesTo32B(value))
+		if err != nil {
+			return 0, err
+		}
+		blk, err := dao.getBlock(blkHash)
+		if err != nil {
+			return 0, err
+		}
+		if pred(blk) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
+
 // getBlockchainHeight returns the blockchain height
 func (dao *blockDAO) getBlockchainHeight() (uint64, error) {
 	value, err := dao.kvstore.Get(blockNS, topHeightKey)
@@ -446,6 +714,34 @@ func (dao *blockDAO) getBlockchainHeight() (uint64, error) {
 	return enc.MachineEndian.Uint64(value), nil
 }
 
+// putRollbackHeight records the height being rolled back to before rolling back the chain
+func (dao *blockDAO) putRollbackHeight(height uint64) error {
+	if err := dao.kvstore.Put(blockNS, rollbackHeightKey, byteutil.Uint64ToBytes(height)); err != nil {
+		return errors.Wrap(err, "failed to put rollback height")
+	}
+	return nil
+}
+
+// getRollbackHeight returns the height being rolled back to if a rollback is ongoing
+func (dao *blockDAO) getRollbackHeight() (uint64, bool, error) {
+	value, err := dao.kvstore.Get(blockNS, rollbackHeightKey)
+	if errors.Cause(err) == db.ErrNotExist {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to get rollback height")
+	}
+	return enc.MachineEndian.Uint64(value), true, nil
+}
+
+// deleteRollbackHeight removes the height being rolled back to after rolling back the chain
+func (dao *blockDAO) deleteRollbackHeight() error {
+	if err := dao.kvstore.Delete(blockNS, rollbackHeightKey); err != nil {
+		return errors.Wrap(err, "failed to delete rollback height")
+	}
+	return nil
+}
+
 // getTotalTransfers returns the total number of transfers
 func (dao *blockDAO) getTotalTransfers() (uint64, error) {
 	value, err := dao.kvstore.Get(blockNS, totalTransfersKey)
@@ -482,11 +778,11 @@ func (dao *blockDAO) getTotalExecutions() (uint64, error) {
 	return enc.MachineEndian.Uint64(value), nil
 }
 
-// getReceiptByExecutionHash returns the receipt by execution hash
-func (dao *blockDAO) getReceiptByExecutionHash(h hash.Hash32B) (*Receipt, error) {
+// getReceiptByActionHash returns the receipt by the hash of an action of any type
+func (dao *blockDAO) getReceiptByActionHash(h hash.Hash32B) (*Receipt, error) {
 	value, err := dao.kvstore.Get(blockExecutionReceiptMappingNS, h[:])
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get receipt for execution %x", h[:])
+		return nil, errors.Wrapf(err, "failed to get receipt for action %x", h[:])
 	}
 	r := Receipt{}
 	if err := r.Deserialize(value); err != nil {
@@ -588,6 +884,10 @@ func (dao *blockDAO) putBlock(blk *Block) error {
 		return err
 	}
 
+	if err = putActions(dao, blk, batch); err != nil {
+		return err
+	}
+
 	return dao.kvstore.Commit(batch)
 }
 
@@ -772,6 +1072,60 @@ func putExecutions(dao *blockDAO, blk *Block, batch db.KVStoreBatch) error {
 	return nil
 }
 
+// putActions stores the index of all txs and actions by hash and by source and destination address into db
+func putActions(dao *blockDAO, blk *Block, batch db.KVStoreBatch) error {
+	blkHash := blk.HashBlock()
+	counts := map[string]uint64{}
+	for _, act := range blk.allActions() {
+		actHash := act.Hash()
+		hashKey := append(actionPrefix, actHash[:]...)
+		batch.Put(blockActionBlockMappingNS, hashKey, blkHash[:], "failed to put action hash %x", actHash)
+
+		for _, key := range actionAddressKeys(act) {
+			count, ok := counts[string(key)]
+			if !ok {
+				var err error
+				if count, err = dao.getActionCount(key); err != nil {
+					return err
+				}
+			}
+			counts[string(key)] = count + 1
+
+			// put new action to address
+			batch.PutIfNotExists(blockAddressActionMappingNS, append(key, byteutil.Uint64ToBytes(count)...),
+				actHash[:], "failed to put action hash %x for %s", actHash, key)
+		}
+	}
+	// update actions count of addresses
+	for key, count := range counts {
+		batch.Put(blockAddressActionCountMappingNS, []byte(key), byteutil.Uint64ToBytes(count),
+			"failed to bump action count for %s", key)
+	}
+	return nil
+}
+
+// actionAddressKeys returns the keys of the source address and, if any, the destination address of an action. An
+// action to its source address is only indexed once
+func actionAddressKeys(act action.Action) [][]byte {
+	keys := [][]byte{append(actionFromPrefix, act.SrcAddr()...)}
+	if act.DstAddr() != "" && act.DstAddr() != act.SrcAddr() {
+		keys = append(keys, append(actionToPrefix, act.DstAddr()...))
+	}
+	return keys
+}
+
+// getActionCount returns the count of actions by the key of an address
+func (dao *blockDAO) getActionCount(key []byte) (uint64, error) {
+	value, err := dao.kvstore.Get(blockAddressActionCountMappingNS, key)
+	if err != nil {
+		return 0, nil
+	}
+	if len(value) == 0 {
+		return 0, errors.Errorf("count of actions of %s is broken", key)
+	}
+	return enc.MachineEndian.Uint64(value), nil
+}
+
 // putReceipts store receipt into db
 func (dao *blockDAO) putReceipts(blk *Block) error {
 	if blk.receipts == nil {
@@ -785,9 +1139,124 @@ func (dao *blockDAO) putReceipts(blk *Block) error {
 		}
 		batch.Put(blockExecutionReceiptMappingNS, r.Hash[:], v[:], "failed to put receipt for execution %x", r.Hash[:])
 	}
+	// only build log index if enable explorer
+	if dao.config.Explorer.Enabled {
+		if err := putLogs(dao, blk, batch); err != nil {
+			return err
+		}
+	}
 	return dao.kvstore.Commit(batch)
 }
 
+// putLogs stores the index of the heights of the blocks with logs by the contract address and by each topic. A block
+// is indexed once for each address and topic of its logs
+func putLogs(dao *blockDAO, blk *Block, batch db.KVStoreBatch) error {
+	for _, key := range logIndexKeys(blk.receipts) {
+		count, err := dao.getLogHeightCount(key)
+		if err != nil {
+			return err
+		}
+		batch.PutIfNotExists(blockLogHeightMappingNS, append(key, byteutil.Uint64ToBytes(count)...),
+			byteutil.Uint64ToBytes(blk.Height()), "failed to put height %d for %s", blk.Height(), key)
+		batch.Put(blockLogHeightCountMappingNS, key, byteutil.Uint64ToBytes(count+1),
+			"failed to bump log height count for %s", key)
+	}
+	return nil
+}
+
+// logIndexKeys returns the distinct keys of the contract addresses and topics of the logs in the receipts, sorted so
+// that the keys of a block are always in the same order
+func logIndexKeys(receipts map[hash.Hash32B]*Receipt) [][]byte {
+	keys := make(map[string]struct{})
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			keys[string(logAddressKey(log.Address))] = struct{}{}
+			for _, topic := range log.Topics {
+				keys[string(logTopicKey(topic))] = struct{}{}
+			}
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	res := make([][]byte, len(sorted))
+	for i, key := range sorted {
+		res[i] = []byte(key)
+	}
+	return res
+}
+
+// logAddressKey returns the log index key of a contract address
+func logAddressKey(address string) []byte {
+	return append(append([]byte{}, logAddressPrefix...), address...)
+}
+
+// logTopicKey returns the log index key of a topic
+func logTopicKey(topic hash.Hash32B) []byte {
+	return append(append([]byte{}, logTopicPrefix...), topic[:]...)
+}
+
+// getLogHeightCount returns the count of blocks with logs by the key of a contract address or topic
+func (dao *blockDAO) getLogHeightCount(key []byte) (uint64, error) {
+	value, err := dao.kvstore.Get(blockLogHeightCountMappingNS, key)
+	if err != nil {
+		return 0, nil
+	}
+	if len(value) == 0 {
+		return 0, errors.Errorf("count of log heights of %s is broken", key)
+	}
+	return enc.MachineEndian.Uint64(value), nil
+}
+
+// getLogHeight returns the i-th height of the blocks with logs by the key of a contract address or topic
+func (dao *blockDAO) getLogHeight(key []byte, i uint64) (uint64, error) {
+	value, err := dao.kvstore.Get(blockLogHeightMappingNS, append(key, byteutil.Uint64ToBytes(i)...))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get log height for index %x", i)
+	}
+	if len(value) != 8 {
+		return 0, errors.Wrapf(db.ErrNotExist, "log height for index %x missing", i)
+	}
+	return enc.MachineEndian.Uint64(value), nil
+}
+
+// getLogHeights returns the heights in range [from, to] of the blocks with logs by the key of a contract address or
+// topic, in ascending order. The range is located by binary search, given that heights are indexed in order
+func (dao *blockDAO) getLogHeights(key []byte, from uint64, to uint64) ([]uint64, error) {
+	count, err := dao.getLogHeightCount(key)
+	if err != nil {
+		return nil, err
+	}
+	// first height not lower than from
+	lo, hi := uint64(0), count
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		height, err := dao.getLogHeight(key, mid)
+		if err != nil {
+			return nil, err
+		}
+		if height >= from {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	heights := []uint64{}
+	for i := lo; i < count; i++ {
+		height, err := dao.getLogHeight(key, i)
+		if err != nil {
+			return nil, err
+		}
+		if height > to {
+			break
+		}
+		heights = append(heights, height)
+	}
+	return heights, nil
+}
+
 // deleteBlock deletes the tip block
 func (dao *blockDAO) deleteTipBlock() error {
 	batch := db.NewBatch()
@@ -894,6 +1363,14 @@ func (dao *blockDAO) deleteTipBlock() error {
 		return err
 	}
 
+	if err = deleteActions(dao, blk, batch); err != nil {
+		return err
+	}
+
+	if err = deleteLogs(dao, blk, batch); err != nil {
+		return err
+	}
+
 	if err = deleteReceipts(blk, batch); err != nil {
 		return err
 	}
@@ -1108,10 +1585,74 @@ func deleteExecutions(dao *blockDAO, blk *Block, batch db.KVStoreBatch) error {
 	return nil
 }
 
+// deleteActions deletes the index of all txs and actions from db
+func deleteActions(dao *blockDAO, blk *Block, batch db.KVStoreBatch) error {
+	acts := blk.allActions()
+	counts := map[string]uint64{}
+	for _, act := range acts {
+		actHash := act.Hash()
+		hashKey := append(actionPrefix, actHash[:]...)
+		batch.Delete(blockActionBlockMappingNS, hashKey, "failed to delete action hash %x", actHash)
+
+		for _, key := range actionAddressKeys(act) {
+			if _, ok := counts[string(key)]; !ok {
+				count, err := dao.getActionCount(key)
+				if err != nil {
+					return err
+				}
+				counts[string(key)] = count
+			}
+		}
+	}
+	// the actions of the tip block are the last ones of each address, so delete them from the end
+	for i := len(acts) - 1; i >= 0; i-- {
+		act := acts[i]
+		for _, key := range actionAddressKeys(act) {
+			counts[string(key)]--
+			batch.Delete(blockAddressActionMappingNS, append(key, byteutil.Uint64ToBytes(counts[string(key)])...),
+				"failed to delete action hash %x for %s", act.Hash(), key)
+		}
+	}
+	// roll back the actions count of addresses to the previous block
+	for key, count := range counts {
+		batch.Put(blockAddressActionCountMappingNS, []byte(key), byteutil.Uint64ToBytes(count),
+			"failed to update action count for %s", key)
+	}
+	return nil
+}
+
+// deleteLogs deletes the log index of the tip block from db
+func deleteLogs(dao *blockDAO, blk *Block, batch db.KVStoreBatch) error {
+	// receipts are not serialized with the block, so read them from db
+	receipts := make(map[hash.Hash32B]*Receipt)
+	for _, execution := range blk.Executions {
+		if receipt, err := dao.getReceiptByActionHash(execution.Hash()); err == nil {
+			receipts[execution.Hash()] = receipt
+		}
+	}
+	// the tip block is the last one indexed for each key
+	for _, key := range logIndexKeys(receipts) {
+		count, err := dao.getLogHeightCount(key)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.Errorf("no log height of %s to delete", key)
+		}
+		batch.Delete(blockLogHeightMappingNS, append(key, byteutil.Uint64ToBytes(count-1)...),
+			"failed to delete height %d for %s", blk.Height(), key)
+		batch.Put(blockLogHeightCountMappingNS, key, byteutil.Uint64ToBytes(count-1),
+			"failed to update log height count for %s", key)
+	}
+	return nil
+}
+
 // deleteReceipts deletes receipt information from db
 func deleteReceipts(blk *Block, batch db.KVStoreBatch) error {
-	for _, r := range blk.receipts {
-		batch.Delete(blockExecutionReceiptMappingNS, r.Hash[:], "failed to delete receipt for execution %x", r.Hash[:])
+	// receipts are not serialized with the block, so delete them by the hashes of actions
+	for _, act := range blk.allActions() {
+		actHash := act.Hash()
+		batch.Delete(blockExecutionReceiptMappingNS, actHash[:], "failed to delete receipt for action %x", actHash)
 	}
 	return nil
 }
@@ -398,6 +398,11 @@ func TestBlockDAO(t *testing.T) {
 		blkHash, err = dao.getBlockHashByExecutionHash(executionHash)
 		require.NoError(err)
 		require.Equal(blks[2].HashBlock(), blkHash)
+		receipt := &Receipt{Hash: executionHash, Status: 1}
+		require.NoError(dao.putReceipts(&Block{receipts: map[hash.Hash32B]*Receipt{executionHash: receipt}}))
+		receipt, err = dao.getReceiptByActionHash(executionHash)
+		require.NoError(err)
+		require.Equal(executionHash, receipt.Hash)
 
 		charlieAddr := testaddress.Addrinfo["charlie"].RawAddress
 		deltaAddr := testaddress.Addrinfo["delta"].RawAddress
@@ -466,6 +471,8 @@ func TestBlockDAO(t *testing.T) {
 		blkHash, err = dao.getBlockHashByExecutionHash(executionHash)
 		require.Equal(db.ErrNotExist, errors.Cause(err))
 		require.Equal(hash.ZeroHash32B, blkHash)
+		_, err = dao.getReceiptByActionHash(executionHash)
+		require.Error(err)
 
 		transfersFromCharlie, _ = dao.getTransfersBySenderAddress(charlieAddr)
 		require.Equal(0, len(transfersFromCharlie))
@@ -536,3 +543,158 @@ func TestBlockDAO(t *testing.T) {
 		testDeleteDao(db.NewBoltDB(path, cfg), t)
 	})
 }
+
+func TestBlockDAO_GetActionsByAddress(t *testing.T) {
+	require := require.New(t)
+
+	alfa := testaddress.Addrinfo["alfa"].RawAddress
+	bravo := testaddress.Addrinfo["bravo"].RawAddress
+	ctx := context.Background()
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+	dao := newBlockDAO(&cfg, db.NewMemKVStore())
+	require.NoError(dao.Start(ctx))
+	defer func() {
+		require.NoError(dao.Stop(ctx))
+	}()
+
+	// block h has a transfer from alfa to bravo, and another from bravo to alfa, at timestamp 1000 + 10h
+	var tsfs []*action.Transfer
+	prevHash := hash.ZeroHash32B
+	for h := uint64(1); h <= 5; h++ {
+		tsf1, err := action.NewTransfer(h, big.NewInt(1), alfa, bravo, nil, uint64(100000), big.NewInt(10))
+		require.NoError(err)
+		tsf2, err := action.NewTransfer(h, big.NewInt(1), bravo, alfa, nil, uint64(100000), big.NewInt(10))
+		require.NoError(err)
+		blk := NewBlock(0, h, prevHash, 1000+10*h, []*action.Transfer{tsf1, tsf2}, nil, nil, nil)
+		require.NoError(dao.putBlock(blk))
+		prevHash = blk.HashBlock()
+		tsfs = append(tsfs, tsf1, tsf2)
+	}
+	// expected returns the hashes of the transfers of the given heights in reverse chronological order
+	expected := func(from, to uint64) []hash.Hash32B {
+		var res []hash.Hash32B
+		for h := to; h >= from; h-- {
+			res = append(res, tsfs[2*h-2].Hash(), tsfs[2*h-1].Hash())
+		}
+		return res
+	}
+
+	hashes, cursor, err := dao.getActionsByAddress(transferIndices, alfa, &HistoryQuery{Limit: 3})
+	require.NoError(err)
+	require.Equal(expected(4, 5)[:3], hashes)
+	require.NotEmpty(cursor)
+	hashes, cursor, err = dao.getActionsByAddress(transferIndices, alfa, &HistoryQuery{Cursor: cursor, Limit: 3})
+	require.NoError(err)
+	require.Equal(expected(3, 4)[1:], hashes)
+	require.NotEmpty(cursor)
+	hashes, cursor, err = dao.getActionsByAddress(transferIndices, alfa, &HistoryQuery{Cursor: cursor, Limit: 10})
+	require.NoError(err)
+	require.Equal(expected(1, 2), hashes)
+	require.Empty(cursor)
+
+	// height range
+	hashes, cursor, err = dao.getActionsByAddress(transferIndices, alfa, &HistoryQuery{
+		Limit:       10,
+		StartHeight: 2,
+		EndHeight:   3,
+	})
+	require.NoError(err)
+	require.Equal(expected(2, 3), hashes)
+	require.Empty(cursor)
+
+	// time range, paged
+	hashes, cursor, err = dao.getActionsByAddress(transferIndices, alfa, &HistoryQuery{Limit: 2, StartTime: 1040})
+	require.NoError(err)
+	require.Equal(expected(5, 5), hashes)
+	require.NotEmpty(cursor)
+	hashes, cursor, err = dao.getActionsByAddress(transferIndices, alfa, &HistoryQuery{
+		Cursor:    cursor,
+		Limit:     10,
+		StartTime: 1040,
+	})
+	require.NoError(err)
+	require.Equal(expected(4, 4), hashes)
+	require.Empty(cursor)
+
+	// address without history
+	hashes, cursor, err = dao.getActionsByAddress(voteIndices, alfa, &HistoryQuery{Limit: 10})
+	require.NoError(err)
+	require.Empty(hashes)
+	require.Empty(cursor)
+
+	_, _, err = dao.getActionsByAddress(transferIndices, alfa, &HistoryQuery{Cursor: "00", Limit: 10})
+	require.Error(err)
+}
+
+func TestBlockDAO_ActionIndex(t *testing.T) {
+	require := require.New(t)
+
+	alfa := testaddress.Addrinfo["alfa"].RawAddress
+	bravo := testaddress.Addrinfo["bravo"].RawAddress
+	ctx := context.Background()
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+	dao := newBlockDAO(&cfg, db.NewMemKVStore())
+	require.NoError(dao.Start(ctx))
+	defer func() {
+		require.NoError(dao.Stop(ctx))
+	}()
+
+	tsf, err := action.NewTransfer(1, big.NewInt(1), alfa, bravo, nil, uint64(100000), big.NewInt(10))
+	require.NoError(err)
+	vote, err := action.NewVote(2, alfa, alfa, uint64(100000), big.NewInt(10))
+	require.NoError(err)
+	blk1 := NewBlock(0, 1, hash.ZeroHash32B, testutil.TimestampNow(), []*action.Transfer{tsf}, []*action.Vote{vote}, nil, nil)
+	require.NoError(dao.putBlock(blk1))
+	start := action.NewStartSubChain(3, 2, alfa, big.NewInt(1), big.NewInt(1), 10, 10, uint64(100000), big.NewInt(10))
+	blk2 := NewBlock(0, 2, blk1.HashBlock(), testutil.TimestampNow(), nil, nil, nil, []action.Action{start})
+	require.NoError(dao.putBlock(blk2))
+
+	// sub-chain action is indexed and could be read back from block
+	blkHash, err := dao.getBlockHashByActionHash(start.Hash())
+	require.NoError(err)
+	require.Equal(blk2.HashBlock(), blkHash)
+	blk, err := dao.getBlock(blkHash)
+	require.NoError(err)
+	require.Equal(1, len(blk.Actions))
+	require.Equal(start.Hash(), blk.Actions[0].Hash())
+	// sub-chain action could be proved to be included in the block
+	proof, err := (&blockchain{dao: dao, config: &cfg}).GetActionProof(start.Hash())
+	require.NoError(err)
+	require.Equal(blk2.HashBlock(), proof.BlockHeader.HashHeader())
+	require.True(proof.Verify())
+	blkHash, err = dao.getBlockHashByActionHash(vote.Hash())
+	require.NoError(err)
+	require.Equal(blk1.HashBlock(), blkHash)
+
+	// self vote is only indexed once
+	hashes, cursor, err := dao.getActionsByAddress(actionIndices, alfa, &HistoryQuery{Limit: 10})
+	require.NoError(err)
+	require.Equal([]hash.Hash32B{start.Hash(), vote.Hash(), tsf.Hash()}, hashes)
+	require.Empty(cursor)
+	hashes, _, err = dao.getActionsByAddress(actionIndices, bravo, &HistoryQuery{Limit: 10})
+	require.NoError(err)
+	require.Equal([]hash.Hash32B{tsf.Hash()}, hashes)
+
+	require.NoError(dao.deleteTipBlock())
+	_, err = dao.getBlockHashByActionHash(start.Hash())
+	require.Error(err)
+	hashes, _, err = dao.getActionsByAddress(actionIndices, alfa, &HistoryQuery{Limit: 10})
+	require.NoError(err)
+	require.Equal([]hash.Hash32B{vote.Hash(), tsf.Hash()}, hashes)
+
+	// the blocks committed before the action index are looked up by the indices of transfers, votes and executions
+	for _, h := range []hash.Hash32B{tsf.Hash(), vote.Hash()} {
+		require.NoError(dao.kvstore.Delete(blockActionBlockMappingNS, append(actionPrefix, h[:]...)))
+		blkHash, err = dao.getBlockHashByActionHash(h)
+		require.NoError(err)
+		require.Equal(blk1.HashBlock(), blkHash)
+		proof, err = (&blockchain{dao: dao, config: &cfg}).GetActionProof(h)
+		require.NoError(err)
+		require.Equal(blk1.HashBlock(), proof.BlockHeader.HashHeader())
+		require.True(proof.Verify())
+	}
+	_, err = dao.getBlockHashByActionHash(hash.ZeroHash32B)
+	require.Equal(db.ErrNotExist, errors.Cause(err))
+}
@@ -32,6 +32,10 @@ type Validator interface {
 type validator struct {
 	sf            state.Factory
 	validatorAddr string
+	// gasLimit and maxBlockSize bound the total gas and the total size of the actions in a block, from limitHeight on
+	gasLimit     uint64
+	maxBlockSize uint64
+	limitHeight  uint64
 }
 
 var (
@@ -49,6 +53,8 @@ var (
 	ErrBalance = errors.New("invalid balance")
 	// ErrDKGSecretProposal indicates the error of DKG secret proposal
 	ErrDKGSecretProposal = errors.New("invalid DKG secret proposal")
+	// ErrExceedBlockLimit indicates the error of a block exceeding the gas limit or the size limit
+	ErrExceedBlockLimit = errors.New("block exceeds the limit")
 )
 
 // Validate validates the given block's content
@@ -62,6 +68,9 @@ func (v *validator) Validate(blk *Block, tipHeight uint64, tipHash hash.Hash32B,
 	if err := verifySigAndRoot(blk); err != nil {
 		return errors.Wrap(err, "failed to verify block's signature and merkle root")
 	}
+	if err := v.verifyLimits(blk); err != nil {
+		return errors.Wrap(err, "failed to verify block's gas and size")
+	}
 
 	if v.sf != nil {
 		return v.verifyActions(blk, containCoinbase)
@@ -314,6 +323,24 @@ func (v *validator) verifyActions(blk *Block, containCoinbase bool) error {
 	return nil
 }
 
+func (v *validator) verifyLimits(blk *Block) error {
+	// genesis block and the blocks lower than the limit height are not limited
+	if blk.Header.height == 0 || blk.Header.height < v.limitHeight {
+		return nil
+	}
+	gas, err := blk.gas()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the gas of block")
+	}
+	if gas > v.gasLimit {
+		return errors.Wrapf(ErrExceedBlockLimit, "block gas %d is higher than limit %d", gas, v.gasLimit)
+	}
+	if size := blk.actionsSize(); size > v.maxBlockSize {
+		return errors.Wrapf(ErrExceedBlockLimit, "block size %d is larger than limit %d", size, v.maxBlockSize)
+	}
+	return nil
+}
+
 func verifyHeightAndHash(blk *Block, tipHeight uint64, tipHash hash.Hash32B) error {
 	if blk == nil {
 		return ErrInvalidBlock
@@ -360,3 +387,21 @@ func verifySigAndRoot(blk *Block) error {
 	}
 	return nil
 }
+
+// verifyReceiptRoot verifies the receipt root and log bloom in the header against the receipts produced in running
+// the block
+func verifyReceiptRoot(blk *Block) error {
+	hashExpect := blk.Header.receiptRoot
+	hashActual := blk.ReceiptRoot()
+	if hashExpect != hashActual {
+		return errors.Wrapf(
+			ErrInvalidBlock,
+			"wrong receipt root %x, expecting %x",
+			hashActual,
+			hashExpect)
+	}
+	if blk.LogBloom() != blk.Header.logBloom {
+		return errors.Wrap(ErrInvalidBlock, "wrong log bloom")
+	}
+	return nil
+}
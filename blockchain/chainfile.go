@@ -0,0 +1,222 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/enc"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+const (
+	// chainFileVersion is the version of the chain file format
+	chainFileVersion uint32 = 1
+	// maxChainFileRecordSize is the max size of a block record in chain file
+	maxChainFileRecordSize = 64 << 20
+)
+
+var (
+	// chainFileMagic is the magic bytes at the beginning of a chain file
+	chainFileMagic = []byte("IOTXCHN")
+
+	// ErrInvalidChainFile is the error that the chain file is malformed or does not match the chain
+	ErrInvalidChainFile = errors.New("invalid chain file")
+)
+
+// ChainFileHeader is the header of a chain file, which identifies the chain the blocks in the file belong to
+type ChainFileHeader struct {
+	Version     uint32
+	ChainID     uint32
+	GenesisHash hash.Hash32B
+	StartHeight uint64
+	EndHeight   uint64
+}
+
+// ExportChain writes the blocks of height in [start, end] to w. The chain file starts with a header carrying the chain
+// ID and the genesis block hash, followed by the blocks as length-prefixed BlockPb records
+func ExportChain(bc Blockchain, w io.Writer, start uint64, end uint64) error {
+	if start == 0 {
+		// genesis block is created by every node itself, so it is never exported
+		start = 1
+	}
+	if end > bc.TipHeight() {
+		return errors.Errorf("end height %d is higher than the tip height %d", end, bc.TipHeight())
+	}
+	if start > end {
+		return errors.Errorf("start height %d is higher than end height %d", start, end)
+	}
+	genesisHash, err := bc.GetHashByHeight(0)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the hash of genesis block")
+	}
+	header := &ChainFileHeader{
+		Version:     chainFileVersion,
+		ChainID:     bc.ChainID(),
+		GenesisHash: genesisHash,
+		StartHeight: start,
+		EndHeight:   end,
+	}
+	if err := writeChainFileHeader(w, header); err != nil {
+		return err
+	}
+	for height := start; height <= end; height++ {
+		blk, err := bc.GetBlockByHeight(height)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get block %d", height)
+		}
+		serialized, err := blk.Serialize()
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize block %d", height)
+		}
+		if err := writeChainFileRecord(w, serialized); err != nil {
+			return errors.Wrapf(err, "failed to write block %d", height)
+		}
+	}
+	return nil
+}
+
+// ImportChain reads the blocks from a chain file, validates and commits them to the chain. Blocks not higher than the
+// tip of the chain are skipped after checking they match the ones on the chain, so that an interrupted import could
+// be resumed by importing the same file again. It returns the number of blocks committed
+func ImportChain(bc Blockchain, r io.Reader) (uint64, error) {
+	header, err := readChainFileHeader(r)
+	if err != nil {
+		return 0, err
+	}
+	if header.ChainID != bc.ChainID() {
+		return 0, errors.Wrapf(ErrInvalidChainFile, "chain ID %d does not match %d", header.ChainID, bc.ChainID())
+	}
+	genesisHash, err := bc.GetHashByHeight(0)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get the hash of genesis block")
+	}
+	if header.GenesisHash != genesisHash {
+		return 0, errors.Wrapf(ErrInvalidChainFile, "genesis hash %x does not match %x", header.GenesisHash, genesisHash)
+	}
+	if header.StartHeight > bc.TipHeight()+1 {
+		return 0, errors.Wrapf(ErrInvalidChainFile, "start height %d is higher than the next height %d",
+			header.StartHeight, bc.TipHeight()+1)
+	}
+
+	var committed uint64
+	for height := header.StartHeight; height <= header.EndHeight; height++ {
+		serialized, err := readChainFileRecord(r)
+		if err != nil {
+			return committed, errors.Wrapf(err, "failed to read block %d", height)
+		}
+		blk := &Block{}
+		if err := blk.Deserialize(serialized); err != nil {
+			return committed, errors.Wrapf(ErrInvalidChainFile, "failed to deserialize block %d: %v", height, err)
+		}
+		if blk.Height() != height {
+			return committed, errors.Wrapf(ErrInvalidChainFile, "block %d is at height %d", height, blk.Height())
+		}
+		if height <= bc.TipHeight() {
+			// the block has been committed before, e.g., by a previous import
+			blkHash, err := bc.GetHashByHeight(height)
+			if err != nil {
+				return committed, errors.Wrapf(err, "failed to get the hash of block %d", height)
+			}
+			if blkHash != blk.HashBlock() {
+				return committed, errors.Wrapf(ErrInvalidChainFile, "block %d does not match the chain", height)
+			}
+			continue
+		}
+		// secret blocks of DKG do not contain coinbase transfer
+		containCoinbase := len(blk.SecretProposals) == 0 && blk.SecretWitness == nil
+		if err := bc.ValidateBlock(blk, containCoinbase); err != nil {
+			return committed, errors.Wrapf(err, "failed to validate block %d", height)
+		}
+		if err := bc.CommitBlock(blk); err != nil {
+			return committed, errors.Wrapf(err, "failed to commit block %d", height)
+		}
+		committed++
+	}
+	logger.Info().
+		Uint64("start", header.StartHeight).
+		Uint64("end", header.EndHeight).
+		Uint64("committed", committed).
+		Msg("Imported chain file")
+	return committed, nil
+}
+
+func writeChainFileHeader(w io.Writer, header *ChainFileHeader) error {
+	stream := make([]byte, 0, len(chainFileMagic)+4+4+hash.HashSize+8+8)
+	stream = append(stream, chainFileMagic...)
+	tmp4B := make([]byte, 4)
+	enc.MachineEndian.PutUint32(tmp4B, header.Version)
+	stream = append(stream, tmp4B...)
+	enc.MachineEndian.PutUint32(tmp4B, header.ChainID)
+	stream = append(stream, tmp4B...)
+	stream = append(stream, header.GenesisHash[:]...)
+	tmp8B := make([]byte, 8)
+	enc.MachineEndian.PutUint64(tmp8B, header.StartHeight)
+	stream = append(stream, tmp8B...)
+	enc.MachineEndian.PutUint64(tmp8B, header.EndHeight)
+	stream = append(stream, tmp8B...)
+	if _, err := w.Write(stream); err != nil {
+		return errors.Wrap(err, "failed to write chain file header")
+	}
+	return nil
+}
+
+func readChainFileHeader(r io.Reader) (*ChainFileHeader, error) {
+	stream := make([]byte, len(chainFileMagic)+4+4+hash.HashSize+8+8)
+	if _, err := io.ReadFull(r, stream); err != nil {
+		return nil, errors.Wrapf(ErrInvalidChainFile, "failed to read header: %v", err)
+	}
+	if !bytes.Equal(stream[:len(chainFileMagic)], chainFileMagic) {
+		return nil, errors.Wrap(ErrInvalidChainFile, "magic bytes do not match")
+	}
+	stream = stream[len(chainFileMagic):]
+	header := &ChainFileHeader{}
+	header.Version = enc.MachineEndian.Uint32(stream[:4])
+	if header.Version != chainFileVersion {
+		return nil, errors.Wrapf(ErrInvalidChainFile, "unsupported version %d", header.Version)
+	}
+	header.ChainID = enc.MachineEndian.Uint32(stream[4:8])
+	copy(header.GenesisHash[:], stream[8:8+hash.HashSize])
+	stream = stream[8+hash.HashSize:]
+	header.StartHeight = enc.MachineEndian.Uint64(stream[:8])
+	header.EndHeight = enc.MachineEndian.Uint64(stream[8:16])
+	if header.StartHeight > header.EndHeight {
+		return nil, errors.Wrapf(ErrInvalidChainFile, "start height %d is higher than end height %d",
+			header.StartHeight, header.EndHeight)
+	}
+	return header, nil
+}
+
+func writeChainFileRecord(w io.Writer, record []byte) error {
+	size := make([]byte, 4)
+	enc.MachineEndian.PutUint32(size, uint32(len(record)))
+	if _, err := w.Write(size); err != nil {
+		return err
+	}
+	_, err := w.Write(record)
+	return err
+}
+
+func readChainFileRecord(r io.Reader) ([]byte, error) {
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(r, size); err != nil {
+		return nil, err
+	}
+	length := enc.MachineEndian.Uint32(size)
+	if length > maxChainFileRecordSize {
+		return nil, errors.Wrapf(ErrInvalidChainFile, "record size %d exceeds the limit", length)
+	}
+	record := make([]byte, length)
+	if _, err := io.ReadFull(r, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
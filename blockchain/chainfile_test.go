@@ -0,0 +1,96 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestExportImportChain(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Disable block reward to make bookkeeping easier
+	Gen.BlockReward = uint64(0)
+	cfg := config.Default
+	newChain := func() Blockchain {
+		bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+		require.NoError(bc.Start(ctx))
+		return bc
+	}
+	bc := newChain()
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	require.NoError(addTestingTsfBlocks(bc))
+	require.Equal(uint64(5), bc.TipHeight())
+
+	var full, partial bytes.Buffer
+	require.NoError(ExportChain(bc, &full, 0, bc.TipHeight()))
+	require.NoError(ExportChain(bc, &partial, 1, 2))
+	require.Error(ExportChain(bc, &bytes.Buffer{}, 1, 6))
+	require.Error(ExportChain(bc, &bytes.Buffer{}, 3, 2))
+
+	// import the whole chain
+	bc1 := newChain()
+	defer func() {
+		require.NoError(bc1.Stop(ctx))
+	}()
+	committed, err := ImportChain(bc1, bytes.NewReader(full.Bytes()))
+	require.NoError(err)
+	require.Equal(uint64(5), committed)
+	require.Equal(bc.TipHash(), bc1.TipHash())
+	for _, addr := range []string{ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["charlie"].RawAddress} {
+		balance, err := bc.Balance(addr)
+		require.NoError(err)
+		balance1, err := bc1.Balance(addr)
+		require.NoError(err)
+		require.Equal(balance, balance1)
+	}
+
+	// resume an interrupted import
+	bc2 := newChain()
+	defer func() {
+		require.NoError(bc2.Stop(ctx))
+	}()
+	committed, err = ImportChain(bc2, bytes.NewReader(partial.Bytes()))
+	require.NoError(err)
+	require.Equal(uint64(2), committed)
+	require.Equal(uint64(2), bc2.TipHeight())
+	committed, err = ImportChain(bc2, bytes.NewReader(full.Bytes()))
+	require.NoError(err)
+	require.Equal(uint64(3), committed)
+	require.Equal(bc.TipHash(), bc2.TipHash())
+
+	// truncated file
+	bc3 := newChain()
+	defer func() {
+		require.NoError(bc3.Stop(ctx))
+	}()
+	_, err = ImportChain(bc3, bytes.NewReader(full.Bytes()[:full.Len()-1]))
+	require.Error(err)
+	require.Equal(uint64(4), bc3.TipHeight())
+
+	// file of another chain
+	cfg.Chain.ID = config.Default.Chain.ID + 1
+	bc4 := newChain()
+	defer func() {
+		require.NoError(bc4.Stop(ctx))
+	}()
+	_, err = ImportChain(bc4, bytes.NewReader(full.Bytes()))
+	require.Equal(ErrInvalidChainFile, errors.Cause(err))
+	_, err = ImportChain(bc4, bytes.NewReader([]byte("not a chain file")))
+	require.Equal(ErrInvalidChainFile, errors.Cause(err))
+}
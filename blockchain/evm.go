@@ -17,6 +17,7 @@ import (
 
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/address"
+	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
@@ -54,10 +55,16 @@ type EVMParams struct {
 	contract           *common.Address
 	gas                uint64
 	data               []byte
+	precompiles        map[common.Address]vm.PrecompiledContract
 }
 
-// NewEVMParams creates a new context for use in the EVM.
-func NewEVMParams(blk *Block, execution *action.Execution, stateDB *EVMStateDBAdapter) (*EVMParams, error) {
+// NewEVMParams creates a new context for use in the EVM, by the block gas limit and the fork heights of the config
+func NewEVMParams(
+	blk *Block,
+	execution *action.Execution,
+	stateDB *EVMStateDBAdapter,
+	cfg *config.Config,
+) (*EVMParams, error) {
 	// If we don't have an explicit author (i.e. not mining), extract from the header
 	/*
 		var beneficiary common.Address
@@ -92,7 +99,7 @@ func NewEVMParams(blk *Block, execution *action.Execution, stateDB *EVMStateDBAd
 		BlockNumber: new(big.Int).SetUint64(blk.Height()),
 		Time:        new(big.Int).SetInt64(blk.Header.Timestamp().Unix()),
 		Difficulty:  new(big.Int).SetUint64(uint64(50)),
-		GasLimit:    action.GasLimit,
+		GasLimit:    cfg.Chain.BlockGasLimit,
 		GasPrice:    execution.GasPrice(),
 	}
 
@@ -104,6 +111,7 @@ func NewEVMParams(blk *Block, execution *action.Execution, stateDB *EVMStateDBAd
 		contractAddrPointer,
 		execution.GasLimit(),
 		execution.Data(),
+		precompilesAtHeight(blk.Height(), cfg.Chain.PrecompileHeight),
 	}, nil
 }
 
@@ -137,26 +145,37 @@ func securityDeposit(ps *EVMParams, stateDB vm.StateDB, gasLimit *uint64) error
 	return nil
 }
 
-// ExecuteContracts process the contracts in a block
-func ExecuteContracts(blk *Block, ws state.WorkingSet, bc Blockchain) {
-	gasLimit := action.GasLimit
+// ExecuteContracts process the contracts in a block within the block gas limit
+func ExecuteContracts(blk *Block, ws state.WorkingSet, bc Blockchain, cfg *config.Config) {
 	blk.receipts = make(map[hash.Hash32B]*Receipt)
+	gasLimit := cfg.Chain.BlockGasLimit
 	for idx, execution := range blk.Executions {
 		// TODO (zhi) log receipt to stateDB
-		if receipt, _ := executeContract(blk, ws, idx, execution, bc, &gasLimit); receipt != nil {
+		if receipt, _ := executeContract(blk, ws, idx, execution, bc, cfg, &gasLimit, nil); receipt != nil {
 			blk.receipts[execution.Hash()] = receipt
 		}
 	}
 }
 
-// executeContract processes a transfer which contains a contract
-func executeContract(blk *Block, ws state.WorkingSet, idx int, execution *action.Execution, bc Blockchain, gasLimit *uint64) (*Receipt, error) {
+// executeContract processes a transfer which contains a contract. gasLimit is the gas left in the block, which is
+// updated after the execution. If tracer is not nil, it captures the steps of evm
+func executeContract(
+	blk *Block,
+	ws state.WorkingSet,
+	idx int,
+	execution *action.Execution,
+	bc Blockchain,
+	cfg *config.Config,
+	gasLimit *uint64,
+	tracer vm.Tracer,
+) (*Receipt, error) {
 	stateDB := NewEVMStateDBAdapter(bc, ws, blk.Height(), blk.HashBlock(), uint(idx), execution.Hash())
-	ps, err := NewEVMParams(blk, execution, stateDB)
+	stateDB.legacy = blk.Height() < cfg.Chain.EVMStateHeight
+	ps, err := NewEVMParams(blk, execution, stateDB, cfg)
 	if err != nil {
 		return nil, err
 	}
-	retval, depositGas, remainingGas, contractAddress, err := executeInEVM(ps, stateDB, gasLimit)
+	retval, depositGas, remainingGas, contractAddress, err := executeInEVM(ps, stateDB, gasLimit, tracer)
 	receipt := &Receipt{
 		ReturnValue:     retval,
 		GasConsumed:     ps.gas - remainingGas,
@@ -165,6 +184,7 @@ func executeContract(blk *Block, ws state.WorkingSet, idx int, execution *action
 	}
 	if err != nil {
 		receipt.Status = FailureStatus
+		receipt.ErrorCode = errorCode(err)
 	} else {
 		receipt.Status = SuccessStatus
 	}
@@ -177,6 +197,10 @@ func executeContract(blk *Block, ws state.WorkingSet, idx int, execution *action
 		gasValue := new(big.Int).Mul(new(big.Int).SetUint64(depositGas-remainingGas), ps.context.GasPrice)
 		stateDB.AddBalance(ps.context.Coinbase, gasValue)
 	}
+	// the contracts killed in a successful execution are deleted after paying for gas, except in the legacy blocks
+	if !stateDB.legacy {
+		stateDB.deleteSuicided()
+	}
 	receipt.Logs = stateDB.Logs()
 	logger.Debug().Msgf("Receipt: %+v, %v", receipt, err)
 	return receipt, err
@@ -190,14 +214,29 @@ func getChainConfig() *params.ChainConfig {
 	return &chainConfig
 }
 
-func executeInEVM(evmParams *EVMParams, stateDB *EVMStateDBAdapter, gasLimit *uint64) ([]byte, uint64, uint64, string, error) {
+func executeInEVM(
+	evmParams *EVMParams,
+	stateDB *EVMStateDBAdapter,
+	gasLimit *uint64,
+	tracer vm.Tracer,
+) ([]byte, uint64, uint64, string, error) {
 	remainingGas := evmParams.gas
 	if err := securityDeposit(evmParams, stateDB, gasLimit); err != nil {
 		return nil, 0, 0, action.EmptyAddress, err
 	}
-	var config vm.Config
-	chainConfig := getChainConfig()
-	evm := vm.NewEVM(evmParams.context, stateDB, chainConfig, config)
+	// all the changes made by a failed execution are reverted, except the gas deposit. The legacy blocks keep them
+	snapshot := stateDB.Snapshot()
+	revert := func() {
+		if !stateDB.legacy {
+			stateDB.RevertToSnapshot(snapshot)
+		}
+	}
+	var vmConfig vm.Config
+	if tracer != nil {
+		vmConfig.Debug = true
+		vmConfig.Tracer = tracer
+	}
+	evm := vm.NewEVM(evmParams.context, stateDB, getChainConfig(), vmConfig)
 	intriGas, err := intrinsicGas(evmParams.data)
 	if err != nil {
 		return nil, evmParams.gas, remainingGas, action.EmptyAddress, err
@@ -215,10 +254,14 @@ func executeInEVM(evmParams *EVMParams, stateDB *EVMStateDBAdapter, gasLimit *ui
 		ret, evmContractAddress, remainingGas, err = evm.Create(executor, evmParams.data, remainingGas, evmParams.amount)
 		logger.Warn().Hex("contract addrHash", evmContractAddress[:]).Msg("evm.Create")
 		if err != nil {
+			revert()
 			return nil, evmParams.gas, remainingGas, action.EmptyAddress, err
 		}
 		contractAddress := address.New(stateDB.bc.ChainID(), evmContractAddress.Bytes())
 		contractRawAddress = contractAddress.IotxAddress()
+	} else if p, ok := evmParams.precompiles[*evmParams.contract]; ok {
+		// evm only looks up the precompiled contracts of go-ethereum, so the ones of IoTeX are run apart from it
+		ret, remainingGas, err = callPrecompiledContract(evmParams, stateDB, p, remainingGas)
 	} else {
 		// process contract
 		ret, remainingGas, err = evm.Call(executor, *evmParams.contract, evmParams.data, remainingGas, evmParams.amount)
@@ -226,6 +269,9 @@ func executeInEVM(evmParams *EVMParams, stateDB *EVMStateDBAdapter, gasLimit *ui
 	if err == nil {
 		err = stateDB.Error()
 	}
+	if err != nil {
+		revert()
+	}
 	if err == vm.ErrInsufficientBalance {
 		return nil, evmParams.gas, remainingGas, action.EmptyAddress, err
 	}
@@ -233,8 +279,15 @@ func executeInEVM(evmParams *EVMParams, stateDB *EVMStateDBAdapter, gasLimit *ui
 		// TODO (zhi) should we refund if any error
 		return nil, evmParams.gas, 0, contractRawAddress, err
 	}
-	// TODO (zhi) figure out what the following function does
-	// stateDB.Finalise(true)
+	// the refund of clearing storage and killing contracts is capped to half of the gas used, except in the legacy
+	// blocks
+	if !stateDB.legacy {
+		refund := (evmParams.gas - remainingGas) / 2
+		if refund > stateDB.GetRefund() {
+			refund = stateDB.GetRefund()
+		}
+		remainingGas += refund
+	}
 	return ret, evmParams.gas, remainingGas, contractRawAddress, nil
 }
 
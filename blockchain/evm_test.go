@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/address"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
@@ -90,6 +91,9 @@ func TestEVM(t *testing.T) {
 		ta.Addrinfo["producer"].RawAddress, contractAddr, 2, big.NewInt(0), uint64(120000), big.NewInt(10), data)
 	require.NoError(err)
 	require.NoError(action.Sign(execution, ta.Addrinfo["producer"].PrivateKey))
+	gas, err := bc.EstimateGas(execution)
+	require.NoError(err)
+	require.True(gas > 0 && gas <= 120000)
 	logger.Info().Msgf("execution %+v", execution)
 	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
@@ -124,12 +128,60 @@ func TestEVM(t *testing.T) {
 	require.Equal(eHash, r.Hash)
 }
 
+func TestTraceExecution(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+	cfg.Chain.EnableHistoryState = true
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	_, err := bc.CreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(bc.GetFactory().Commit(nil))
+
+	var executions []*action.Execution
+	for nonce := uint64(1); nonce <= 2; nonce++ {
+		execution, err := action.NewExecution(
+			ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, nonce, big.NewInt(1), uint64(100000),
+			big.NewInt(10), []byte{byte(nonce)})
+		require.NoError(err)
+		require.NoError(action.Sign(execution, ta.Addrinfo["producer"].PrivateKey))
+		executions = append(executions, execution)
+	}
+	blk, err := bc.MintNewBlock(nil, nil, executions, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.NoError(bc.ValidateBlock(blk, true))
+	require.NoError(bc.CommitBlock(blk))
+
+	// re-running the execution on the states before the block gets the same receipt
+	for _, execution := range executions {
+		receipt, err := bc.GetReceiptByExecutionHash(execution.Hash())
+		require.NoError(err)
+		trace, err := bc.TraceExecution(execution.Hash())
+		require.NoError(err)
+		require.Equal(receipt.Hash, trace.Receipt.Hash)
+		require.Equal(receipt.Status, trace.Receipt.Status)
+		require.Equal(receipt.GasConsumed, trace.Receipt.GasConsumed)
+		require.NotNil(trace.Steps)
+	}
+	_, err = bc.TraceExecution(hash.ZeroHash32B)
+	require.Error(err)
+}
+
 func TestLogReceipt(t *testing.T) {
 	require := require.New(t)
 	log := Log{Address: "abcde", Data: []byte("12345"), BlockNumber: 5, Index: 6}
-	var topic hash.Hash32B
-	copy(topic[:], hash.Hash256b([]byte("12345")))
-	log.Topics = []hash.Hash32B{topic}
+	var topic1, topic2 hash.Hash32B
+	copy(topic1[:], hash.Hash256b([]byte("12345")))
+	copy(topic2[:], hash.Hash256b([]byte("67890")))
+	log.Topics = []hash.Hash32B{topic1, topic2}
 	copy(log.TxnHash[:], hash.Hash256b([]byte("11111")))
 	copy(log.BlockHash[:], hash.Hash256b([]byte("22222")))
 	s, err := log.Serialize()
@@ -137,8 +189,7 @@ func TestLogReceipt(t *testing.T) {
 	actuallog := Log{}
 	actuallog.Deserialize(s)
 	require.Equal(log.Address, actuallog.Address)
-	require.Equal(log.Topics[0], actuallog.Topics[0])
-	require.Equal(len(log.Topics), len(actuallog.Topics))
+	require.Equal(log.Topics, actuallog.Topics)
 	require.Equal(log.Data, actuallog.Data)
 	require.Equal(log.BlockNumber, actuallog.BlockNumber)
 	require.Equal(log.TxnHash, actuallog.TxnHash)
@@ -383,3 +434,67 @@ func TestERC20(t *testing.T) {
 	amount := binary.BigEndian.Uint64(h)
 	require.Equal(uint64(10000), amount)
 }
+
+func TestNewEVMParams(t *testing.T) {
+	require := require.New(t)
+
+	execution, err := action.NewExecution(ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, 1,
+		big.NewInt(0), uint64(100000), big.NewInt(10), []byte{})
+	require.NoError(err)
+	blk := NewBlock(0, 1, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, []*action.Execution{execution}, nil)
+	cfg := config.Default
+	cfg.Chain.PrecompileHeight = 2
+	ps, err := NewEVMParams(blk, execution, nil, &cfg)
+	require.NoError(err)
+	// the gas limit of the block is exposed to contracts, rather than the one of the execution
+	require.Equal(cfg.Chain.BlockGasLimit, ps.context.GasLimit)
+	require.Equal(execution.GasLimit(), ps.gas)
+	// the precompiled contracts of IoTeX are callable from the precompile height
+	require.Nil(ps.precompiles)
+	blk = NewBlock(0, 2, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, []*action.Execution{execution}, nil)
+	ps, err = NewEVMParams(blk, execution, nil, &cfg)
+	require.NoError(err)
+	require.Equal(iotexPrecompiles, ps.precompiles)
+}
+
+func TestEVMStateHeight(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+	cfg.Chain.EVMStateHeight = 2
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	producer := ta.Addrinfo["producer"]
+	_, err := bc.CreateState(producer.RawAddress, Gen.TotalSupply)
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(bc.GetFactory().Commit(nil))
+
+	// the execution sends the amount to the precompiled contract, which then fails on the invalid input
+	contract := address.New(cfg.Chain.ID, EC283VerifyAddress.Bytes()).IotxAddress()
+	for i, expected := range []int64{100, 100} {
+		nonce := uint64(i + 1)
+		execution, err := action.NewExecution(
+			producer.RawAddress, contract, nonce, big.NewInt(100), uint64(100000), big.NewInt(10), []byte{})
+		require.NoError(err)
+		require.NoError(action.Sign(execution, producer.PrivateKey))
+		blk, err := bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, producer, "")
+		require.NoError(err)
+		require.Equal(nonce, blk.Height())
+		require.NoError(bc.ValidateBlock(blk, true))
+		require.NoError(bc.CommitBlock(blk))
+		receipt, err := bc.GetReceiptByExecutionHash(execution.Hash())
+		require.NoError(err)
+		require.Equal(FailureStatus, receipt.Status)
+		// the amount sent by the failed execution is kept below the EVM state height, and reverted from it
+		balance, err := bc.Balance(contract)
+		require.NoError(err)
+		require.Equal(big.NewInt(expected), balance)
+	}
+}
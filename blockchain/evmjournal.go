@@ -0,0 +1,149 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"math/big"
+
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/address"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+type (
+	// journalEntry is a modification to the working set made by evm, which could be reverted
+	journalEntry interface {
+		revert(*EVMStateDBAdapter) error
+	}
+
+	// revision is a snapshot id with the length of journal when the snapshot is taken
+	revision struct {
+		id           int
+		journalIndex int
+	}
+
+	// createAccountChange is the creation of an account which did not exist
+	createAccountChange struct {
+		addr string
+	}
+
+	// balanceChange is the change of an account's balance
+	balanceChange struct {
+		addr string
+		prev *big.Int
+	}
+
+	// nonceChange is the change of an account's nonce
+	nonceChange struct {
+		addr string
+		prev uint64
+	}
+
+	// codeChange is the change of a contract's code
+	codeChange struct {
+		addr     hash.PKHash
+		prevCode []byte
+	}
+
+	// storageChange is the change of a value in a contract's storage
+	storageChange struct {
+		addr      hash.PKHash
+		key       hash.Hash32B
+		prevValue hash.Hash32B
+		existed   bool
+	}
+
+	// addLogChange is the addition of a log
+	addLogChange struct{}
+
+	// refundChange is the change of the refund counter
+	refundChange struct {
+		prev uint64
+	}
+
+	// suicideChange is the kill of a contract
+	suicideChange struct {
+		addr        common.Address
+		prev        bool
+		prevBalance *big.Int
+	}
+
+	// addPreimageChange is the addition of a preimage
+	addPreimageChange struct {
+		hash common.Hash
+	}
+)
+
+func (ch createAccountChange) revert(stateDB *EVMStateDBAdapter) error {
+	return stateDB.ws.DeleteCachedState(ch.addr)
+}
+
+func (ch balanceChange) revert(stateDB *EVMStateDBAdapter) error {
+	state, err := stateDB.ws.CachedState(ch.addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to revert balance of %s", ch.addr)
+	}
+	state.Balance = ch.prev
+	return nil
+}
+
+func (ch nonceChange) revert(stateDB *EVMStateDBAdapter) error {
+	state, err := stateDB.ws.CachedState(ch.addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to revert nonce of %s", ch.addr)
+	}
+	state.Nonce = ch.prev
+	return nil
+}
+
+func (ch codeChange) revert(stateDB *EVMStateDBAdapter) error {
+	if len(ch.prevCode) == 0 {
+		return stateDB.ws.DeleteCode(ch.addr)
+	}
+	return stateDB.ws.SetCode(ch.addr, ch.prevCode)
+}
+
+func (ch storageChange) revert(stateDB *EVMStateDBAdapter) error {
+	if ch.existed {
+		return stateDB.ws.SetContractState(ch.addr, ch.key, ch.prevValue)
+	}
+	if err := stateDB.ws.DeleteContractState(ch.addr, ch.key); err != nil && errors.Cause(err) != trie.ErrNotExist {
+		return err
+	}
+	return nil
+}
+
+func (ch addLogChange) revert(stateDB *EVMStateDBAdapter) error {
+	stateDB.logs = stateDB.logs[:len(stateDB.logs)-1]
+	return nil
+}
+
+func (ch refundChange) revert(stateDB *EVMStateDBAdapter) error {
+	stateDB.refund = ch.prev
+	return nil
+}
+
+func (ch suicideChange) revert(stateDB *EVMStateDBAdapter) error {
+	addr := address.New(stateDB.bc.ChainID(), ch.addr.Bytes())
+	state, err := stateDB.ws.CachedState(addr.IotxAddress())
+	if err != nil {
+		return errors.Wrapf(err, "failed to revert suicide of %s", addr.IotxAddress())
+	}
+	if !ch.prev {
+		delete(stateDB.suicided, ch.addr)
+	}
+	state.Balance = ch.prevBalance
+	return nil
+}
+
+func (ch addPreimageChange) revert(stateDB *EVMStateDBAdapter) error {
+	delete(stateDB.preimages, ch.hash)
+	return nil
+}
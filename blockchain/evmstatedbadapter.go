@@ -8,9 +8,12 @@ package blockchain
 
 import (
 	"math/big"
+	"sort"
 
 	"github.com/CoderZhi/go-ethereum/common"
 	"github.com/CoderZhi/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
 	"github.com/iotexproject/iotex-core/address"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
@@ -18,6 +21,9 @@ import (
 	"github.com/iotexproject/iotex-core/state"
 )
 
+// errStopIteration is returned by the storage visitor to stop iterating the storage
+var errStopIteration = errors.New("stop iterating storage")
+
 // EVMStateDBAdapter represents the state db adapter for evm to access iotx blockchain
 type EVMStateDBAdapter struct {
 	bc             Blockchain
@@ -28,6 +34,15 @@ type EVMStateDBAdapter struct {
 	blockHash      hash.Hash32B
 	executionIndex uint
 	executionHash  hash.Hash32B
+	journal        []journalEntry
+	revisions      []revision
+	nextRevisionID int
+	refund         uint64
+	suicided       map[common.Address]struct{}
+	preimages      map[common.Hash][]byte
+	// legacy is set if the block is lower than chain.EVMStateHeight, whose executions keep the changes made before they
+	// fail, and run without nonces, killing contracts and gas refunds
+	legacy bool
 }
 
 // NewEVMStateDBAdapter creates a new state db with iotx blockchain
@@ -41,6 +56,13 @@ func NewEVMStateDBAdapter(bc Blockchain, ws state.WorkingSet, blockHeight uint64
 		blockHash,
 		executionIndex,
 		executionHash,
+		[]journalEntry{},
+		[]revision{},
+		0,
+		0,
+		make(map[common.Address]struct{}),
+		make(map[common.Hash][]byte),
+		false,
 	}
 }
 
@@ -58,12 +80,16 @@ func (stateDB *EVMStateDBAdapter) Error() error {
 // CreateAccount creates an account in iotx blockchain
 func (stateDB *EVMStateDBAdapter) CreateAccount(evmAddr common.Address) {
 	addr := address.New(stateDB.bc.ChainID(), evmAddr.Bytes())
-	_, err := stateDB.ws.LoadOrCreateState(addr.IotxAddress(), 0)
-	if err != nil {
+	_, err := stateDB.ws.CachedState(addr.IotxAddress())
+	existed := errors.Cause(err) != state.ErrAccountNotExist
+	if _, err := stateDB.ws.LoadOrCreateState(addr.IotxAddress(), 0); err != nil {
 		logger.Error().Err(err).Msg("CreateAccount")
 		// stateDB.logError(err)
 		return
 	}
+	if !existed {
+		stateDB.journal = append(stateDB.journal, createAccountChange{addr.IotxAddress()})
+	}
 	logger.Debug().Hex("addrHash", evmAddr[:]).Msg("CreateAccount")
 }
 
@@ -81,6 +107,7 @@ func (stateDB *EVMStateDBAdapter) SubBalance(evmAddr common.Address, amount *big
 		stateDB.logError(err)
 		return
 	}
+	stateDB.journal = append(stateDB.journal, balanceChange{addr.IotxAddress(), new(big.Int).Set(state.Balance)})
 	state.SubBalance(amount)
 	// stateDB.GetBalance(evmAddr)
 }
@@ -100,6 +127,7 @@ func (stateDB *EVMStateDBAdapter) AddBalance(evmAddr common.Address, amount *big
 		stateDB.logError(err)
 		return
 	}
+	stateDB.journal = append(stateDB.journal, balanceChange{addr.IotxAddress(), new(big.Int).Set(state.Balance)})
 	state.AddBalance(amount)
 	// stateDB.GetBalance(evmAddr)
 }
@@ -120,19 +148,31 @@ func (stateDB *EVMStateDBAdapter) GetBalance(evmAddr common.Address) *big.Int {
 // GetNonce gets the nonce of account
 func (stateDB *EVMStateDBAdapter) GetNonce(evmAddr common.Address) uint64 {
 	addr := address.New(stateDB.bc.ChainID(), evmAddr.Bytes())
-	nonce, err := stateDB.ws.Nonce(addr.IotxAddress())
+	state, err := stateDB.ws.CachedState(addr.IotxAddress())
 	if err != nil {
 		logger.Error().Err(err).Msg("GetNonce")
 		// stateDB.logError(err)
 		return 0
 	}
-	logger.Debug().Uint64("nonce", nonce).Msg("GetNonce")
-	return nonce
+	logger.Debug().Uint64("nonce", state.Nonce).Msg("GetNonce")
+	return state.Nonce
 }
 
 // SetNonce sets the nonce of account
-func (stateDB *EVMStateDBAdapter) SetNonce(common.Address, uint64) {
-	logger.Error().Msg("SetNonce is not implemented")
+func (stateDB *EVMStateDBAdapter) SetNonce(evmAddr common.Address, nonce uint64) {
+	if stateDB.legacy {
+		return
+	}
+	addr := address.New(stateDB.bc.ChainID(), evmAddr.Bytes())
+	state, err := stateDB.ws.CachedState(addr.IotxAddress())
+	if err != nil {
+		logger.Error().Err(err).Msg("SetNonce")
+		stateDB.logError(err)
+		return
+	}
+	stateDB.journal = append(stateDB.journal, nonceChange{addr.IotxAddress(), state.Nonce})
+	state.Nonce = nonce
+	logger.Debug().Uint64("nonce", nonce).Msg("SetNonce")
 }
 
 // GetCodeHash gets the code hash of account
@@ -164,9 +204,13 @@ func (stateDB *EVMStateDBAdapter) GetCode(evmAddr common.Address) []byte {
 
 // SetCode sets the code saved in hash
 func (stateDB *EVMStateDBAdapter) SetCode(evmAddr common.Address, code []byte) {
-	if err := stateDB.ws.SetCode(byteutil.BytesTo20B(evmAddr[:]), code); err != nil {
+	addr := byteutil.BytesTo20B(evmAddr[:])
+	prevCode := stateDB.GetCode(evmAddr)
+	if err := stateDB.ws.SetCode(addr, code); err != nil {
 		logger.Error().Err(err).Msg("SetCode")
+		return
 	}
+	stateDB.journal = append(stateDB.journal, codeChange{addr, prevCode})
 	logger.Debug().Hex("code", code).Hex("hash", hash.Hash256b(code)[:]).Msg("SetCode")
 }
 
@@ -181,14 +225,15 @@ func (stateDB *EVMStateDBAdapter) GetCodeSize(evmAddr common.Address) int {
 }
 
 // AddRefund adds refund
-func (stateDB *EVMStateDBAdapter) AddRefund(uint64) {
-	logger.Error().Msg("AddRefund is not implemented")
+func (stateDB *EVMStateDBAdapter) AddRefund(gas uint64) {
+	stateDB.journal = append(stateDB.journal, refundChange{stateDB.refund})
+	stateDB.refund += gas
+	logger.Debug().Uint64("gas", gas).Uint64("refund", stateDB.refund).Msg("AddRefund")
 }
 
 // GetRefund gets refund
 func (stateDB *EVMStateDBAdapter) GetRefund() uint64 {
-	logger.Error().Msg("GetRefund is not implemented")
-	return 0
+	return stateDB.refund
 }
 
 // GetState gets state
@@ -206,23 +251,41 @@ func (stateDB *EVMStateDBAdapter) GetState(evmAddr common.Address, k common.Hash
 
 // SetState sets state
 func (stateDB *EVMStateDBAdapter) SetState(evmAddr common.Address, k, v common.Hash) {
-	if err := stateDB.ws.SetContractState(byteutil.BytesTo20B(evmAddr[:]), byteutil.BytesTo32B(k[:]), byteutil.BytesTo32B(v[:])); err != nil {
+	addr := byteutil.BytesTo20B(evmAddr[:])
+	key := byteutil.BytesTo32B(k[:])
+	prevValue, err := stateDB.ws.GetContractState(addr, key)
+	existed := err == nil
+	if err := stateDB.ws.SetContractState(addr, key, byteutil.BytesTo32B(v[:])); err != nil {
 		logger.Error().Err(err).Msg("SetState")
 		return
 	}
+	stateDB.journal = append(stateDB.journal, storageChange{addr, key, prevValue, existed})
 	logger.Debug().Hex("addrHash", evmAddr[:]).Hex("k", k[:]).Hex("v", v[:]).Msg("SetState")
 }
 
-// Suicide kills the contract
-func (stateDB *EVMStateDBAdapter) Suicide(common.Address) bool {
-	logger.Error().Msg("Suicide is not implemented")
-	return false
+// Suicide kills the contract, its balance is cleared at once, and the account is deleted when the execution succeeds
+func (stateDB *EVMStateDBAdapter) Suicide(evmAddr common.Address) bool {
+	if stateDB.legacy {
+		return false
+	}
+	addr := address.New(stateDB.bc.ChainID(), evmAddr.Bytes())
+	state, err := stateDB.ws.CachedState(addr.IotxAddress())
+	if err != nil {
+		logger.Debug().Err(err).Msg("Suicide")
+		return false
+	}
+	_, suicided := stateDB.suicided[evmAddr]
+	stateDB.journal = append(stateDB.journal, suicideChange{evmAddr, suicided, state.Balance})
+	stateDB.suicided[evmAddr] = struct{}{}
+	state.Balance = big.NewInt(0)
+	logger.Debug().Hex("addrHash", evmAddr[:]).Msg("Suicide")
+	return true
 }
 
 // HasSuicided returns whether the contract has been killed
-func (stateDB *EVMStateDBAdapter) HasSuicided(common.Address) bool {
-	logger.Error().Msg("HasSuicide is not implemented")
-	return false
+func (stateDB *EVMStateDBAdapter) HasSuicided(evmAddr common.Address) bool {
+	_, ok := stateDB.suicided[evmAddr]
+	return ok
 }
 
 // Exist checks the existence of an address
@@ -236,21 +299,48 @@ func (stateDB *EVMStateDBAdapter) Exist(evmAddr common.Address) bool {
 	return true
 }
 
-// Empty empties the contract
-func (stateDB *EVMStateDBAdapter) Empty(common.Address) bool {
-	logger.Debug().Msg("Empty is not implemented")
-	return false
+// Empty returns whether the account does not exist, or has zero nonce, zero balance and no code
+func (stateDB *EVMStateDBAdapter) Empty(evmAddr common.Address) bool {
+	if stateDB.legacy {
+		return false
+	}
+	addr := address.New(stateDB.bc.ChainID(), evmAddr.Bytes())
+	state, err := stateDB.ws.CachedState(addr.IotxAddress())
+	if err != nil {
+		return true
+	}
+	return state.Nonce == 0 && state.Balance.Sign() == 0 && len(state.CodeHash) == 0
 }
 
-// RevertToSnapshot reverts the state factory to snapshot
-func (stateDB *EVMStateDBAdapter) RevertToSnapshot(int) {
-	logger.Debug().Msg("RevertToSnapshot is not implemented")
+// RevertToSnapshot reverts all the changes made since the snapshot was taken, in the reverse order
+func (stateDB *EVMStateDBAdapter) RevertToSnapshot(snapshot int) {
+	idx := sort.Search(len(stateDB.revisions), func(i int) bool {
+		return stateDB.revisions[i].id >= snapshot
+	})
+	if idx == len(stateDB.revisions) || stateDB.revisions[idx].id != snapshot {
+		err := errors.Errorf("snapshot %d cannot be reverted", snapshot)
+		logger.Error().Err(err).Msg("RevertToSnapshot")
+		stateDB.logError(err)
+		return
+	}
+	journalIndex := stateDB.revisions[idx].journalIndex
+	for i := len(stateDB.journal) - 1; i >= journalIndex; i-- {
+		if err := stateDB.journal[i].revert(stateDB); err != nil {
+			logger.Error().Err(err).Msg("RevertToSnapshot")
+			stateDB.logError(err)
+		}
+	}
+	stateDB.journal = stateDB.journal[:journalIndex]
+	stateDB.revisions = stateDB.revisions[:idx]
+	logger.Debug().Int("snapshot", snapshot).Msg("RevertToSnapshot")
 }
 
-// Snapshot returns the snapshot id
+// Snapshot returns the snapshot id, which could be used to revert the changes made afterwards
 func (stateDB *EVMStateDBAdapter) Snapshot() int {
-	logger.Debug().Msg("Snapshot is not implemented")
-	return 0
+	id := stateDB.nextRevisionID
+	stateDB.nextRevisionID++
+	stateDB.revisions = append(stateDB.revisions, revision{id, len(stateDB.journal)})
+	return id
 }
 
 // AddLog adds log
@@ -273,6 +363,7 @@ func (stateDB *EVMStateDBAdapter) AddLog(evmLog *types.Log) {
 		stateDB.executionIndex,
 	}
 	stateDB.logs = append(stateDB.logs, log)
+	stateDB.journal = append(stateDB.journal, addLogChange{})
 }
 
 // Logs returns the logs
@@ -280,12 +371,43 @@ func (stateDB *EVMStateDBAdapter) Logs() []*Log {
 	return stateDB.logs
 }
 
-// AddPreimage adds the preimage
-func (stateDB *EVMStateDBAdapter) AddPreimage(common.Hash, []byte) {
-	logger.Error().Msg("AddPreimage is not implemented")
+// AddPreimage adds the preimage of a hash
+func (stateDB *EVMStateDBAdapter) AddPreimage(h common.Hash, preimage []byte) {
+	if _, ok := stateDB.preimages[h]; ok {
+		return
+	}
+	stateDB.journal = append(stateDB.journal, addPreimageChange{h})
+	b := make([]byte, len(preimage))
+	copy(b, preimage)
+	stateDB.preimages[h] = b
+}
+
+// Preimages returns the preimages added
+func (stateDB *EVMStateDBAdapter) Preimages() map[common.Hash][]byte {
+	return stateDB.preimages
 }
 
-// ForEachStorage loops each storage
-func (stateDB *EVMStateDBAdapter) ForEachStorage(common.Address, func(common.Hash, common.Hash) bool) {
-	logger.Error().Msg("ForEachStorage is not implemented")
+// ForEachStorage loops each storage of the contract, until cb returns false
+func (stateDB *EVMStateDBAdapter) ForEachStorage(evmAddr common.Address, cb func(common.Hash, common.Hash) bool) {
+	err := stateDB.ws.IterateContractState(byteutil.BytesTo20B(evmAddr[:]), func(k []byte, v []byte) error {
+		if !cb(common.BytesToHash(k), common.BytesToHash(v)) {
+			return errStopIteration
+		}
+		return nil
+	})
+	if err != nil && err != errStopIteration {
+		logger.Error().Err(err).Msg("ForEachStorage")
+	}
+}
+
+// deleteSuicided deletes the accounts of the contracts killed in the execution
+func (stateDB *EVMStateDBAdapter) deleteSuicided() {
+	for evmAddr := range stateDB.suicided {
+		addr := address.New(stateDB.bc.ChainID(), evmAddr.Bytes())
+		if err := stateDB.ws.DeleteAccount(addr.IotxAddress()); err != nil {
+			logger.Error().Err(err).Msg("deleteSuicided")
+			stateDB.logError(err)
+		}
+	}
+	stateDB.suicided = make(map[common.Address]struct{})
 }
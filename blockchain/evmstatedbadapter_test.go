@@ -0,0 +1,226 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/CoderZhi/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+func TestEVMStateDBAdapter_RevertToSnapshot(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	cfg := config.Default
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	ws, err := bc.GetFactory().NewWorkingSet()
+	require.NoError(err)
+	stateDB := NewEVMStateDBAdapter(bc, ws, 1, hash.ZeroHash32B, 0, hash.ZeroHash32B)
+
+	addr1 := common.BytesToAddress(hash.Hash160b([]byte("alfa")))
+	addr2 := common.BytesToAddress(hash.Hash160b([]byte("bravo")))
+	addr3 := common.BytesToAddress(hash.Hash160b([]byte("charlie")))
+	k1 := common.BytesToHash(hash.Hash256b([]byte("cat")))
+	k2 := common.BytesToHash(hash.Hash256b([]byte("dog")))
+	v1 := common.BytesToHash(hash.Hash256b([]byte("cow")))
+	v2 := common.BytesToHash(hash.Hash256b([]byte("pig")))
+	code := []byte("test contract code")
+
+	stateDB.CreateAccount(addr1)
+	stateDB.AddBalance(addr1, big.NewInt(100))
+	stateDB.CreateAccount(addr2)
+	stateDB.SetCode(addr2, code)
+	stateDB.SetState(addr2, k1, v1)
+	stateDB.AddLog(&types.Log{Address: addr2})
+
+	snapshot := stateDB.Snapshot()
+	stateDB.SubBalance(addr1, big.NewInt(30))
+	stateDB.AddBalance(addr2, big.NewInt(30))
+	stateDB.SetState(addr2, k1, v2)
+	stateDB.SetState(addr2, k2, v2)
+	stateDB.AddLog(&types.Log{Address: addr2})
+	stateDB.CreateAccount(addr3)
+	stateDB.SetCode(addr3, code)
+	stateDB.SetState(addr3, k1, v1)
+	inner := stateDB.Snapshot()
+	stateDB.AddBalance(addr1, big.NewInt(1))
+	require.Equal(big.NewInt(71), stateDB.GetBalance(addr1))
+	stateDB.RevertToSnapshot(inner)
+	require.Equal(big.NewInt(70), stateDB.GetBalance(addr1))
+	require.Equal(big.NewInt(30), stateDB.GetBalance(addr2))
+	require.Equal(v2, stateDB.GetState(addr2, k1))
+	require.Equal(2, len(stateDB.Logs()))
+	require.True(stateDB.Exist(addr3))
+	require.Equal(code, stateDB.GetCode(addr3))
+
+	stateDB.RevertToSnapshot(snapshot)
+	require.Equal(big.NewInt(100), stateDB.GetBalance(addr1))
+	require.Equal(big.NewInt(0), stateDB.GetBalance(addr2))
+	require.Equal(v1, stateDB.GetState(addr2, k1))
+	require.Equal(common.Hash{}, stateDB.GetState(addr2, k2))
+	require.Equal(code, stateDB.GetCode(addr2))
+	require.Equal(1, len(stateDB.Logs()))
+	require.False(stateDB.Exist(addr3))
+	require.NoError(stateDB.Error())
+
+	// the code of an existing account is removed on revert
+	snapshot = stateDB.Snapshot()
+	stateDB.SetCode(addr1, code)
+	require.Equal(code, stateDB.GetCode(addr1))
+	stateDB.RevertToSnapshot(snapshot)
+	require.Nil(stateDB.GetCode(addr1))
+	require.Equal(common.Hash{}, stateDB.GetCodeHash(addr1))
+	require.True(stateDB.Exist(addr1))
+	require.NoError(stateDB.Error())
+
+	// a snapshot could only be reverted once
+	stateDB.RevertToSnapshot(snapshot)
+	require.Error(stateDB.Error())
+}
+
+func TestEVMStateDBAdapter_Suicide(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	cfg := config.Default
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	ws, err := bc.GetFactory().NewWorkingSet()
+	require.NoError(err)
+	stateDB := NewEVMStateDBAdapter(bc, ws, 1, hash.ZeroHash32B, 0, hash.ZeroHash32B)
+
+	addr1 := common.BytesToAddress(hash.Hash160b([]byte("alfa")))
+	addr2 := common.BytesToAddress(hash.Hash160b([]byte("bravo")))
+	k1 := common.BytesToHash(hash.Hash256b([]byte("cat")))
+	k2 := common.BytesToHash(hash.Hash256b([]byte("dog")))
+	v1 := common.BytesToHash(hash.Hash256b([]byte("cow")))
+	v2 := common.BytesToHash(hash.Hash256b([]byte("pig")))
+
+	require.True(stateDB.Empty(addr1))
+	stateDB.CreateAccount(addr1)
+	require.True(stateDB.Empty(addr1))
+	stateDB.SetNonce(addr1, 2)
+	require.Equal(uint64(2), stateDB.GetNonce(addr1))
+	require.False(stateDB.Empty(addr1))
+	stateDB.CreateAccount(addr2)
+	stateDB.SetCode(addr2, []byte("test contract code"))
+	require.False(stateDB.Empty(addr2))
+	stateDB.AddBalance(addr2, big.NewInt(10))
+	stateDB.SetState(addr2, k1, v1)
+	stateDB.SetState(addr2, k2, v2)
+	storage := make(map[common.Hash]common.Hash)
+	stateDB.ForEachStorage(addr2, func(k common.Hash, v common.Hash) bool {
+		storage[k] = v
+		return true
+	})
+	require.Equal(map[common.Hash]common.Hash{k1: v1, k2: v2}, storage)
+	count := 0
+	stateDB.ForEachStorage(addr2, func(common.Hash, common.Hash) bool {
+		count++
+		return false
+	})
+	require.Equal(1, count)
+
+	snapshot := stateDB.Snapshot()
+	stateDB.SetNonce(addr1, 3)
+	stateDB.AddRefund(100)
+	stateDB.AddPreimage(k1, []byte("cat"))
+	require.True(stateDB.Suicide(addr2))
+	require.True(stateDB.HasSuicided(addr2))
+	require.Equal(big.NewInt(0), stateDB.GetBalance(addr2))
+	require.Equal(uint64(100), stateDB.GetRefund())
+	require.Equal(1, len(stateDB.Preimages()))
+	stateDB.RevertToSnapshot(snapshot)
+	require.Equal(uint64(2), stateDB.GetNonce(addr1))
+	require.Equal(uint64(0), stateDB.GetRefund())
+	require.Equal(0, len(stateDB.Preimages()))
+	require.False(stateDB.HasSuicided(addr2))
+	require.Equal(big.NewInt(10), stateDB.GetBalance(addr2))
+
+	_, err = ws.RunActions(1, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(bc.GetFactory().Commit(ws))
+
+	// the killed contract is deleted from the state trie
+	ws, err = bc.GetFactory().NewWorkingSet()
+	require.NoError(err)
+	stateDB = NewEVMStateDBAdapter(bc, ws, 2, hash.ZeroHash32B, 0, hash.ZeroHash32B)
+	require.True(stateDB.Exist(addr2))
+	require.True(stateDB.Suicide(addr2))
+	require.False(stateDB.Suicide(common.BytesToAddress(hash.Hash160b([]byte("charlie")))))
+	stateDB.deleteSuicided()
+	require.False(stateDB.HasSuicided(addr2))
+	require.False(stateDB.Exist(addr2))
+	require.True(stateDB.Exist(addr1))
+	require.NoError(stateDB.Error())
+	_, err = ws.RunActions(2, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(bc.GetFactory().Commit(ws))
+	_, err = bc.GetFactory().GetCodeHash(byteutil.BytesTo20B(addr2[:]))
+	require.Error(err)
+	_, err = bc.GetFactory().GetCodeHash(byteutil.BytesTo20B(addr1[:]))
+	require.NoError(err)
+}
+
+func TestEVMStateDBAdapter_Legacy(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	cfg := config.Default
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	addr := common.BytesToAddress(hash.Hash160b([]byte("alfa")))
+	run := func(legacy bool, execute func(*EVMStateDBAdapter)) hash.Hash32B {
+		ws, err := bc.GetFactory().NewWorkingSet()
+		require.NoError(err)
+		stateDB := NewEVMStateDBAdapter(bc, ws, 1, hash.ZeroHash32B, 0, hash.ZeroHash32B)
+		stateDB.legacy = legacy
+		stateDB.CreateAccount(addr)
+		stateDB.SetCode(addr, []byte("test contract code"))
+		stateDB.AddBalance(addr, big.NewInt(10))
+		execute(stateDB)
+		require.NoError(stateDB.Error())
+		root, err := ws.RunActions(1, nil, nil, nil, nil)
+		require.NoError(err)
+		return root
+	}
+	// the execution in a legacy block leaves the root it had before nonces and killing contracts were introduced
+	execute := func(stateDB *EVMStateDBAdapter) {
+		require.True(stateDB.Exist(addr))
+		require.False(stateDB.Empty(addr))
+		stateDB.SetNonce(addr, 1)
+		require.Equal(uint64(0), stateDB.GetNonce(addr))
+		require.False(stateDB.Suicide(addr))
+		require.False(stateDB.HasSuicided(addr))
+	}
+	original := run(true, func(*EVMStateDBAdapter) {})
+	require.Equal(original, run(true, execute))
+	require.NotEqual(original, run(false, func(stateDB *EVMStateDBAdapter) {
+		stateDB.SetNonce(addr, 1)
+		require.True(stateDB.Suicide(addr))
+		stateDB.deleteSuicided()
+	}))
+}
@@ -7,13 +7,40 @@
 package blockchain
 
 import (
+	"github.com/CoderZhi/go-ethereum/core/vm"
 	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
 
+	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/state"
 )
 
-// Receipt represents the result of a contract
+const (
+	// NoErrorCode is the error code of the receipt of a successful action
+	NoErrorCode = uint64(iota)
+	// UnknownErrorCode is the error code of the receipt of an action failed for other reasons
+	UnknownErrorCode
+	// NotEnoughBalanceErrorCode is the error code of the receipt of an action transferring more than the balance
+	NotEnoughBalanceErrorCode
+	// InconsistentNonceErrorCode is the error code of the receipt of an execution with a stale nonce
+	InconsistentNonceErrorCode
+	// HitGasLimitErrorCode is the error code of the receipt of an execution exceeding the block gas limit
+	HitGasLimitErrorCode
+	// InsufficientBalanceForGasErrorCode is the error code of the receipt of an execution whose executor cannot afford
+	// the gas
+	InsufficientBalanceForGasErrorCode
+	// OutOfGasErrorCode is the error code of the receipt of an execution running out of gas
+	OutOfGasErrorCode
+)
+
+// receiptVersion is the version of the byte stream of receipts, which is bumped whenever a field is added to it
+const receiptVersion byte = 1
+
+// Receipt represents the result of an action
 type Receipt struct {
 	ReturnValue     []byte
 	Status          uint64
@@ -21,6 +48,7 @@ type Receipt struct {
 	GasConsumed     uint64
 	ContractAddress string
 	Logs            []*Log
+	ErrorCode       uint64
 }
 
 // Log stores an evm contract event
@@ -46,6 +74,7 @@ func (receipt *Receipt) ConvertToReceiptPb() *iproto.ReceiptPb {
 	for _, log := range receipt.Logs {
 		r.Logs = append(r.Logs, log.ConvertToLogPb())
 	}
+	r.ErrorCode = receipt.ErrorCode
 	return r
 }
 
@@ -62,6 +91,7 @@ func (receipt *Receipt) ConvertFromReceiptPb(pbReceipt *iproto.ReceiptPb) {
 		receipt.Logs[i] = &Log{}
 		receipt.Logs[i].ConvertFromLogPb(log)
 	}
+	receipt.ErrorCode = pbReceipt.GetErrorCode()
 }
 
 // Serialize returns a serialized byte stream for the Receipt
@@ -79,13 +109,61 @@ func (receipt *Receipt) Deserialize(buf []byte) error {
 	return nil
 }
 
+// ByteStream returns a raw byte stream of the receipt, which starts with the version of the encoding, and every
+// variable-size field is prefixed by its size. The block number, block hash and execution hash of logs are not
+// included, because the block hash is not final yet when the receipt is produced in minting a block
+func (receipt *Receipt) ByteStream() []byte {
+	stream := []byte{receiptVersion}
+	stream = append(stream, receipt.Hash[:]...)
+	stream = append(stream, byteutil.Uint64ToBytes(receipt.Status)...)
+	stream = append(stream, byteutil.Uint64ToBytes(receipt.GasConsumed)...)
+	stream = appendSizePrefixed(stream, []byte(receipt.ContractAddress))
+	stream = appendSizePrefixed(stream, receipt.ReturnValue)
+	stream = append(stream, byteutil.Uint32ToBytes(uint32(len(receipt.Logs)))...)
+	for _, log := range receipt.Logs {
+		stream = appendSizePrefixed(stream, []byte(log.Address))
+		stream = append(stream, byteutil.Uint32ToBytes(uint32(len(log.Topics)))...)
+		for _, topic := range log.Topics {
+			stream = append(stream, topic[:]...)
+		}
+		stream = appendSizePrefixed(stream, log.Data)
+		stream = append(stream, byteutil.Uint64ToBytes(uint64(log.Index))...)
+	}
+	stream = append(stream, byteutil.Uint64ToBytes(receipt.ErrorCode)...)
+	return stream
+}
+
+// HashReceipt returns the hash of the receipt, which is the leaf of the receipt Merkle tree of the block
+func (receipt *Receipt) HashReceipt() hash.Hash32B {
+	return blake2b.Sum256(receipt.ByteStream())
+}
+
+// errorCode returns the error code in the receipt of an action failed with the error
+func errorCode(err error) uint64 {
+	switch errors.Cause(err) {
+	case nil:
+		return NoErrorCode
+	case state.ErrNotEnoughBalance, vm.ErrInsufficientBalance:
+		return NotEnoughBalanceErrorCode
+	case ErrInconsistentNonce:
+		return InconsistentNonceErrorCode
+	case action.ErrHitGasLimit:
+		return HitGasLimitErrorCode
+	case action.ErrInsufficientBalanceForGas:
+		return InsufficientBalanceForGasErrorCode
+	case action.ErrOutOfGas, vm.ErrOutOfGas:
+		return OutOfGasErrorCode
+	}
+	return UnknownErrorCode
+}
+
 // ConvertToLogPb converts a Log to protobuf's LogPb
 func (log *Log) ConvertToLogPb() *iproto.LogPb {
 	l := &iproto.LogPb{}
 	l.Address = log.Address
 	l.Topics = [][]byte{}
-	for _, topic := range log.Topics {
-		l.Topics = append(l.Topics, topic[:])
+	for i := range log.Topics {
+		l.Topics = append(l.Topics, log.Topics[i][:])
 	}
 	l.Data = log.Data
 	l.BlockNumber = log.BlockNumber
@@ -124,3 +202,9 @@ func (log *Log) Deserialize(buf []byte) error {
 	log.ConvertFromLogPb(pbLog)
 	return nil
 }
+
+// appendSizePrefixed appends the data prefixed by its size to the stream
+func appendSizePrefixed(stream []byte, data []byte) []byte {
+	stream = append(stream, byteutil.Uint32ToBytes(uint32(len(data)))...)
+	return append(stream, data...)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"math/big"
+
+	"github.com/CoderZhi/go-ethereum/core/vm"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// ExecutionTrace is the opcode-level trace of an execution, along with the receipt of re-running it
+type ExecutionTrace struct {
+	Receipt *Receipt
+	Steps   []*TraceStep
+}
+
+// TraceStep is the state of evm right before running an opcode
+type TraceStep struct {
+	PC      uint64
+	Op      string
+	Gas     uint64
+	GasCost uint64
+	Depth   int
+	Stack   []*big.Int
+	Memory  []byte
+	Storage map[hash.Hash32B]hash.Hash32B
+	Err     string
+}
+
+// traceExecution re-runs the executions of the block up to the one with the given hash on the working set, and returns
+// the trace of that execution. The working set must have the states right before the block
+func traceExecution(
+	blk *Block,
+	ws state.WorkingSet,
+	bc Blockchain,
+	h hash.Hash32B,
+	cfg *config.Config,
+) (*ExecutionTrace, error) {
+	gasLimit := cfg.Chain.BlockGasLimit
+	for idx, execution := range blk.Executions {
+		if execution.Hash() != h {
+			// the executions before the traced one only change the states
+			executeContract(blk, ws, idx, execution, bc, cfg, &gasLimit, nil)
+			continue
+		}
+		structLogger := vm.NewStructLogger(nil)
+		receipt, err := executeContract(blk, ws, idx, execution, bc, cfg, &gasLimit, structLogger)
+		if receipt == nil {
+			return nil, errors.Wrapf(err, "failed to re-run execution %x", h)
+		}
+		trace := &ExecutionTrace{Receipt: receipt, Steps: []*TraceStep{}}
+		for _, log := range structLogger.StructLogs() {
+			step := &TraceStep{
+				PC:      log.Pc,
+				Op:      log.OpName(),
+				Gas:     log.Gas,
+				GasCost: log.GasCost,
+				Depth:   log.Depth,
+				Stack:   log.Stack,
+				Memory:  log.Memory,
+				Storage: make(map[hash.Hash32B]hash.Hash32B),
+				Err:     log.ErrorString(),
+			}
+			for k, v := range log.Storage {
+				step.Storage[hash.Hash32B(k)] = hash.Hash32B(v)
+			}
+			trace.Steps = append(trace.Steps, step)
+		}
+		return trace, nil
+	}
+	return nil, errors.Errorf("block %x does not have execution %x", blk.HashBlock(), h)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// putActionReceipts records a receipt for each action other than executions in the block. The action consumes its
+// intrinsic gas if gasFee is set, in which case the gas fees are charged by the working set right before running the
+// actions, or no gas otherwise. The actions in genesis block have no receipt as the genesis header has no receipt root,
+// and neither do coinbase transfers as their hashes are not unique across blocks
+func putActionReceipts(blk *Block, gasFee bool) error {
+	if blk.Height() == 0 {
+		return nil
+	}
+	if blk.receipts == nil {
+		blk.receipts = make(map[hash.Hash32B]*Receipt)
+	}
+	for _, act := range blk.allActions() {
+		if _, ok := act.(*action.Execution); ok {
+			continue
+		}
+		if tsf, ok := act.(*action.Transfer); ok && tsf.IsCoinbase() {
+			continue
+		}
+		var gas uint64
+		if gasFee {
+			var err error
+			if gas, err = ActionGas(act); err != nil {
+				return errors.Wrapf(err, "failed to get intrinsic gas of action %x", act.Hash())
+			}
+		}
+		blk.receipts[act.Hash()] = &Receipt{
+			Status:      SuccessStatus,
+			Hash:        act.Hash(),
+			GasConsumed: gas,
+			ErrorCode:   NoErrorCode,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestChargeGasFees(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	alfa := ta.Addrinfo["alfa"]
+	bravo := ta.Addrinfo["bravo"]
+	charlie := ta.Addrinfo["charlie"]
+	producer := ta.Addrinfo["producer"]
+	_, err := bc.CreateState(alfa.RawAddress, 1000000)
+	require.NoError(err)
+	_, err = bc.CreateState(bravo.RawAddress, 1000000)
+	require.NoError(err)
+	_, err = bc.CreateState(producer.RawAddress, 0)
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(bc.GetFactory().Commit(nil))
+	producerBalance := func() *big.Int {
+		balance, err := bc.Balance(producer.RawAddress)
+		require.NoError(err)
+		return balance
+	}
+	commit := func(tsfs []*action.Transfer, votes []*action.Vote) *Block {
+		blk, err := bc.MintNewBlock(tsfs, votes, nil, nil, producer, "")
+		require.NoError(err)
+		require.NoError(bc.ValidateBlock(blk, true))
+		require.NoError(bc.CommitBlock(blk))
+		return blk
+	}
+
+	// alfa nominates itself, and bravo votes for alfa
+	vote1, err := action.NewVote(1, alfa.RawAddress, alfa.RawAddress, uint64(100000), big.NewInt(3))
+	require.NoError(err)
+	require.NoError(action.Sign(vote1, alfa.PrivateKey))
+	vote2, err := action.NewVote(1, bravo.RawAddress, alfa.RawAddress, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(action.Sign(vote2, bravo.PrivateKey))
+	before := producerBalance()
+	blk := commit(nil, []*action.Vote{vote1, vote2})
+	voteFee := big.NewInt(3 * int64(action.VoteIntrinsicGas))
+	require.Equal(new(big.Int).Add(before, voteFee), new(big.Int).Sub(producerBalance(), blk.Transfers[0].Amount()))
+	balance, err := bc.Balance(alfa.RawAddress)
+	require.NoError(err)
+	require.Equal(new(big.Int).Sub(big.NewInt(1000000), voteFee), balance)
+	for _, vote := range []*action.Vote{vote1, vote2} {
+		receipt, err := bc.GetReceiptByActionHash(vote.Hash())
+		require.NoError(err)
+		require.Equal(SuccessStatus, receipt.Status)
+		require.Equal(action.VoteIntrinsicGas, receipt.GasConsumed)
+	}
+	require.NotEqual(hash.ZeroHash32B, blk.Header.ReceiptRoot())
+
+	// bravo pays the transfer fee, which is deducted from the voting weight of alfa as well
+	tsf, err := action.NewTransfer(2, big.NewInt(100), bravo.RawAddress, charlie.RawAddress, []byte("hi"),
+		uint64(100000), big.NewInt(2))
+	require.NoError(err)
+	require.NoError(action.Sign(tsf, bravo.PrivateKey))
+	tsfGas, err := tsf.IntrinsicGas()
+	require.NoError(err)
+	before = producerBalance()
+	blk = commit([]*action.Transfer{tsf}, nil)
+	tsfFee := big.NewInt(2 * int64(tsfGas))
+	require.Equal(new(big.Int).Add(before, tsfFee), new(big.Int).Sub(producerBalance(), blk.Transfers[1].Amount()))
+	cost, err := tsf.Cost()
+	require.NoError(err)
+	bravoState, err := bc.StateByAddr(bravo.RawAddress)
+	require.NoError(err)
+	require.Equal(new(big.Int).Sub(big.NewInt(1000000), cost), bravoState.Balance)
+	alfaState, err := bc.StateByAddr(alfa.RawAddress)
+	require.NoError(err)
+	require.Equal(bravoState.Balance, alfaState.VotingWeight)
+	receipt, err := bc.GetReceiptByActionHash(tsf.Hash())
+	require.NoError(err)
+	require.Equal(tsfGas, receipt.GasConsumed)
+	// coinbase transfer is free of gas fee
+	_, err = bc.GetReceiptByActionHash(blk.Transfers[1].Hash())
+	require.Error(err)
+
+	// charlie cannot afford the fee of sending all its balance, which fails and pays its balance as the fee
+	tsf, err = action.NewTransfer(1, big.NewInt(100), charlie.RawAddress, alfa.RawAddress, []byte{},
+		uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	require.NoError(action.Sign(tsf, charlie.PrivateKey))
+	before = producerBalance()
+	blk = commit([]*action.Transfer{tsf}, nil)
+	require.Equal(new(big.Int).Add(before, big.NewInt(100)), new(big.Int).Sub(producerBalance(), blk.Transfers[1].Amount()))
+	receipt, err = bc.GetReceiptByActionHash(tsf.Hash())
+	require.NoError(err)
+	require.Equal(FailureStatus, receipt.Status)
+	require.Equal(NotEnoughBalanceErrorCode, receipt.ErrorCode)
+	charlieState, err := bc.StateByAddr(charlie.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(0), charlieState.Balance)
+	require.Equal(uint64(1), charlieState.Nonce)
+
+	// the fee of charlie is charged after charlie is funded by the transfer before in the same block
+	tsf1, err := action.NewTransfer(2, big.NewInt(20000), alfa.RawAddress, charlie.RawAddress, []byte{},
+		uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	require.NoError(action.Sign(tsf1, alfa.PrivateKey))
+	tsf2, err := action.NewTransfer(2, big.NewInt(1), charlie.RawAddress, alfa.RawAddress, []byte{},
+		uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	require.NoError(action.Sign(tsf2, charlie.PrivateKey))
+	tsf2Gas, err := tsf2.IntrinsicGas()
+	require.NoError(err)
+	commit([]*action.Transfer{tsf1, tsf2}, nil)
+	for _, tsf := range []*action.Transfer{tsf1, tsf2} {
+		receipt, err := bc.GetReceiptByActionHash(tsf.Hash())
+		require.NoError(err)
+		require.Equal(SuccessStatus, receipt.Status)
+	}
+	balance, err = bc.Balance(charlie.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(int64(20000-1-tsf2Gas)), balance)
+
+	// bravo transfers more than its balance, which fails but still pays the fee
+	tsf, err = action.NewTransfer(3, big.NewInt(2000000), bravo.RawAddress, charlie.RawAddress, []byte{},
+		uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	require.NoError(action.Sign(tsf, bravo.PrivateKey))
+	tsfGas, err = tsf.IntrinsicGas()
+	require.NoError(err)
+	commit([]*action.Transfer{tsf}, nil)
+	receipt, err = bc.GetReceiptByActionHash(tsf.Hash())
+	require.NoError(err)
+	require.Equal(FailureStatus, receipt.Status)
+	require.Equal(NotEnoughBalanceErrorCode, receipt.ErrorCode)
+	require.Equal(tsfGas, receipt.GasConsumed)
+	newBravoState, err := bc.StateByAddr(bravo.RawAddress)
+	require.NoError(err)
+	require.Equal(new(big.Int).Sub(bravoState.Balance, big.NewInt(int64(tsfGas))), newBravoState.Balance)
+	require.Equal(uint64(3), newBravoState.Nonce)
+}
+
+func TestGasFeeHeight(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+	cfg.Chain.GasFeeHeight = 2
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	alfa := ta.Addrinfo["alfa"]
+	bravo := ta.Addrinfo["bravo"]
+	producer := ta.Addrinfo["producer"]
+	_, err := bc.CreateState(alfa.RawAddress, 1000000)
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(bc.GetFactory().Commit(nil))
+
+	// the transfer on height 1 is free of gas fee, and the one on height 2 pays it
+	for i, expected := range []int64{999900, 989800} {
+		nonce := uint64(i + 1)
+		tsf, err := action.NewTransfer(nonce, big.NewInt(100), alfa.RawAddress, bravo.RawAddress, []byte{},
+			uint64(100000), big.NewInt(1))
+		require.NoError(err)
+		require.NoError(action.Sign(tsf, alfa.PrivateKey))
+		blk, err := bc.MintNewBlock([]*action.Transfer{tsf}, nil, nil, nil, producer, "")
+		require.NoError(err)
+		require.Equal(nonce, blk.Height())
+		require.NoError(bc.ValidateBlock(blk, true))
+		require.NoError(bc.CommitBlock(blk))
+		balance, err := bc.Balance(alfa.RawAddress)
+		require.NoError(err)
+		require.Equal(big.NewInt(expected), balance)
+		// the transfers get receipts whether or not they pay the gas fee
+		receipt, err := bc.GetReceiptByActionHash(tsf.Hash())
+		require.NoError(err)
+		require.Equal(SuccessStatus, receipt.Status)
+		var gas uint64
+		if blk.Height() >= cfg.Chain.GasFeeHeight {
+			gas, err = tsf.IntrinsicGas()
+			require.NoError(err)
+		}
+		require.Equal(gas, receipt.GasConsumed)
+	}
+}
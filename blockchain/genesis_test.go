@@ -7,6 +7,7 @@
 package blockchain
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -37,4 +38,11 @@ func TestGenesis(t *testing.T) {
 	assert.Equal(uint64(0), genesisBlk.Header.height)
 	assert.Equal(uint64(1524676419), genesisBlk.Header.timestamp)
 	assert.Equal(expectedParentHash, genesisBlk.Header.prevBlockHash)
+
+	// the hash of the genesis block stays the same as the header is extended
+	genesisHash := genesisBlk.HashBlock()
+	assert.Equal(
+		"0bc996097d27138f5c3f3ff70ff6c39fea3adb874042c51595e13b7a1b1551bf",
+		hex.EncodeToString(genesisHash[:]),
+	)
 }
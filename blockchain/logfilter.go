@@ -0,0 +1,124 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// LogBloomSize is the size of the log bloom filter in bytes
+const LogBloomSize = 256
+
+// LogBloom is a bloom filter of the contract addresses and topics of the logs in a block, with which the blocks
+// without any log of interest are skipped in log queries
+type LogBloom [LogBloomSize]byte
+
+// Add adds a contract address or topic into the bloom filter
+func (b *LogBloom) Add(data []byte) {
+	for _, bit := range bloomBits(data) {
+		b[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Test returns false if the contract address or topic is definitely not in the bloom filter
+func (b *LogBloom) Test(data []byte) bool {
+	for _, bit := range bloomBits(data) {
+		if b[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomBits returns the 3 bits set in the bloom filter for the data, which are taken from the hash of the data
+func bloomBits(data []byte) []uint {
+	h := blake2b.Sum256(data)
+	bits := make([]uint, 3)
+	for i := range bits {
+		bits[i] = (uint(h[2*i])<<8 | uint(h[2*i+1])) % (LogBloomSize * 8)
+	}
+	return bits
+}
+
+// LogFilter is the criteria of a log query. A log matches the filter if it is emitted in the height range, by one of
+// the addresses, and for each position i, its i-th topic is one of the Topics[i]. Empty Addresses or Topics[i] matches
+// anything, and zero ToHeight means the tip height
+type LogFilter struct {
+	FromHeight uint64
+	ToHeight   uint64
+	Addresses  []string
+	Topics     [][]hash.Hash32B
+}
+
+// Match returns true if the log satisfies the address and topic criteria of the filter
+func (f *LogFilter) Match(log *Log) bool {
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, addr := range f.Addresses {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for i, topics := range f.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		if i >= len(log.Topics) {
+			return false
+		}
+		found := false
+		for _, topic := range topics {
+			if topic == log.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MayMatch returns false if no log in the block with the bloom filter could satisfy the filter
+func (f *LogFilter) MayMatch(bloom *LogBloom) bool {
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, addr := range f.Addresses {
+			if bloom.Test([]byte(addr)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, topics := range f.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range topics {
+			if bloom.Test(topic[:]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestLogBloom(t *testing.T) {
+	require := require.New(t)
+
+	var bloom LogBloom
+	require.False(bloom.Test([]byte("alfa")))
+	bloom.Add([]byte("alfa"))
+	topic := byteutil.BytesTo32B(hash.Hash256b([]byte("Transfer")))
+	bloom.Add(topic[:])
+	require.True(bloom.Test([]byte("alfa")))
+	require.True(bloom.Test(topic[:]))
+
+	filter := &LogFilter{Addresses: []string{"bravo", "alfa"}}
+	require.True(filter.MayMatch(&bloom))
+	filter.Topics = [][]hash.Hash32B{nil, {topic}}
+	require.True(filter.MayMatch(&bloom))
+	require.False((&LogFilter{Addresses: []string{"bravo"}}).MayMatch(&LogBloom{}))
+}
+
+func TestLogFilter_Match(t *testing.T) {
+	require := require.New(t)
+
+	t1 := byteutil.BytesTo32B(hash.Hash256b([]byte("Transfer")))
+	t2 := byteutil.BytesTo32B(hash.Hash256b([]byte("alfa")))
+	t3 := byteutil.BytesTo32B(hash.Hash256b([]byte("bravo")))
+	log := &Log{Address: "token", Topics: []hash.Hash32B{t1, t2, t3}}
+
+	require.True((&LogFilter{}).Match(log))
+	require.True((&LogFilter{Addresses: []string{"other", "token"}}).Match(log))
+	require.False((&LogFilter{Addresses: []string{"other"}}).Match(log))
+	require.True((&LogFilter{Topics: [][]hash.Hash32B{{t1}, nil, {t2, t3}}}).Match(log))
+	// topics are matched by position
+	require.False((&LogFilter{Topics: [][]hash.Hash32B{{t2}}}).Match(log))
+	require.False((&LogFilter{Topics: [][]hash.Hash32B{nil, nil, nil, {t1}}}).Match(log))
+}
+
+func TestGetLogs(t *testing.T) {
+	require := require.New(t)
+
+	alfa := testaddress.Addrinfo["alfa"].RawAddress
+	transfer := byteutil.BytesTo32B(hash.Hash256b([]byte("Transfer")))
+	approval := byteutil.BytesTo32B(hash.Hash256b([]byte("Approval")))
+	topicOf := func(addr string) hash.Hash32B { return byteutil.BytesTo32B(hash.Hash256b([]byte(addr))) }
+
+	test := func(explorerEnabled bool) {
+		ctx := context.Background()
+		cfg := config.Default
+		cfg.Explorer.Enabled = explorerEnabled
+		// block 1 is produced before the log bloom is introduced
+		cfg.Chain.ReceiptRootHeight = 2
+		dao := newBlockDAO(&cfg, db.NewMemKVStore())
+		require.NoError(dao.Start(ctx))
+		defer func() {
+			require.NoError(dao.Stop(ctx))
+		}()
+		bc := &blockchain{dao: dao, config: &cfg}
+
+		genesis := NewBlock(0, 0, hash.ZeroHash32B, 0, nil, nil, nil, nil)
+		require.NoError(dao.putBlock(genesis))
+
+		// the execution in block h emits a Transfer log of token1 if h is odd, or an Approval log of token2 otherwise
+		prevHash := genesis.HashBlock()
+		for h := uint64(1); h <= 5; h++ {
+			execution, err := action.NewExecution(alfa, action.EmptyAddress, h, big.NewInt(0), 100000, big.NewInt(0), nil)
+			require.NoError(err)
+			blk := NewBlock(0, h, prevHash, 0, nil, nil, []*action.Execution{execution}, nil)
+			log := &Log{Address: "token1", Topics: []hash.Hash32B{transfer, topicOf(alfa)}, TxnHash: execution.Hash()}
+			if h%2 == 0 {
+				log = &Log{Address: "token2", Topics: []hash.Hash32B{approval, topicOf(alfa)}, TxnHash: execution.Hash()}
+			}
+			blk.receipts = map[hash.Hash32B]*Receipt{
+				execution.Hash(): {Hash: execution.Hash(), Status: SuccessStatus, Logs: []*Log{log}},
+			}
+			if h >= cfg.Chain.ReceiptRootHeight {
+				blk.Header.logBloom = blk.LogBloom()
+			}
+			require.NoError(dao.putBlock(blk))
+			require.NoError(dao.putReceipts(blk))
+			prevHash = blk.HashBlock()
+			bc.tipHeight = h
+		}
+		heights := func(logs []*Log) []uint64 {
+			res := []uint64{}
+			for _, log := range logs {
+				res = append(res, log.BlockNumber)
+			}
+			return res
+		}
+
+		logs, err := bc.GetLogs(&LogFilter{})
+		require.NoError(err)
+		require.Equal([]uint64{1, 2, 3, 4, 5}, heights(logs))
+		logs, err = bc.GetLogs(&LogFilter{FromHeight: 2, ToHeight: 4, Addresses: []string{"token1"}})
+		require.NoError(err)
+		require.Equal([]uint64{3}, heights(logs))
+		blk, err := bc.GetBlockByHeight(3)
+		require.NoError(err)
+		require.Equal(blk.HashBlock(), logs[0].BlockHash)
+		require.Equal(blk.Executions[0].Hash(), logs[0].TxnHash)
+		logs, err = bc.GetLogs(&LogFilter{Topics: [][]hash.Hash32B{{approval}, {topicOf(alfa)}}})
+		require.NoError(err)
+		require.Equal([]uint64{2, 4}, heights(logs))
+		logs, err = bc.GetLogs(&LogFilter{Addresses: []string{"token1"}, Topics: [][]hash.Hash32B{{approval}}})
+		require.NoError(err)
+		require.Empty(logs)
+		// topics are matched by position
+		logs, err = bc.GetLogs(&LogFilter{Topics: [][]hash.Hash32B{{topicOf(alfa)}}})
+		require.NoError(err)
+		require.Empty(logs)
+		_, err = bc.GetLogs(&LogFilter{FromHeight: 6})
+		require.Error(err)
+		// the height range is capped
+		cfg.Explorer.MaxLogQueryRange = 3
+		_, err = bc.GetLogs(&LogFilter{FromHeight: 1, ToHeight: 4})
+		require.Error(err)
+		logs, err = bc.GetLogs(&LogFilter{FromHeight: 3})
+		require.NoError(err)
+		require.Equal([]uint64{3, 4, 5}, heights(logs))
+		cfg.Explorer.MaxLogQueryRange = config.Default.Explorer.MaxLogQueryRange
+
+		// the logs of the deleted tip block are removed from index
+		require.NoError(dao.deleteTipBlock())
+		bc.tipHeight = 4
+		logs, err = bc.GetLogs(&LogFilter{Addresses: []string{"token1"}})
+		require.NoError(err)
+		require.Equal([]uint64{1, 3}, heights(logs))
+		if explorerEnabled {
+			heights, err := dao.getLogHeights(logAddressKey("token1"), 0, 10)
+			require.NoError(err)
+			require.Equal([]uint64{1, 3}, heights)
+			heights, err = dao.getLogHeights(logTopicKey(topicOf(alfa)), 2, 3)
+			require.NoError(err)
+			require.Equal([]uint64{2, 3}, heights)
+		}
+	}
+
+	test(true)
+	test(false)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/CoderZhi/go-ethereum/core/vm"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
+)
+
+const (
+	// EC283VerifyGas is the gas of verifying an EC283 signature
+	EC283VerifyGas = uint64(20000)
+	// BLSVerifyGas is the gas of verifying a BLS signature
+	BLSVerifyGas = uint64(100000)
+	// BLSVerifyAggregateGas is the gas of verifying a BLS signature aggregated from the signature shares of a group
+	BLSVerifyAggregateGas = uint64(500000)
+	// Blake2bBaseGas is the base gas of hashing with blake2b
+	Blake2bBaseGas = uint64(60)
+	// Blake2bPerWordGas is the gas of hashing every 32 bytes of the input with blake2b
+	Blake2bPerWordGas = uint64(12)
+
+	ec283PubKeySize = 72
+	ec283SigSize    = 72
+	blsIDSize       = 32
+	blsPubKeySize   = 120
+	blsSigSize      = 20
+)
+
+var (
+	// EC283VerifyAddress is the address of the precompiled contract verifying an EC283 signature
+	EC283VerifyAddress = common.BytesToAddress([]byte{0x01, 0x01})
+	// BLSVerifyAddress is the address of the precompiled contract verifying a BLS signature
+	BLSVerifyAddress = common.BytesToAddress([]byte{0x01, 0x02})
+	// Blake2bAddress is the address of the precompiled contract hashing with blake2b
+	Blake2bAddress = common.BytesToAddress([]byte{0x01, 0x03})
+
+	// ErrPrecompileInput indicates the error of invalid input to a precompiled contract
+	ErrPrecompileInput = errors.New("invalid input of precompiled contract")
+
+	// iotexPrecompiles are the precompiled contracts of IoTeX, which are kept apart from the ones of go-ethereum
+	iotexPrecompiles = map[common.Address]vm.PrecompiledContract{
+		EC283VerifyAddress: &ec283Verify{},
+		BLSVerifyAddress:   &blsVerify{},
+		Blake2bAddress:     &blake2bHash{},
+	}
+)
+
+// precompilesAtHeight returns the precompiled contracts of IoTeX callable by the executions on the given height, which
+// are none below the precompile height
+func precompilesAtHeight(height uint64, precompileHeight uint64) map[common.Address]vm.PrecompiledContract {
+	if height < precompileHeight {
+		return nil
+	}
+	return iotexPrecompiles
+}
+
+// callPrecompiledContract calls the precompiled contract of the execution with the gas left, and returns the output
+// and the gas left after the call. The amount of the execution is transferred to the contract address as evm does
+func callPrecompiledContract(
+	ps *EVMParams,
+	stateDB vm.StateDB,
+	contract vm.PrecompiledContract,
+	gas uint64,
+) ([]byte, uint64, error) {
+	if ps.amount.Sign() > 0 {
+		if !ps.context.CanTransfer(stateDB, ps.context.Origin, ps.amount) {
+			return nil, gas, vm.ErrInsufficientBalance
+		}
+		if !stateDB.Exist(*ps.contract) {
+			stateDB.CreateAccount(*ps.contract)
+		}
+		ps.context.Transfer(stateDB, ps.context.Origin, *ps.contract, ps.amount)
+	}
+	requiredGas := contract.RequiredGas(ps.data)
+	if gas < requiredGas {
+		return nil, 0, vm.ErrOutOfGas
+	}
+	ret, err := contract.Run(ps.data)
+	return ret, gas - requiredGas, err
+}
+
+// ec283Verify verifies an EC283 signature. The input is the 32-byte hash of the message, followed by the public key
+// and the signature, and the output is a 32-byte word of 1 if the signature is valid, or 0 otherwise
+type ec283Verify struct{}
+
+// RequiredGas returns the gas of verifying the signature
+func (c *ec283Verify) RequiredGas(input []byte) uint64 {
+	return EC283VerifyGas
+}
+
+// Run verifies the signature
+func (c *ec283Verify) Run(input []byte) ([]byte, error) {
+	if len(input) != hash.HashSize+ec283PubKeySize+ec283SigSize {
+		return nil, errors.Wrapf(ErrPrecompileInput, "input of EC283 verify has %d bytes", len(input))
+	}
+	msg := input[:hash.HashSize]
+	pubKey, err := keypair.BytesToPublicKey(input[hash.HashSize : hash.HashSize+ec283PubKeySize])
+	if err != nil {
+		return nil, errors.Wrap(ErrPrecompileInput, err.Error())
+	}
+	sig := input[hash.HashSize+ec283PubKeySize:]
+	return verifyResult(crypto.EC283.Verify(pubKey, msg, sig)), nil
+}
+
+// blsVerify verifies a BLS signature. The input is the 32-byte hash of the message, followed by either the public key
+// and the signature, or the Degree+1 pairs of the ID and public key share of the signers in a group and the signature
+// aggregated from their signature shares. The output is a 32-byte word of 1 if the signature is valid, or 0 otherwise
+type blsVerify struct{}
+
+const (
+	blsVerifyInputSize          = hash.HashSize + blsPubKeySize + blsSigSize
+	blsVerifyAggregateInputSize = hash.HashSize + (crypto.Degree+1)*(blsIDSize+blsPubKeySize) + blsSigSize
+)
+
+// RequiredGas returns the gas of verifying the signature
+func (c *blsVerify) RequiredGas(input []byte) uint64 {
+	if len(input) == blsVerifyAggregateInputSize {
+		return BLSVerifyAggregateGas
+	}
+	return BLSVerifyGas
+}
+
+// Run verifies the signature
+func (c *blsVerify) Run(input []byte) ([]byte, error) {
+	switch len(input) {
+	case blsVerifyInputSize:
+		msg := input[:hash.HashSize]
+		pubKey := input[hash.HashSize : hash.HashSize+blsPubKeySize]
+		sig := input[hash.HashSize+blsPubKeySize:]
+		return verifyResult(crypto.BLS.Verify(pubKey, msg, sig) == nil), nil
+	case blsVerifyAggregateInputSize:
+		msg := input[:hash.HashSize]
+		ids := make([][]uint8, crypto.Degree+1)
+		pubKeys := make([][]byte, crypto.Degree+1)
+		for i := range ids {
+			offset := hash.HashSize + i*(blsIDSize+blsPubKeySize)
+			ids[i] = input[offset : offset+blsIDSize]
+			pubKeys[i] = input[offset+blsIDSize : offset+blsIDSize+blsPubKeySize]
+		}
+		sig := input[len(input)-blsSigSize:]
+		return verifyResult(crypto.BLS.VerifyAggregate(ids, pubKeys, msg, sig) == nil), nil
+	default:
+		return nil, errors.Wrapf(ErrPrecompileInput, "input of BLS verify has %d bytes", len(input))
+	}
+}
+
+// blake2bHash returns the 32-byte blake2b hash of the input
+type blake2bHash struct{}
+
+// RequiredGas returns the gas of hashing the input
+func (c *blake2bHash) RequiredGas(input []byte) uint64 {
+	return Blake2bBaseGas + uint64(len(input)+31)/32*Blake2bPerWordGas
+}
+
+// Run hashes the input
+func (c *blake2bHash) Run(input []byte) ([]byte, error) {
+	return hash.Hash256b(input), nil
+}
+
+func verifyResult(valid bool) []byte {
+	if valid {
+		return common.LeftPadBytes([]byte{1}, 32)
+	}
+	return make([]byte, 32)
+}
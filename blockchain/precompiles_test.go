@@ -0,0 +1,246 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/CoderZhi/go-ethereum/core/vm"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/address"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestPrecompiles(t *testing.T) {
+	require := require.New(t)
+
+	// the precompiled contracts of go-ethereum are left untouched
+	for addr := range iotexPrecompiles {
+		require.Nil(vm.PrecompiledContractsHomestead[addr])
+		require.Nil(vm.PrecompiledContractsByzantium[addr])
+	}
+	require.Nil(precompilesAtHeight(1, 2))
+	require.Equal(iotexPrecompiles, precompilesAtHeight(2, 2))
+	valid := make([]byte, 32)
+	valid[31] = 1
+	invalid := make([]byte, 32)
+	msg := hash.Hash256b([]byte("hello iotex"))
+	otherMsg := hash.Hash256b([]byte("hello ethereum"))
+
+	t.Run("EC283 verify", func(t *testing.T) {
+		contract := iotexPrecompiles[EC283VerifyAddress]
+		require.Equal(EC283VerifyGas, contract.RequiredGas(nil))
+		alfa := ta.Addrinfo["alfa"]
+		sig := crypto.EC283.Sign(alfa.PrivateKey, msg)
+		input := append(append(append([]byte{}, msg...), alfa.PublicKey[:]...), sig...)
+		output, err := contract.Run(input)
+		require.NoError(err)
+		require.Equal(valid, output)
+		input = append(append(append([]byte{}, otherMsg...), alfa.PublicKey[:]...), sig...)
+		output, err = contract.Run(input)
+		require.NoError(err)
+		require.Equal(invalid, output)
+		_, err = contract.Run(input[1:])
+		require.Equal(ErrPrecompileInput, errors.Cause(err))
+	})
+
+	t.Run("BLS verify", func(t *testing.T) {
+		contract := iotexPrecompiles[BLSVerifyAddress]
+		require.Equal(BLSVerifyGas, contract.RequiredGas(nil))
+		sk := crypto.DKG.SkGeneration()
+		pk, err := crypto.BLS.NewPubKey(sk)
+		require.NoError(err)
+		ok, sig, err := crypto.BLS.Sign(sk, msg)
+		require.NoError(err)
+		require.True(ok)
+		input := append(append(append([]byte{}, msg...), pk...), sig...)
+		output, err := contract.Run(input)
+		require.NoError(err)
+		require.Equal(valid, output)
+		input = append(append(append([]byte{}, otherMsg...), pk...), sig...)
+		output, err = contract.Run(input)
+		require.NoError(err)
+		require.Equal(invalid, output)
+		_, err = contract.Run(append(input, 0))
+		require.Equal(ErrPrecompileInput, errors.Cause(err))
+	})
+
+	t.Run("BLS verify aggregate", func(t *testing.T) {
+		contract := iotexPrecompiles[BLSVerifyAddress]
+		const numNodes = 21
+		idList := make([][]uint8, numNodes)
+		skList := make([][]uint32, numNodes)
+		sharesList := make([][][]uint32, numNodes)
+		shares := make([][]uint32, numNodes)
+		witnessesList := make([][][]byte, numNodes)
+		sharestatusmatrix := make([][numNodes]bool, numNodes)
+		pkList := make([][]byte, numNodes)
+		sigList := make([][]byte, numNodes)
+		for i := 0; i < numNodes; i++ {
+			idList[i] = crypto.RndGenerate()
+			skList[i] = crypto.DKG.SkGeneration()
+		}
+		var err error
+		for i := 0; i < numNodes; i++ {
+			_, sharesList[i], witnessesList[i], err = crypto.DKG.Init(skList[i], idList)
+			require.NoError(err)
+		}
+		for i := 0; i < numNodes; i++ {
+			for j := 0; j < numNodes; j++ {
+				shares[j] = sharesList[j][i]
+			}
+			sharestatusmatrix[i], err = crypto.DKG.SharesCollect(idList[i], shares, witnessesList)
+			require.NoError(err)
+		}
+		for i := 0; i < numNodes; i++ {
+			for j := 0; j < numNodes; j++ {
+				shares[j] = sharesList[j][i]
+			}
+			var ask []uint32
+			_, pkList[i], ask, err = crypto.DKG.KeyPairGeneration(shares, sharestatusmatrix)
+			require.NoError(err)
+			var ok bool
+			ok, sigList[i], err = crypto.BLS.SignShare(ask, msg)
+			require.NoError(err)
+			require.True(ok)
+		}
+		aggSig, err := crypto.BLS.SignAggregate(idList[:crypto.Degree+1], sigList[:crypto.Degree+1])
+		require.NoError(err)
+		aggInput := func(msg []byte) []byte {
+			input := append([]byte{}, msg...)
+			for i := 0; i < crypto.Degree+1; i++ {
+				input = append(append(input, idList[i]...), pkList[i]...)
+			}
+			return append(input, aggSig...)
+		}
+		input := aggInput(msg)
+		require.Equal(BLSVerifyAggregateGas, contract.RequiredGas(input))
+		output, err := contract.Run(input)
+		require.NoError(err)
+		require.Equal(valid, output)
+		output, err = contract.Run(aggInput(otherMsg))
+		require.NoError(err)
+		require.Equal(invalid, output)
+		_, err = contract.Run(input[1:])
+		require.Equal(ErrPrecompileInput, errors.Cause(err))
+	})
+
+	t.Run("blake2b", func(t *testing.T) {
+		contract := iotexPrecompiles[Blake2bAddress]
+		require.Equal(Blake2bBaseGas, contract.RequiredGas(nil))
+		require.Equal(Blake2bBaseGas+2*Blake2bPerWordGas, contract.RequiredGas(make([]byte, 33)))
+		output, err := contract.Run([]byte("hello iotex"))
+		require.NoError(err)
+		require.Equal(msg, output)
+	})
+}
+
+func TestPrecompilesInEVM(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+	cfg.Chain.PrecompileHeight = 3
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	producer := ta.Addrinfo["producer"]
+	_, err := bc.CreateState(producer.RawAddress, Gen.TotalSupply)
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(bc.GetFactory().Commit(nil))
+
+	nonce := uint64(0)
+	newExecution := func(contract string, data []byte) *action.Execution {
+		nonce++
+		execution, err := action.NewExecution(
+			producer.RawAddress, contract, nonce, big.NewInt(0), uint64(1000000), big.NewInt(10), data)
+		require.NoError(err)
+		require.NoError(action.Sign(execution, producer.PrivateKey))
+		return execution
+	}
+	commit := func(executions ...*action.Execution) []*Receipt {
+		blk, err := bc.MintNewBlock(nil, nil, executions, nil, producer, "")
+		require.NoError(err)
+		require.NoError(bc.ValidateBlock(blk, true))
+		require.NoError(bc.CommitBlock(blk))
+		var receipts []*Receipt
+		for _, execution := range executions {
+			receipt, err := bc.GetReceiptByExecutionHash(execution.Hash())
+			require.NoError(err)
+			receipts = append(receipts, receipt)
+		}
+		return receipts
+	}
+
+	precompileAddress := func(addr common.Address) string {
+		return address.New(cfg.Chain.ID, addr.Bytes()).IotxAddress()
+	}
+	ec283Verify := precompileAddress(EC283VerifyAddress)
+	blsVerify := precompileAddress(BLSVerifyAddress)
+	blake2b := precompileAddress(Blake2bAddress)
+
+	valid := make([]byte, 32)
+	valid[31] = 1
+	invalid := make([]byte, 32)
+	msg := hash.Hash256b([]byte("hello iotex"))
+	otherMsg := hash.Hash256b([]byte("hello ethereum"))
+
+	// the precompiled contracts of IoTeX are not callable below the precompile height
+	for i := 0; i < 2; i++ {
+		receipts := commit(newExecution(blake2b, []byte("hello iotex")))
+		require.NotEqual(msg, receipts[0].ReturnValue)
+	}
+
+	alfa := ta.Addrinfo["alfa"]
+	ec283Sig := crypto.EC283.Sign(alfa.PrivateKey, msg)
+	sk := crypto.DKG.SkGeneration()
+	pk, err := crypto.BLS.NewPubKey(sk)
+	require.NoError(err)
+	ok, blsSig, err := crypto.BLS.Sign(sk, msg)
+	require.NoError(err)
+	require.True(ok)
+	receipts := commit(
+		newExecution(ec283Verify, append(append(append([]byte{}, msg...), alfa.PublicKey[:]...), ec283Sig...)),
+		newExecution(ec283Verify, append(append(append([]byte{}, otherMsg...), alfa.PublicKey[:]...), ec283Sig...)),
+		newExecution(blsVerify, append(append(append([]byte{}, msg...), pk...), blsSig...)),
+		newExecution(blsVerify, append(append(append([]byte{}, otherMsg...), pk...), blsSig...)),
+		newExecution(blake2b, []byte("hello iotex")),
+		newExecution(ec283Verify, msg),
+	)
+	for i, expected := range [][]byte{valid, invalid, valid, invalid, msg} {
+		require.Equal(SuccessStatus, receipts[i].Status)
+		require.Equal(expected, receipts[i].ReturnValue)
+	}
+	// the precompiled contract fails on an invalid input
+	require.Equal(FailureStatus, receipts[5].Status)
+
+	// the amount sent to a precompiled contract is kept in its account
+	nonce++
+	execution, err := action.NewExecution(
+		producer.RawAddress, blake2b, nonce, big.NewInt(100), uint64(1000000), big.NewInt(10), []byte{})
+	require.NoError(err)
+	require.NoError(action.Sign(execution, producer.PrivateKey))
+	receipts = commit(execution)
+	require.Equal(SuccessStatus, receipts[0].Status)
+	balance, err := bc.Balance(blake2b)
+	require.NoError(err)
+	require.Equal(big.NewInt(100), balance)
+}
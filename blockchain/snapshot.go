@@ -0,0 +1,106 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+// ExportSnapshot writes the snapshot of the chain on a given height, which is the block on the height followed by the
+// snapshot of the states after the block. A node could be bootstrapped from the snapshot by setting
+// chain.stateSnapshotPath, and it only needs to sync the blocks after the height
+func ExportSnapshot(bc Blockchain, w io.Writer, height uint64) error {
+	if height == 0 || height > bc.TipHeight() {
+		return errors.Errorf("cannot export snapshot on height %d with tip height %d", height, bc.TipHeight())
+	}
+	blk, err := bc.GetBlockByHeight(height)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get block %d", height)
+	}
+	serialized, err := blk.Serialize()
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize block %d", height)
+	}
+	if err := writeChainFileRecord(w, serialized); err != nil {
+		return errors.Wrapf(err, "failed to write block %d", height)
+	}
+	return bc.GetFactory().ExportSnapshot(w, height)
+}
+
+// bootstrapFromSnapshot imports the state snapshot configured in chain.stateSnapshotPath, if the state factory is
+// lower than the snapshot. The block in the snapshot must match chain.trustedSnapshotHeight and
+// chain.trustedSnapshotHash, and the state root of the snapshot is verified against its header.
+// If the chain is lower than the snapshot, the block is put onto the chain as the new tip, so that only blocks after it
+// are synced
+func (bc *blockchain) bootstrapFromSnapshot() error {
+	path := bc.config.Chain.StateSnapshotPath
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open state snapshot %s", path)
+	}
+	defer file.Close()
+	r := bufio.NewReader(file)
+	serialized, err := readChainFileRecord(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the block of state snapshot")
+	}
+	blk := &Block{}
+	if err := blk.Deserialize(serialized); err != nil {
+		return errors.Wrap(err, "failed to deserialize the block of state snapshot")
+	}
+	height := blk.Height()
+	if factoryHeight, err := bc.sf.Height(); err == nil && factoryHeight >= height {
+		// the snapshot has been imported before, or the states are already beyond it
+		return nil
+	}
+	blkHash := blk.HashBlock()
+	if height != bc.config.Chain.TrustedSnapshotHeight ||
+		hex.EncodeToString(blkHash[:]) != bc.config.Chain.TrustedSnapshotHash {
+		return errors.Errorf("block %d %x in state snapshot does not match trusted block %d %s", height, blkHash,
+			bc.config.Chain.TrustedSnapshotHeight, bc.config.Chain.TrustedSnapshotHash)
+	}
+	if blk.Header.chainID != bc.ChainID() {
+		return errors.Errorf("chain ID %d of state snapshot does not match %d", blk.Header.chainID, bc.ChainID())
+	}
+	if blk.TxRoot() != blk.Header.txRoot {
+		return errors.Errorf("tx root of block %d in state snapshot does not match", height)
+	}
+	if !blk.VerifySignature() {
+		return errors.Errorf("failed to verify the signature of block %d in state snapshot", height)
+	}
+	if height <= bc.tipHeight {
+		existing, err := bc.dao.getBlockHash(height)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the hash of block %d", height)
+		}
+		if existing != blkHash {
+			return errors.Errorf("block %d in state snapshot does not match the chain", height)
+		}
+	}
+	if err := bc.sf.ImportSnapshot(r, height, blk.Header.stateRoot); err != nil {
+		return errors.Wrapf(err, "failed to import state snapshot on height %d", height)
+	}
+	if height > bc.tipHeight {
+		if err := bc.dao.putBlock(blk); err != nil {
+			return errors.Wrapf(err, "failed to put block %d in state snapshot", height)
+		}
+		bc.tipHeight = height
+		bc.tipHash = blkHash
+	}
+	logger.Info().Uint64("height", height).Str("path", path).Msg("Bootstrapped from state snapshot")
+	return nil
+}
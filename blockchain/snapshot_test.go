@@ -0,0 +1,93 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+const testSnapshotPath = "snapshot.test"
+
+func TestBootstrapFromSnapshot(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Disable block reward to make bookkeeping easier
+	Gen.BlockReward = uint64(0)
+	cfg := config.Default
+	cfg.Chain.EnableHistoryState = true
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	require.NoError(addTestingTsfBlocks(bc))
+	require.Equal(uint64(5), bc.TipHeight())
+
+	var snapshot bytes.Buffer
+	require.Error(ExportSnapshot(bc, &snapshot, 0))
+	require.Error(ExportSnapshot(bc, &snapshot, 6))
+	require.NoError(ExportSnapshot(bc, &snapshot, 3))
+	testutil.CleanupPath(t, testSnapshotPath)
+	defer testutil.CleanupPath(t, testSnapshotPath)
+	require.NoError(ioutil.WriteFile(testSnapshotPath, snapshot.Bytes(), 0600))
+
+	// snapshot of a block other than the trusted one
+	hash2, err := bc.GetHashByHeight(2)
+	require.NoError(err)
+	cfg.Chain.StateSnapshotPath = testSnapshotPath
+	cfg.Chain.TrustedSnapshotHeight = 2
+	cfg.Chain.TrustedSnapshotHash = hex.EncodeToString(hash2[:])
+	bc0 := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.Error(bc0.Start(ctx))
+
+	// bootstrap a new node from the snapshot
+	hash3, err := bc.GetHashByHeight(3)
+	require.NoError(err)
+	cfg.Chain.TrustedSnapshotHeight = 3
+	cfg.Chain.TrustedSnapshotHash = hex.EncodeToString(hash3[:])
+	bc1 := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc1.Start(ctx))
+	defer func() {
+		require.NoError(bc1.Stop(ctx))
+	}()
+	require.Equal(uint64(3), bc1.TipHeight())
+	require.Equal(hash3, bc1.TipHash())
+	height, err := bc1.GetFactory().Height()
+	require.NoError(err)
+	require.Equal(uint64(3), height)
+
+	// sync only the blocks after the snapshot
+	var blocks bytes.Buffer
+	require.NoError(ExportChain(bc, &blocks, 4, 5))
+	committed, err := ImportChain(bc1, &blocks)
+	require.NoError(err)
+	require.Equal(uint64(2), committed)
+	require.Equal(bc.TipHash(), bc1.TipHash())
+	for _, addr := range []string{ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["charlie"].RawAddress} {
+		balance, err := bc.Balance(addr)
+		require.NoError(err)
+		balance1, err := bc1.Balance(addr)
+		require.NoError(err)
+		require.Equal(balance, balance1)
+	}
+
+	// snapshot of another chain
+	cfg.Chain.ID = config.Default.Chain.ID + 1
+	bc2 := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.Error(bc2.Start(ctx))
+}
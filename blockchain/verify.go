@@ -0,0 +1,190 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+// Divergence describes the first block on which the result of re-execution diverges from the one stored
+type Divergence struct {
+	Height uint64
+	Reason string
+}
+
+// VerifyChain re-executes all blocks in chainDB from genesis into a fresh in-memory state factory, and compares the
+// state root, receipts and, if trieDB is not nil, candidates of each block with the ones stored. It returns the first
+// block where they diverge, or nil if all blocks match. Both DBs are only read, so they could be opened read-only
+func VerifyChain(ctx context.Context, cfg *config.Config, chainDB db.KVStore, trieDB db.KVStore) (*Divergence, error) {
+	if err := chainDB.Start(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to start chain DB")
+	}
+	defer func() {
+		if err := chainDB.Stop(ctx); err != nil {
+			logger.Error().Err(err).Msg("Failed to stop chain DB")
+		}
+	}()
+	if trieDB != nil {
+		if err := trieDB.Start(ctx); err != nil {
+			return nil, errors.Wrap(err, "failed to start trie DB")
+		}
+		defer func() {
+			if err := trieDB.Stop(ctx); err != nil {
+				logger.Error().Err(err).Msg("Failed to stop trie DB")
+			}
+		}()
+	}
+	kv := db.NewMemKVStore()
+	sf, err := state.NewFactory(cfg, state.PrecreatedTrieDBOption(kv))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create in-memory state factory")
+	}
+	// the chain is never started, so that nothing is written into chain DB
+	bc := &blockchain{
+		config:  cfg,
+		genesis: Gen,
+		clk:     clock.New(),
+		dao:     newBlockDAO(cfg, chainDB),
+		sf:      sf,
+	}
+	tipHeight, err := bc.dao.getBlockchainHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := sf.NewWorkingSet()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain working set from state factory")
+	}
+	// create creator's state as in starting an empty blockchain
+	if _, err := ws.LoadOrCreateState(Gen.CreatorAddr(bc.ChainID()), Gen.TotalSupply); err != nil {
+		return nil, err
+	}
+	if _, err := ws.RunActions(0, nil, nil, nil, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to create Creator into StateFactory")
+	}
+	if err := sf.Commit(ws); err != nil {
+		return nil, errors.Wrap(err, "failed to add Creator into StateFactory")
+	}
+	for height := uint64(0); height <= tipHeight; height++ {
+		blk, err := bc.GetBlockByHeight(height)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get block %d", height)
+		}
+		// working set of each block is committed on top of the previous one, as in committing blocks
+		if ws, err = sf.NewWorkingSet(); err != nil {
+			return nil, errors.Wrap(err, "failed to obtain working set from state factory")
+		}
+		root, err := bc.runActions(blk, ws, false)
+		if err != nil {
+			return &Divergence{Height: height, Reason: fmt.Sprintf("failed to execute block: %v", err)}, nil
+		}
+		if err := sf.Commit(ws); err != nil {
+			return nil, errors.Wrapf(err, "failed to commit states of block %d", height)
+		}
+		if root != blk.Header.stateRoot {
+			return &Divergence{
+				Height: height,
+				Reason: fmt.Sprintf("state root %x does not match %x", root, blk.Header.stateRoot),
+			}, nil
+		}
+		if height >= bc.config.Chain.ReceiptRootHeight {
+			if root := blk.ReceiptRoot(); root != blk.Header.receiptRoot {
+				return &Divergence{
+					Height: height,
+					Reason: fmt.Sprintf("receipt root %x does not match %x", root, blk.Header.receiptRoot),
+				}, nil
+			}
+			if blk.LogBloom() != blk.Header.logBloom {
+				return &Divergence{Height: height, Reason: "log bloom does not match"}, nil
+			}
+		}
+		reason, err := bc.verifyReceipts(blk)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" && trieDB != nil {
+			if reason, err = verifyCandidates(kv, trieDB, height); err != nil {
+				return nil, err
+			}
+		}
+		if reason != "" {
+			return &Divergence{Height: height, Reason: reason}, nil
+		}
+		if height%1000 == 0 {
+			logger.Info().Uint64("height", height).Uint64("tipHeight", tipHeight).Msg("Verified blocks")
+		}
+	}
+	return nil, nil
+}
+
+// verifyReceipts compares the receipts of re-executed block with the ones stored. The block hash in logs is not final
+// when the receipts are produced in minting, so only the hashes of receipts are compared
+func (bc *blockchain) verifyReceipts(blk *Block) (string, error) {
+	for _, execution := range blk.Executions {
+		h := execution.Hash()
+		receipt := blk.receipts[h]
+		stored, err := bc.dao.getReceiptByActionHash(h)
+		if receipt == nil && stored == nil {
+			continue
+		}
+		if receipt == nil {
+			return fmt.Sprintf("receipt of execution %x is missing", h), nil
+		}
+		if stored == nil {
+			return fmt.Sprintf("receipt of execution %x is not stored: %v", h, err), nil
+		}
+		if receipt.HashReceipt() != stored.HashReceipt() {
+			return fmt.Sprintf("receipt of execution %x does not match", h), nil
+		}
+	}
+	return "", nil
+}
+
+// verifyCandidates compares the candidates of re-executed block with the ones stored. Candidates of the same votes
+// are not sorted in a deterministic order, so they are compared in the order of address
+func verifyCandidates(kv db.KVStore, stored db.KVStore, height uint64) (string, error) {
+	computed, err := candidatesByAddress(kv, height)
+	if err != nil {
+		return "", err
+	}
+	expected, err := candidatesByAddress(stored, height)
+	if err != nil {
+		return fmt.Sprintf("candidates are not stored: %v", err), nil
+	}
+	if !bytes.Equal(computed, expected) {
+		return "candidates do not match", nil
+	}
+	return "", nil
+}
+
+// candidatesByAddress returns the serialized candidates of a given height in the order of address
+func candidatesByAddress(kv db.KVStore, height uint64) ([]byte, error) {
+	candidatesBytes, err := kv.Get(trie.CandidateKVNameSpace, byteutil.Uint64ToBytes(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get candidates on height %d", height)
+	}
+	candidates, err := state.Deserialize(candidatesBytes)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Address < candidates[j].Address })
+	return state.Serialize(candidates)
+}
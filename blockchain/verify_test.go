@@ -0,0 +1,69 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+func TestVerifyChain(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Disable block reward to make bookkeeping easier
+	Gen.BlockReward = uint64(0)
+	cfg := config.Default
+	chainDB := db.NewMemKVStore()
+	trieDB := db.NewMemKVStore()
+	sf, err := state.NewFactory(&cfg, state.PrecreatedTrieDBOption(trieDB))
+	require.NoError(err)
+	bc := NewBlockchain(&cfg, PrecreatedStateFactoryOption(sf), PrecreatedDaoOption(newBlockDAO(&cfg, chainDB)))
+	require.NoError(bc.Start(ctx))
+	require.NoError(addTestingTsfBlocks(bc))
+	require.Equal(uint64(5), bc.TipHeight())
+
+	divergence, err := VerifyChain(ctx, &cfg, chainDB, trieDB)
+	require.NoError(err)
+	require.Nil(divergence)
+
+	// tamper the state root of block 4
+	blk, err := bc.GetBlockByHeight(4)
+	require.NoError(err)
+	blkHash := blk.HashBlock()
+	blk.Header.stateRoot[0]++
+	serialized, err := blk.Serialize()
+	require.NoError(err)
+	require.NoError(chainDB.Put(blockNS, blkHash[:], serialized))
+	divergence, err = VerifyChain(ctx, &cfg, chainDB, trieDB)
+	require.NoError(err)
+	require.Equal(uint64(4), divergence.Height)
+
+	// tamper the candidates of block 2, which are only checked against trie DB
+	candidates, err := sf.CandidatesByHeight(2)
+	require.NoError(err)
+	require.NotEmpty(candidates)
+	candidates[0].Votes = new(big.Int).Add(candidates[0].Votes, big.NewInt(1))
+	candidatesBytes, err := state.Serialize(candidates)
+	require.NoError(err)
+	require.NoError(trieDB.Put(trie.CandidateKVNameSpace, byteutil.Uint64ToBytes(2), candidatesBytes))
+	divergence, err = VerifyChain(ctx, &cfg, chainDB, nil)
+	require.NoError(err)
+	require.Equal(uint64(4), divergence.Height)
+	divergence, err = VerifyChain(ctx, &cfg, chainDB, trieDB)
+	require.NoError(err)
+	require.Equal(uint64(2), divergence.Height)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/iotexproject/iotex-core/indexservice"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/pkg/routine"
 	pb "github.com/iotexproject/iotex-core/proto"
 )
 
@@ -29,6 +30,9 @@ type ChainService struct {
 	chain        blockchain.Blockchain
 	explorer     *explorer.Server
 	indexservice *indexservice.Server
+	p2p          network.Overlay
+	// rebroadcastTask rebroadcasts the actions submitted to this node while they are in actpool
+	rebroadcastTask *routine.RecurringTask
 }
 
 type optionParams struct {
@@ -120,14 +124,19 @@ func New(cfg *config.Config, p2p network.Overlay, dispatcher dispatcher.Dispatch
 	} else {
 		exp = explorer.NewServer(cfg.Explorer, chain, consensus, dispatcher, actPool, p2p)
 	}
-	return &ChainService{
+	cs := &ChainService{
 		actpool:      actPool,
 		chain:        chain,
 		blocksync:    bs,
 		consensus:    consensus,
 		indexservice: idx,
 		explorer:     exp,
-	}, nil
+		p2p:          p2p,
+	}
+	if cfg.ActPool.RebroadcastDelay > 0 {
+		cs.rebroadcastTask = routine.NewRecurringTask(cs.rebroadcastLocalActs, cfg.ActPool.RebroadcastDelay)
+	}
+	return cs, nil
 }
 
 // Start starts the server
@@ -135,6 +144,9 @@ func (cs *ChainService) Start(ctx context.Context) error {
 	if err := cs.chain.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting blockchain")
 	}
+	if err := cs.actpool.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting actpool")
+	}
 	if err := cs.consensus.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting consensus")
 	}
@@ -151,11 +163,21 @@ func (cs *ChainService) Start(ctx context.Context) error {
 	if err := cs.explorer.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting explorer")
 	}
+	if cs.rebroadcastTask != nil {
+		if err := cs.rebroadcastTask.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting rebroadcast task")
+		}
+	}
 	return nil
 }
 
 // Stop stops the server
 func (cs *ChainService) Stop(ctx context.Context) error {
+	if cs.rebroadcastTask != nil {
+		if err := cs.rebroadcastTask.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping rebroadcast task")
+		}
+	}
 	if err := cs.explorer.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping explorer")
 	}
@@ -172,6 +194,9 @@ func (cs *ChainService) Stop(ctx context.Context) error {
 	if err := cs.blocksync.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping blocksync")
 	}
+	if err := cs.actpool.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping actpool")
+	}
 	if err := cs.chain.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping blockchain")
 	}
@@ -205,6 +230,23 @@ func (cs *ChainService) HandleAction(act *pb.ActionPb) error {
 	return nil
 }
 
+// rebroadcastLocalActs rebroadcasts the actions submitted to this node which are due to be rebroadcast
+func (cs *ChainService) rebroadcastLocalActs() {
+	for _, act := range cs.actpool.LocalActsToRebroadcast() {
+		hash := act.Hash()
+		if err := cs.p2p.Broadcast(cs.chain.ChainID(), act.Proto()); err != nil {
+			logger.Warn().
+				Hex("hash", hash[:]).
+				Err(err).
+				Msg("Failed to rebroadcast action")
+			continue
+		}
+		logger.Debug().
+			Hex("hash", hash[:]).
+			Msg("Rebroadcast action")
+	}
+}
+
 // HandleBlock handles incoming block request.
 func (cs *ChainService) HandleBlock(pbBlock *pb.BlockPb) error {
 	blk := &blockchain.Block{}
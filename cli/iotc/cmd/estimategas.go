@@ -0,0 +1,59 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	eidl "github.com/iotexproject/iotex-core/explorer/idl/explorer"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+var (
+	contract string
+	amount   int64
+)
+
+// estimateGasCmd represents the estimategas command
+var estimateGasCmd = &cobra.Command{
+	Use:   "estimategas [executor] [data]",
+	Short: "Returns the estimated gas limit of a smart contract execution",
+	Long: `Returns the estimated gas limit of a smart contract execution. The data is hex encoded, and the contract is
+deployed if no contract address is given.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(estimateGas(args))
+	},
+}
+
+func estimateGas(args []string) string {
+	client, err := getClient()
+	if err != nil {
+		logger.Error().Err(err).Msg("cannot get explorer client")
+		return ""
+	}
+	execution := eidl.Execution{
+		Executor: args[0],
+		Contract: contract,
+		Amount:   amount,
+		Data:     args[1],
+	}
+	gas, err := client.EstimateGasForSmartContract(execution)
+	if err != nil {
+		logger.Error().Err(err).Msgf("cannot estimate gas for execution from %s", args[0])
+		return ""
+	}
+	return fmt.Sprintf("Estimated gas: %d", gas)
+}
+
+func init() {
+	rootCmd.AddCommand(estimateGasCmd)
+	estimateGasCmd.PersistentFlags().StringVarP(&contract, "contract", "c", "", "address of the contract to execute")
+	estimateGasCmd.PersistentFlags().Int64VarP(&amount, "amount", "a", 0, "amount transferred to the contract")
+}
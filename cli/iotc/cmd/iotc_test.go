@@ -38,4 +38,5 @@ func Test_All(t *testing.T) {
 	det := details([]string{addr})
 	assert.Equal(t, 1, strings.Count(det, "\n"))
 	assert.NotEqual(t, "", balance([]string{addr})) // no real way to test this because balance returned is random
+	assert.NotEqual(t, "", estimateGas([]string{addr, ""}))
 }
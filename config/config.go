@@ -7,6 +7,7 @@
 package config
 
 import (
+	"encoding/hex"
 	"flag"
 	"os"
 	"time"
@@ -96,11 +97,29 @@ var (
 			GenesisActionsPath:      "",
 			NumCandidates:           101,
 			EnableFallBackToFreshDB: false,
+			EnableHistoryState:      false,
+			StateSnapshotPath:       "",
+			TrustedSnapshotHeight:   0,
+			TrustedSnapshotHash:     "",
+			BlockGasLimit:           1000000000,
+			MaxBlockSize:            4194304,
+			ReceiptRootHeight:       0,
+			GasFeeHeight:            0,
+			PrecompileHeight:        0,
+			EVMStateHeight:          0,
+			BlockLimitHeight:        0,
 		},
 		ActPool: ActPool{
-			MaxNumActsPerPool: 32000,
-			MaxNumActsPerAcct: 2000,
-			MaxNumActsToPick:  0,
+			MaxNumActsPerPool:     32000,
+			MaxNumActsPerAcct:     2000,
+			MaxNumActsToPick:      0,
+			MaxGasToPick:          1000000000,
+			GasPriceBumpToReplace: 10,
+			MaxBytesPerPool:       67108864,
+			ActionExpiry:          10 * time.Minute,
+			JournalPath:           "",
+			RebroadcastDelay:      30 * time.Second,
+			MaxRebroadcasts:       5,
 		},
 		Consensus: Consensus{
 			Scheme: NOOPScheme,
@@ -136,6 +155,8 @@ var (
 			Port:                    14004,
 			TpsWindow:               10,
 			MaxTransferPayloadBytes: 1024,
+			DebugEnabled:            false,
+			MaxLogQueryRange:        1000,
 		},
 		Indexer: Indexer{
 			Enabled: false,
@@ -213,6 +234,35 @@ type (
 		GenesisActionsPath      string `yaml:"genesisActionsPath"`
 		NumCandidates           uint   `yaml:"numCandidates"`
 		EnableFallBackToFreshDB bool   `yaml:"enablefallbacktofreshdb"`
+		// EnableHistoryState keeps the states of all past heights in trie DB, so that they could be queried later. The
+		// history starts from the first height run with it enabled, and the states of earlier heights are not available
+		EnableHistoryState bool `yaml:"enableHistoryState"`
+		// StateSnapshotPath is the path of the state snapshot to bootstrap the node from, instead of replaying all blocks
+		StateSnapshotPath string `yaml:"stateSnapshotPath"`
+		// TrustedSnapshotHeight is the height of the block in the state snapshot, which must be obtained from a trusted
+		// source, as the snapshot itself only proves that it is signed by a block producer
+		TrustedSnapshotHeight uint64 `yaml:"trustedSnapshotHeight"`
+		// TrustedSnapshotHash is the hex-encoded hash of the block in the state snapshot
+		TrustedSnapshotHash string `yaml:"trustedSnapshotHash"`
+		// BlockGasLimit is the max total gas of the actions in a block
+		BlockGasLimit uint64 `yaml:"blockGasLimit"`
+		// MaxBlockSize is the max total byte size of the serialized actions in a block
+		MaxBlockSize uint64 `yaml:"maxBlockSize"`
+		// ReceiptRootHeight is the height from which the receipt root and log bloom are set in block headers and
+		// verified. The blocks produced before the receipt root was introduced are lower than it
+		ReceiptRootHeight uint64 `yaml:"receiptRootHeight"`
+		// GasFeeHeight is the height from which the intrinsic gas fees of transfers and votes are charged. The blocks
+		// produced before the gas fees were introduced are lower than it
+		GasFeeHeight uint64 `yaml:"gasFeeHeight"`
+		// PrecompileHeight is the height from which executions can call the precompiled contracts of IoTeX. The blocks
+		// produced before those contracts were introduced are lower than it
+		PrecompileHeight uint64 `yaml:"precompileHeight"`
+		// EVMStateHeight is the height from which the state changes made by a failed execution are reverted, and evm
+		// sets nonces, kills contracts and refunds gas. The blocks produced before those were introduced are lower than it
+		EVMStateHeight uint64 `yaml:"evmStateHeight"`
+		// BlockLimitHeight is the height from which the blocks are verified against BlockGasLimit and MaxBlockSize. The
+		// blocks produced before the limits were introduced are lower than it
+		BlockLimitHeight uint64 `yaml:"blockLimitHeight"`
 	}
 
 	// Consensus is the config struct for consensus package
@@ -261,6 +311,10 @@ type (
 		TpsWindow int  `yaml:"tpsWindow"`
 		// MaxTransferPayloadBytes limits how many bytes a playload can contain at most
 		MaxTransferPayloadBytes uint64 `yaml:"maxTransferPayloadBytes"`
+		// DebugEnabled enables the debug APIs, such as tracing executions, which are expensive to serve
+		DebugEnabled bool `yaml:"debugEnabled"`
+		// MaxLogQueryRange is the max number of blocks a query of contract logs could span
+		MaxLogQueryRange uint64 `yaml:"maxLogQueryRange"`
 	}
 
 	// Indexer is the index service config
@@ -286,6 +340,26 @@ type (
 		// MaxNumActsToPick indicates maximum number of actions to pick to mint a block. Default is 0, which means no
 		// limit on the number of actions to pick.
 		MaxNumActsToPick uint64 `yaml:"maxNumActsToPick"`
+		// MaxGasToPick indicates maximum total gas of actions to pick to mint a block, which cannot be greater than the
+		// block gas limit. 0 means no limit on the gas of actions to pick.
+		MaxGasToPick uint64 `yaml:"maxGasToPick"`
+		// GasPriceBumpToReplace indicates the minimum percentage by which the gas price of an action should exceed the
+		// one of the pending action with the same sender and nonce to replace it
+		GasPriceBumpToReplace uint64 `yaml:"gasPriceBumpToReplace"`
+		// MaxBytesPerPool indicates maximum total size in bytes of the actions the whole actpool can hold. 0 means no
+		// limit on the size of actions in pool.
+		MaxBytesPerPool uint64 `yaml:"maxBytesPerPool"`
+		// ActionExpiry indicates how long an action can stay in pool while it cannot be picked because of a nonce gap.
+		// 0 means such actions never expire.
+		ActionExpiry time.Duration `yaml:"actionExpiry"`
+		// JournalPath is the path of the file journaling the actions in pool, which are reloaded when the node starts.
+		// Empty path means no journal.
+		JournalPath string `yaml:"journalPath"`
+		// RebroadcastDelay indicates how long to wait before rebroadcasting an action submitted to this node if it is
+		// still in pool, which doubles after each rebroadcast. 0 means no rebroadcast.
+		RebroadcastDelay time.Duration `yaml:"rebroadcastDelay"`
+		// MaxRebroadcasts indicates maximum number of times to rebroadcast an action submitted to this node
+		MaxRebroadcasts uint64 `yaml:"maxRebroadcasts"`
 	}
 
 	// DB is the blotDB config
@@ -468,6 +542,17 @@ func ValidateChain(cfg *Config) error {
 	if cfg.Consensus.Scheme == RollDPoSScheme && cfg.Chain.NumCandidates < cfg.Consensus.RollDPoS.NumDelegates {
 		return errors.Wrapf(ErrInvalidCfg, "candidate number should be greater than or equal to delegate number")
 	}
+	if cfg.Chain.BlockGasLimit == 0 || cfg.Chain.MaxBlockSize == 0 {
+		return errors.Wrapf(ErrInvalidCfg, "block gas limit and max block size should be greater than 0")
+	}
+	if cfg.Chain.StateSnapshotPath != "" {
+		if cfg.Chain.TrustedSnapshotHeight == 0 || cfg.Chain.TrustedSnapshotHash == "" {
+			return errors.Wrap(ErrInvalidCfg, "trusted height and hash should be set to bootstrap from state snapshot")
+		}
+		if h, err := hex.DecodeString(cfg.Chain.TrustedSnapshotHash); err != nil || len(h) != 32 {
+			return errors.Wrapf(ErrInvalidCfg, "invalid trusted snapshot hash %s", cfg.Chain.TrustedSnapshotHash)
+		}
+	}
 	return nil
 }
 
@@ -518,6 +603,9 @@ func ValidateExplorer(cfg *Config) error {
 	if cfg.Explorer.Enabled && cfg.Explorer.TpsWindow <= 0 {
 		return errors.Wrap(ErrInvalidCfg, "tps window is not a positive integer when the explorer is enabled")
 	}
+	if cfg.Explorer.Enabled && cfg.Explorer.MaxLogQueryRange == 0 {
+		return errors.Wrap(ErrInvalidCfg, "max log query range should be greater than 0 when the explorer is enabled")
+	}
 	return nil
 }
 
@@ -545,6 +633,9 @@ func ValidateActPool(cfg *Config) error {
 			"maximum number of actions per pool cannot be less than maximum number of actions per account",
 		)
 	}
+	if cfg.ActPool.MaxGasToPick > cfg.Chain.BlockGasLimit {
+		return errors.Wrap(ErrInvalidCfg, "maximum gas of actions to pick cannot be greater than block gas limit")
+	}
 	return nil
 }
 
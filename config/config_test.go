@@ -206,6 +206,17 @@ func TestValidateExplorer(t *testing.T) {
 		t,
 		strings.Contains(err.Error(), "tps window is not a positive integer when the explorer is enabled"),
 	)
+
+	cfg = Default
+	cfg.Explorer.Enabled = true
+	cfg.Explorer.MaxLogQueryRange = 0
+	err = ValidateExplorer(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "max log query range should be greater than 0 when the explorer is enabled"),
+	)
 }
 
 func TestValidateChain(t *testing.T) {
@@ -231,6 +242,34 @@ func TestValidateChain(t *testing.T) {
 		t,
 		strings.Contains(err.Error(), "candidate number should be greater than or equal to delegate number"),
 	)
+
+	cfg = Default
+	cfg.Chain.MaxBlockSize = 0
+	err = ValidateChain(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "block gas limit and max block size should be greater than 0"),
+	)
+
+	cfg = Default
+	cfg.Chain.StateSnapshotPath = "/tmp/snapshot"
+	err = ValidateChain(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "trusted height and hash should be set to bootstrap from state snapshot"),
+	)
+	cfg.Chain.TrustedSnapshotHeight = 10
+	cfg.Chain.TrustedSnapshotHash = "1234"
+	err = ValidateChain(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "invalid trusted snapshot hash"))
+	cfg.Chain.TrustedSnapshotHash = strings.Repeat("ab", 32)
+	require.NoError(t, ValidateChain(&cfg))
 }
 
 func TestValidateConsensusScheme(t *testing.T) {
@@ -361,6 +400,19 @@ func TestValidateActPool(t *testing.T) {
 			"maximum number of actions per pool cannot be less than maximum number of actions per account",
 		),
 	)
+
+	cfg.ActPool.MaxNumActsPerPool = 100
+	cfg.ActPool.MaxGasToPick = cfg.Chain.BlockGasLimit + 1
+	err = ValidateActPool(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(
+			err.Error(),
+			"maximum gas of actions to pick cannot be greater than block gas limit",
+		),
+	)
 }
 
 func TestCheckNodeType(t *testing.T) {
@@ -7,6 +7,7 @@
 package crypto
 
 import (
+	"github.com/pkg/errors"
 	"golang.org/x/crypto/blake2b"
 
 	"github.com/iotexproject/iotex-core/logger"
@@ -83,3 +84,52 @@ func (mk *Merkle) HashTree() hash.Hash32B {
 	mk.root = merkle[0]
 	return mk.root
 }
+
+// AuditPath returns the audit path of the leaf at index, which is the list of sibling hashes from the leaf level up
+// to the level right below the root
+func (mk *Merkle) AuditPath(index int) ([]hash.Hash32B, error) {
+	if index < 0 || index >= mk.size {
+		return nil, errors.Errorf("index %d is out of range [0, %d)", index, mk.size)
+	}
+
+	path := []hash.Hash32B{}
+	level := make([]hash.Hash32B, mk.size)
+	copy(level, mk.leaf)
+	for len(level) > 1 {
+		// copy the last hash if the level has odd number of nodes, same as HashTree
+		if len(level)&1 != 0 {
+			level = append(level, level[len(level)-1])
+		}
+		path = append(path, level[index^1])
+
+		next := make([]hash.Hash32B, len(level)>>1)
+		for i := range next {
+			h := level[i<<1][:]
+			h = append(h, level[i<<1+1][:]...)
+			next[i] = blake2b.Sum256(h)
+		}
+		level = next
+		index >>= 1
+	}
+	return path, nil
+}
+
+// VerifyAuditPath verifies that the leaf at index is included in the merkle tree with the given root
+func VerifyAuditPath(root hash.Hash32B, leaf hash.Hash32B, index int, path []hash.Hash32B) bool {
+	if index < 0 {
+		return false
+	}
+	h := leaf
+	for _, sibling := range path {
+		var b []byte
+		if index&1 == 0 {
+			b = append(h[:], sibling[:]...)
+		} else {
+			b = append(sibling[:], h[:]...)
+		}
+		h = blake2b.Sum256(b)
+		index >>= 1
+	}
+	// the index must be fully consumed by the path
+	return index == 0 && h == root
+}
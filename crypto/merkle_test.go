@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
 
 	"github.com/iotexproject/iotex-core/pkg/hash"
 )
@@ -69,3 +70,30 @@ func TestMerkleTree(t *testing.T) {
 	assert.Equal(t, 0, bytes.Compare(expected[:], actual5[:]))
 	assert.Equal(t, -1, bytes.Compare(actual5[:], actual4[:]))
 }
+
+func TestMerkleAuditPath(t *testing.T) {
+	var inputs []hash.Hash32B
+	for i := 0; i < 7; i++ {
+		inputs = append(inputs, blake2b.Sum256([]byte{byte(i)}))
+	}
+
+	for size := 1; size <= len(inputs); size++ {
+		m := NewMerkleTree(inputs[:size])
+		root := m.HashTree()
+		for i := 0; i < size; i++ {
+			path, err := m.AuditPath(i)
+			assert.Nil(t, err)
+			assert.True(t, VerifyAuditPath(root, inputs[i], i, path))
+			// wrong leaf or index
+			assert.False(t, VerifyAuditPath(root, inputs[(i+1)%len(inputs)], i, path))
+			if size > 1 && !(size&1 != 0 && i == size-1) {
+				assert.False(t, VerifyAuditPath(root, inputs[i], i^1, path))
+			}
+			assert.False(t, VerifyAuditPath(root, inputs[i], i+1<<uint(len(path)), path))
+		}
+		_, err := m.AuditPath(-1)
+		assert.NotNil(t, err)
+		_, err = m.AuditPath(len(m.leaf) + 1)
+		assert.NotNil(t, err)
+	}
+}
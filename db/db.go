@@ -8,6 +8,7 @@ package db
 
 import (
 	"context"
+	"os"
 	"sync"
 
 	"github.com/boltdb/bolt"
@@ -137,10 +138,11 @@ const fileMode = 0600
 
 // boltDB is KVStore implementation based bolt DB
 type boltDB struct {
-	mutex  sync.RWMutex
-	db     *bolt.DB
-	path   string
-	config *config.DB
+	mutex    sync.RWMutex
+	db       *bolt.DB
+	path     string
+	config   *config.DB
+	readOnly bool
 }
 
 // NewBoltDB instantiates a boltdb based KV store
@@ -148,6 +150,12 @@ func NewBoltDB(path string, cfg *config.DB) KVStore {
 	return &boltDB{db: nil, path: path, config: cfg}
 }
 
+// NewReadOnlyBoltDB instantiates a boltdb based KV store which opens an existing DB file in read-only mode, so that
+// it could be shared with other readers. All writes to it fail
+func NewReadOnlyBoltDB(path string, cfg *config.DB) KVStore {
+	return &boltDB{db: nil, path: path, config: cfg, readOnly: true}
+}
+
 // Start opens the BoltDB (creates new file if not existing yet)
 func (b *boltDB) Start(_ context.Context) error {
 	b.mutex.Lock()
@@ -157,7 +165,15 @@ func (b *boltDB) Start(_ context.Context) error {
 		return nil
 	}
 
-	db, err := bolt.Open(b.path, fileMode, nil)
+	var opts *bolt.Options
+	if b.readOnly {
+		// bolt would create the file even if it is opened read-only
+		if _, err := os.Stat(b.path); err != nil {
+			return errors.Wrapf(err, "failed to open read-only DB %s", b.path)
+		}
+		opts = &bolt.Options{ReadOnly: true}
+	}
+	db, err := bolt.Open(b.path, fileMode, opts)
 	if err != nil {
 		return err
 	}
@@ -408,3 +408,33 @@ func TestClone(t *testing.T) {
 	_, err = kv.Get(bucket2, testK1[0])
 	require.Error(err)
 }
+
+func TestReadOnlyBoltDB(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	path := "db.test"
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+	// read-only DB cannot be created
+	require.Error(NewReadOnlyBoltDB(path, cfg).Start(ctx))
+
+	kv := NewBoltDB(path, cfg)
+	require.NoError(kv.Start(ctx))
+	require.NoError(kv.Put(bucket1, testK1[0], testV1[0]))
+	require.NoError(kv.Stop(ctx))
+
+	kv = NewReadOnlyBoltDB(path, cfg)
+	require.NoError(kv.Start(ctx))
+	defer func() {
+		require.NoError(kv.Stop(ctx))
+	}()
+	v, err := kv.Get(bucket1, testK1[0])
+	require.NoError(err)
+	require.Equal(testV1[0], v)
+	require.Error(kv.Put(bucket1, testK1[1], testV1[1]))
+	require.Error(kv.Delete(bucket1, testK1[0]))
+	batch := kv.Batch()
+	require.NoError(batch.Put(bucket1, testK1[1], testV1[1], ""))
+	require.Error(kv.Commit(batch))
+}
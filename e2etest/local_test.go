@@ -117,7 +117,8 @@ func TestLocalCommit(t *testing.T) {
 	t.Logf("test balance = %d", test)
 	change.Add(change, test)
 
-	require.Equal(uint64(3000000000), change.Uint64())
+	// the producer is paid the gas fee of the transfer from the creator as well
+	require.Equal(uint64(3000100000), change.Uint64())
 	t.Log("Total balance match")
 
 	if beta.Sign() == 0 || fox.Sign() == 0 || test.Sign() == 0 {
@@ -290,7 +291,8 @@ func TestLocalCommit(t *testing.T) {
 	t.Logf("test balance = %d", test)
 	change.Add(change, test)
 
-	require.Equal(uint64(3000000000), change.Uint64())
+	// the producer is paid the gas fee of the transfer from the creator as well
+	require.Equal(uint64(3000100000), change.Uint64())
 	t.Log("Total balance match")
 }
 
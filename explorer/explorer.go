@@ -9,6 +9,7 @@ package explorer
 import (
 	"encoding/hex"
 	"math/big"
+	"sort"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -100,6 +101,130 @@ func (exp *Service) GetAddressDetails(address string) (explorer.AddressDetails,
 	return details, nil
 }
 
+// GetAddressBalanceAtHeight returns the balance of an address at a given block height
+func (exp *Service) GetAddressBalanceAtHeight(address string, height int64) (int64, error) {
+	if height < 0 {
+		return int64(0), errors.New("invalid block height")
+	}
+	balance, err := exp.bc.BalanceAtHeight(address, uint64(height))
+	if err != nil {
+		return int64(0), err
+	}
+	return balance.Int64(), nil
+}
+
+// GetAddressDetailsAtHeight returns the properties of an address at a given block height
+func (exp *Service) GetAddressDetailsAtHeight(address string, height int64) (explorer.AddressDetails, error) {
+	if height < 0 {
+		return explorer.AddressDetails{}, errors.New("invalid block height")
+	}
+	state, err := exp.bc.StateAtHeight(address, uint64(height))
+	if err != nil {
+		return explorer.AddressDetails{}, err
+	}
+	details := explorer.AddressDetails{
+		Address:      address,
+		TotalBalance: (*state).Balance.Int64(),
+		Nonce:        int64((*state).Nonce),
+		// pending nonce at a past height is the next nonce to be used after that height
+		PendingNonce: int64((*state).Nonce + 1),
+		IsCandidate:  (*state).IsCandidate,
+	}
+
+	return details, nil
+}
+
+// GetAccountProof returns the merkle proof of the state of an address against the state root of the block at a given
+// height, which is the latest height without history states
+func (exp *Service) GetAccountProof(address string, height int64) (explorer.AccountProof, error) {
+	if height < 0 {
+		return explorer.AccountProof{}, errors.New("invalid block height")
+	}
+	blkHash, err := exp.bc.GetHashByHeight(uint64(height))
+	if err != nil {
+		return explorer.AccountProof{}, err
+	}
+	root, proof, err := exp.bc.GetFactory().StateProof(address, uint64(height))
+	if err != nil {
+		return explorer.AccountProof{}, err
+	}
+	accountProof := explorer.AccountProof{
+		Address:     address,
+		BlockID:     hex.EncodeToString(blkHash[:]),
+		BlockHeight: height,
+		StateRoot:   hex.EncodeToString(root[:]),
+	}
+	for _, node := range proof {
+		accountProof.Proof = append(accountProof.Proof, hex.EncodeToString(node))
+	}
+	return accountProof, nil
+}
+
+// GetActionProof returns the merkle proof of a tx or action of any type being included in a block
+func (exp *Service) GetActionProof(actionID string) (explorer.ActionProof, error) {
+	bytes, err := hex.DecodeString(actionID)
+	if err != nil {
+		return explorer.ActionProof{}, err
+	}
+	var actionHash hash.Hash32B
+	copy(actionHash[:], bytes)
+
+	proof, err := exp.bc.GetActionProof(actionHash)
+	if err != nil {
+		return explorer.ActionProof{}, err
+	}
+	blkHash := proof.BlockHeader.HashHeader()
+	txRoot := proof.BlockHeader.TxRoot()
+	actionProof := explorer.ActionProof{
+		ActionID:    actionID,
+		BlockID:     hex.EncodeToString(blkHash[:]),
+		BlockHeight: int64(proof.BlockHeader.Height()),
+		BlockHeader: hex.EncodeToString(proof.BlockHeader.ByteStream()),
+		TxRoot:      hex.EncodeToString(txRoot[:]),
+		Index:       int64(proof.Index),
+	}
+	for _, sibling := range proof.Path {
+		actionProof.Path = append(actionProof.Path, hex.EncodeToString(sibling[:]))
+	}
+	return actionProof, nil
+}
+
+// GetReceiptProof returns the merkle proof of the receipt of an execution being included in a block
+func (exp *Service) GetReceiptProof(executionID string) (explorer.ReceiptProof, error) {
+	bytes, err := hex.DecodeString(executionID)
+	if err != nil {
+		return explorer.ReceiptProof{}, err
+	}
+	var executionHash hash.Hash32B
+	copy(executionHash[:], bytes)
+
+	proof, err := exp.bc.GetReceiptProof(executionHash)
+	if err != nil {
+		return explorer.ReceiptProof{}, err
+	}
+	receipt, err := convertReceiptToExplorerReceipt(proof.Receipt)
+	if err != nil {
+		return explorer.ReceiptProof{}, err
+	}
+	blkHash := proof.BlockHeader.HashHeader()
+	receiptRoot := proof.BlockHeader.ReceiptRoot()
+	receiptHash := proof.Receipt.HashReceipt()
+	receiptProof := explorer.ReceiptProof{
+		ExecutionID: executionID,
+		BlockID:     hex.EncodeToString(blkHash[:]),
+		BlockHeight: int64(proof.BlockHeader.Height()),
+		BlockHeader: hex.EncodeToString(proof.BlockHeader.ByteStream()),
+		ReceiptRoot: hex.EncodeToString(receiptRoot[:]),
+		Receipt:     receipt,
+		ReceiptHash: hex.EncodeToString(receiptHash[:]),
+		Index:       int64(proof.Index),
+	}
+	for _, sibling := range proof.Path {
+		receiptProof.Path = append(receiptProof.Path, hex.EncodeToString(sibling[:]))
+	}
+	return receiptProof, nil
+}
+
 // GetLastTransfersByRange returns transfers in [-(offset+limit-1), -offset] from block
 // with height startBlockHeight
 func (exp *Service) GetLastTransfersByRange(startBlockHeight int64, offset int64, limit int64, showCoinBase bool) ([]explorer.Transfer, error) {
@@ -164,26 +289,19 @@ func (exp *Service) GetTransferByID(transferID string) (explorer.Transfer, error
 // GetTransfersByAddress returns all transfers associated with an address
 func (exp *Service) GetTransfersByAddress(address string, offset int64, limit int64) ([]explorer.Transfer, error) {
 	var res []explorer.Transfer
-	transfersFromAddress, err := exp.bc.GetTransfersFromAddress(address)
-	if err != nil {
-		return []explorer.Transfer{}, err
+	if offset < 0 || limit <= 0 {
+		return res, nil
 	}
-
-	transfersToAddress, err := exp.bc.GetTransfersToAddress(address)
+	// only the latest offset + limit transfers are read
+	transferHashes, _, err := exp.bc.GetTransfersByAddress(address, &blockchain.HistoryQuery{Limit: uint64(offset + limit)})
 	if err != nil {
 		return []explorer.Transfer{}, err
 	}
-
-	transfersFromAddress = append(transfersFromAddress, transfersToAddress...)
-	for i, transferHash := range transfersFromAddress {
+	for i, transferHash := range transferHashes {
 		if int64(i) < offset {
 			continue
 		}
 
-		if int64(len(res)) >= limit {
-			break
-		}
-
 		explorerTransfer, err := getTransfer(exp.bc, exp.ap, transferHash)
 		if err != nil {
 			return []explorer.Transfer{}, err
@@ -195,6 +313,28 @@ func (exp *Service) GetTransfersByAddress(address string, offset int64, limit in
 	return res, nil
 }
 
+// GetTransfersByAddressPage returns a page of transfers associated with an address in reverse chronological order
+func (exp *Service) GetTransfersByAddressPage(request explorer.AddressHistoryRequest) (explorer.TransferPage, error) {
+	query, err := convertAddressHistoryRequest(request)
+	if err != nil {
+		return explorer.TransferPage{}, err
+	}
+	transferHashes, cursor, err := exp.bc.GetTransfersByAddress(request.Address, query)
+	if err != nil {
+		return explorer.TransferPage{}, err
+	}
+	res := explorer.TransferPage{Transfers: []explorer.Transfer{}, Cursor: cursor}
+	for _, transferHash := range transferHashes {
+		explorerTransfer, err := getTransfer(exp.bc, exp.ap, transferHash)
+		if err != nil {
+			return explorer.TransferPage{}, err
+		}
+		res.Transfers = append(res.Transfers, explorerTransfer)
+	}
+
+	return res, nil
+}
+
 // GetUnconfirmedTransfersByAddress returns all unconfirmed transfers in actpool associated with an address
 func (exp *Service) GetUnconfirmedTransfersByAddress(address string, offset int64, limit int64) ([]explorer.Transfer, error) {
 	res := make([]explorer.Transfer, 0)
@@ -323,26 +463,19 @@ func (exp *Service) GetVoteByID(voteID string) (explorer.Vote, error) {
 // GetVotesByAddress returns all votes associated with an address
 func (exp *Service) GetVotesByAddress(address string, offset int64, limit int64) ([]explorer.Vote, error) {
 	var res []explorer.Vote
-	votesFromAddress, err := exp.bc.GetVotesFromAddress(address)
-	if err != nil {
-		return []explorer.Vote{}, err
+	if offset < 0 || limit <= 0 {
+		return res, nil
 	}
-
-	votesToAddress, err := exp.bc.GetVotesToAddress(address)
+	// only the latest offset + limit votes are read
+	voteHashes, _, err := exp.bc.GetVotesByAddress(address, &blockchain.HistoryQuery{Limit: uint64(offset + limit)})
 	if err != nil {
 		return []explorer.Vote{}, err
 	}
-
-	votesFromAddress = append(votesFromAddress, votesToAddress...)
-	for i, voteHash := range votesFromAddress {
+	for i, voteHash := range voteHashes {
 		if int64(i) < offset {
 			continue
 		}
 
-		if int64(len(res)) >= limit {
-			break
-		}
-
 		explorerVote, err := getVote(exp.bc, exp.ap, voteHash)
 		if err != nil {
 			return []explorer.Vote{}, err
@@ -354,6 +487,28 @@ func (exp *Service) GetVotesByAddress(address string, offset int64, limit int64)
 	return res, nil
 }
 
+// GetVotesByAddressPage returns a page of votes associated with an address in reverse chronological order
+func (exp *Service) GetVotesByAddressPage(request explorer.AddressHistoryRequest) (explorer.VotePage, error) {
+	query, err := convertAddressHistoryRequest(request)
+	if err != nil {
+		return explorer.VotePage{}, err
+	}
+	voteHashes, cursor, err := exp.bc.GetVotesByAddress(request.Address, query)
+	if err != nil {
+		return explorer.VotePage{}, err
+	}
+	res := explorer.VotePage{Votes: []explorer.Vote{}, Cursor: cursor}
+	for _, voteHash := range voteHashes {
+		explorerVote, err := getVote(exp.bc, exp.ap, voteHash)
+		if err != nil {
+			return explorer.VotePage{}, err
+		}
+		res.Votes = append(res.Votes, explorerVote)
+	}
+
+	return res, nil
+}
+
 // GetUnconfirmedVotesByAddress returns all unconfirmed votes in actpool associated with an address
 func (exp *Service) GetUnconfirmedVotesByAddress(address string, offset int64, limit int64) ([]explorer.Vote, error) {
 	res := make([]explorer.Vote, 0)
@@ -481,26 +636,19 @@ func (exp *Service) GetExecutionByID(executionID string) (explorer.Execution, er
 // GetExecutionsByAddress returns all executions associated with an address
 func (exp *Service) GetExecutionsByAddress(address string, offset int64, limit int64) ([]explorer.Execution, error) {
 	var res []explorer.Execution
-	executionsFromAddress, err := exp.bc.GetExecutionsFromAddress(address)
-	if err != nil {
-		return []explorer.Execution{}, err
+	if offset < 0 || limit <= 0 {
+		return res, nil
 	}
-
-	executionsToAddress, err := exp.bc.GetExecutionsToAddress(address)
+	// only the latest offset + limit executions are read
+	executionHashes, _, err := exp.bc.GetExecutionsByAddress(address, &blockchain.HistoryQuery{Limit: uint64(offset + limit)})
 	if err != nil {
 		return []explorer.Execution{}, err
 	}
-
-	executionsFromAddress = append(executionsFromAddress, executionsToAddress...)
-	for i, executionHash := range executionsFromAddress {
+	for i, executionHash := range executionHashes {
 		if int64(i) < offset {
 			continue
 		}
 
-		if int64(len(res)) >= limit {
-			break
-		}
-
 		explorerExecution, err := getExecution(exp.bc, exp.ap, executionHash)
 		if err != nil {
 			return []explorer.Execution{}, err
@@ -512,6 +660,28 @@ func (exp *Service) GetExecutionsByAddress(address string, offset int64, limit i
 	return res, nil
 }
 
+// GetExecutionsByAddressPage returns a page of executions associated with an address in reverse chronological order
+func (exp *Service) GetExecutionsByAddressPage(request explorer.AddressHistoryRequest) (explorer.ExecutionPage, error) {
+	query, err := convertAddressHistoryRequest(request)
+	if err != nil {
+		return explorer.ExecutionPage{}, err
+	}
+	executionHashes, cursor, err := exp.bc.GetExecutionsByAddress(request.Address, query)
+	if err != nil {
+		return explorer.ExecutionPage{}, err
+	}
+	res := explorer.ExecutionPage{Executions: []explorer.Execution{}, Cursor: cursor}
+	for _, executionHash := range executionHashes {
+		explorerExecution, err := getExecution(exp.bc, exp.ap, executionHash)
+		if err != nil {
+			return explorer.ExecutionPage{}, err
+		}
+		res.Executions = append(res.Executions, explorerExecution)
+	}
+
+	return res, nil
+}
+
 // GetUnconfirmedExecutionsByAddress returns all unconfirmed executions in actpool associated with an address
 func (exp *Service) GetUnconfirmedExecutionsByAddress(address string, offset int64, limit int64) ([]explorer.Execution, error) {
 	res := make([]explorer.Execution, 0)
@@ -598,6 +768,75 @@ func (exp *Service) GetReceiptByExecutionID(id string) (explorer.Receipt, error)
 	return convertReceiptToExplorerReceipt(receipt)
 }
 
+// GetReceiptByActionID gets receipt with corresponding action id of any type
+func (exp *Service) GetReceiptByActionID(id string) (explorer.Receipt, error) {
+	bytes, err := hex.DecodeString(id)
+	if err != nil {
+		return explorer.Receipt{}, err
+	}
+	var actionHash hash.Hash32B
+	copy(actionHash[:], bytes)
+	receipt, err := exp.bc.GetReceiptByActionHash(actionHash)
+	if err != nil {
+		return explorer.Receipt{}, err
+	}
+
+	return convertReceiptToExplorerReceipt(receipt)
+}
+
+// GetLogs returns the logs emitted by contracts in a height range, filtered by contract addresses and topics at each
+// position
+func (exp *Service) GetLogs(filter explorer.LogFilter) ([]explorer.Log, error) {
+	logFilter, err := convertExplorerLogFilter(filter)
+	if err != nil {
+		return []explorer.Log{}, err
+	}
+	logs, err := exp.bc.GetLogs(logFilter)
+	if err != nil {
+		return []explorer.Log{}, err
+	}
+	res := []explorer.Log{}
+	for _, log := range logs {
+		res = append(res, convertLogToExplorerLog(log))
+	}
+	return res, nil
+}
+
+// GetActionByID returns tx or action of any type by action id
+func (exp *Service) GetActionByID(actionID string) (explorer.Action, error) {
+	bytes, err := hex.DecodeString(actionID)
+	if err != nil {
+		return explorer.Action{}, err
+	}
+	var actionHash hash.Hash32B
+	copy(actionHash[:], bytes)
+
+	return getAction(exp.bc, exp.ap, actionHash)
+}
+
+// GetActionsByAddress returns a page of txs and actions of any type associated with an address in reverse chronological
+// order
+func (exp *Service) GetActionsByAddress(request explorer.AddressHistoryRequest) (explorer.ActionPage, error) {
+	query, err := convertAddressHistoryRequest(request)
+	if err != nil {
+		return explorer.ActionPage{}, err
+	}
+	actionHashes, cursor, err := exp.bc.GetActionsByAddress(request.Address, query)
+	if err != nil {
+		return explorer.ActionPage{}, err
+	}
+	res := explorer.ActionPage{Actions: []explorer.Action{}, Cursor: cursor}
+	for _, actionHash := range actionHashes {
+		explorerAction, err := getAction(exp.bc, exp.ap, actionHash)
+		if err != nil {
+			return explorer.ActionPage{}, err
+		}
+		res.Actions = append(res.Actions, explorerAction)
+	}
+
+	return res, nil
+}
+
 // GetLastBlocksByRange get block with height [offset-limit+1, offset]
 func (exp *Service) GetLastBlocksByRange(offset int64, limit int64) ([]explorer.Block, error) {
 	var res []explorer.Block
@@ -890,9 +1129,10 @@ func (exp *Service) SendTransfer(tsfJSON explorer.SendTransferRequest) (resp exp
 		GasPrice:  big.NewInt(tsfJSON.GasPrice).Bytes(),
 		Signature: signature,
 	}
-	// broadcast to the network
-	if err = exp.p2p.Broadcast(exp.bc.ChainID(), actPb); err != nil {
-		return explorer.SendTransferResponse{}, err
+	// broadcast to the network. The action is still put into actpool if the broadcast fails, from which it is
+	// rebroadcast later
+	if err := exp.p2p.Broadcast(exp.bc.ChainID(), actPb); err != nil {
+		logger.Warn().Err(err).Msg("Failed to broadcast transfer")
 	}
 	// send to actpool via dispatcher
 	exp.dp.HandleBroadcast(exp.bc.ChainID(), actPb, nil)
@@ -900,6 +1140,8 @@ func (exp *Service) SendTransfer(tsfJSON explorer.SendTransferRequest) (resp exp
 	tsf := &action.Transfer{}
 	tsf.ConvertFromActionPb(actPb)
 	h := tsf.Hash()
+	// rebroadcast the action later in case it fails to propagate
+	exp.ap.MarkLocal(h)
 	return explorer.SendTransferResponse{Hash: hex.EncodeToString(h[:])}, nil
 }
 
@@ -937,9 +1179,10 @@ func (exp *Service) SendVote(voteJSON explorer.SendVoteRequest) (resp explorer.S
 		GasPrice:  big.NewInt(voteJSON.GasPrice).Bytes(),
 		Signature: signature,
 	}
-	// broadcast to the network
-	if err = exp.p2p.Broadcast(exp.bc.ChainID(), actPb); err != nil {
-		return explorer.SendVoteResponse{}, err
+	// broadcast to the network. The action is still put into actpool if the broadcast fails, from which it is
+	// rebroadcast later
+	if err := exp.p2p.Broadcast(exp.bc.ChainID(), actPb); err != nil {
+		logger.Warn().Err(err).Msg("Failed to broadcast vote")
 	}
 	// send to actpool via dispatcher
 	exp.dp.HandleBroadcast(exp.bc.ChainID(), actPb, nil)
@@ -947,6 +1190,8 @@ func (exp *Service) SendVote(voteJSON explorer.SendVoteRequest) (resp explorer.S
 	v := &action.Vote{}
 	v.ConvertFromActionPb(actPb)
 	h := v.Hash()
+	// rebroadcast the action later in case it fails to propagate
+	exp.ap.MarkLocal(h)
 	return explorer.SendVoteResponse{Hash: hex.EncodeToString(h[:])}, nil
 }
 
@@ -1004,9 +1249,10 @@ func (exp *Service) SendSmartContract(execution explorer.Execution) (resp explor
 		GasPrice:  big.NewInt(execution.GasPrice).Bytes(),
 		Signature: signature,
 	}
-	// broadcast to the network
-	if err = exp.p2p.Broadcast(exp.bc.ChainID(), actPb); err != nil {
-		return explorer.SendSmartContractResponse{}, err
+	// broadcast to the network. The action is still put into actpool if the broadcast fails, from which it is
+	// rebroadcast later
+	if err := exp.p2p.Broadcast(exp.bc.ChainID(), actPb); err != nil {
+		logger.Warn().Err(err).Msg("Failed to broadcast execution")
 	}
 	// send to actpool via dispatcher
 	exp.dp.HandleBroadcast(exp.bc.ChainID(), actPb, nil)
@@ -1014,6 +1260,8 @@ func (exp *Service) SendSmartContract(execution explorer.Execution) (resp explor
 	sc := &action.Execution{}
 	sc.ConvertFromActionPb(actPb)
 	h := sc.Hash()
+	// rebroadcast the action later in case it fails to propagate
+	exp.ap.MarkLocal(h)
 	return explorer.SendSmartContractResponse{Hash: hex.EncodeToString(h[:])}, nil
 }
 
@@ -1021,38 +1269,56 @@ func (exp *Service) SendSmartContract(execution explorer.Execution) (resp explor
 func (exp *Service) ReadExecutionState(execution explorer.Execution) (string, error) {
 	logger.Debug().Msg("receive read smart contract request")
 
-	data, err := hex.DecodeString(execution.Data)
+	sc, err := convertExplorerExecution(execution)
 	if err != nil {
 		return "", err
 	}
-	signature, err := hex.DecodeString(execution.Signature)
+	res, err := exp.bc.ExecuteContractRead(sc)
 	if err != nil {
 		return "", err
 	}
-	actPb := &pb.ActionPb{
-		Action: &pb.ActionPb_Execution{
-			Execution: &pb.ExecutionPb{
-				Amount:         big.NewInt(execution.Amount).Bytes(),
-				Executor:       execution.Executor,
-				Contract:       execution.Contract,
-				ExecutorPubKey: nil,
-				Data:           data,
-			},
-		},
-		Version:   uint32(execution.Version),
-		Nonce:     uint64(execution.Nonce),
-		GasLimit:  uint64(execution.GasLimit),
-		GasPrice:  big.NewInt(execution.GasPrice).Bytes(),
-		Signature: signature,
+	return hex.EncodeToString(res), nil
+}
+
+// EstimateGasForSmartContract estimates the minimal gas limit with which an execution succeeds
+func (exp *Service) EstimateGasForSmartContract(execution explorer.Execution) (int64, error) {
+	logger.Debug().Msg("receive estimate gas request")
+
+	sc, err := convertExplorerExecution(execution)
+	if err != nil {
+		return 0, err
 	}
+	gas, err := exp.bc.EstimateGas(sc)
+	if err != nil {
+		return 0, err
+	}
+	return int64(gas), nil
+}
 
-	sc := &action.Execution{}
-	sc.ConvertFromActionPb(actPb)
-	res, err := exp.bc.ExecuteContractRead(sc)
+// TraceExecution re-runs an execution and returns the opcode-level trace of it, only if debug is enabled
+func (exp *Service) TraceExecution(id string) (explorer.ExecutionTrace, error) {
+	if !exp.cfg.DebugEnabled {
+		return explorer.ExecutionTrace{}, errors.New("debug not enabled")
+	}
+	bytes, err := hex.DecodeString(id)
 	if err != nil {
-		return "", err
+		return explorer.ExecutionTrace{}, err
 	}
-	return hex.EncodeToString(res), nil
+	var executionHash hash.Hash32B
+	copy(executionHash[:], bytes)
+	trace, err := exp.bc.TraceExecution(executionHash)
+	if err != nil {
+		return explorer.ExecutionTrace{}, err
+	}
+	receipt, err := convertReceiptToExplorerReceipt(trace.Receipt)
+	if err != nil {
+		return explorer.ExecutionTrace{}, err
+	}
+	res := explorer.ExecutionTrace{Receipt: receipt, Steps: []explorer.TraceStep{}}
+	for _, step := range trace.Steps {
+		res.Steps = append(res.Steps, convertTraceStepToExplorerTraceStep(step))
+	}
+	return res, nil
 }
 
 // GetBlockOrActionByHash get block or action by a hash
@@ -1073,6 +1339,10 @@ func (exp *Service) GetBlockOrActionByHash(hashStr string) (explorer.GetBlkOrAct
 		return explorer.GetBlkOrActResponse{Execution: &exe}, nil
 	}
 
+	if act, err := exp.GetActionByID(hashStr); err == nil {
+		return explorer.GetBlkOrActResponse{Action: &act}, nil
+	}
+
 	return explorer.GetBlkOrActResponse{}, nil
 }
 
@@ -1175,6 +1445,37 @@ func getExecution(bc blockchain.Blockchain, ap actpool.ActPool, executionHash ha
 	return explorerExecution, nil
 }
 
+// getAction takes in a blockchain and actionHash and returns an Explorer Action
+func getAction(bc blockchain.Blockchain, ap actpool.ActPool, actionHash hash.Hash32B) (explorer.Action, error) {
+	act, err := bc.GetActionByHash(actionHash)
+	if err != nil {
+		// Try to fetch pending action from actpool
+		act, err := ap.GetActionByHash(actionHash)
+		if err != nil || act == nil {
+			return explorer.Action{}, err
+		}
+		return convertActionToExplorerAction(act, true)
+	}
+
+	// Fetch from block
+	blkHash, err := bc.GetBlockHashByActionHash(actionHash)
+	if err != nil {
+		return explorer.Action{}, err
+	}
+	blk, err := bc.GetBlockByHash(blkHash)
+	if err != nil {
+		return explorer.Action{}, err
+	}
+
+	explorerAction, err := convertActionToExplorerAction(act, false)
+	if err != nil {
+		return explorerAction, errors.Wrapf(err, "failed to convert action %x to explorer's JSON action", actionHash)
+	}
+	explorerAction.Timestamp = int64(blk.ConvertToBlockHeaderPb().Timestamp)
+	explorerAction.BlockID = hex.EncodeToString(blkHash[:])
+	return explorerAction, nil
+}
+
 func convertTsfToExplorerTsf(transfer *action.Transfer, isPending bool) (explorer.Transfer, error) {
 	if transfer == nil {
 		return explorer.Transfer{}, errors.Wrap(ErrTransfer, "transfer cannot be nil")
@@ -1241,25 +1542,45 @@ func convertExecutionToExplorerExecution(execution *action.Execution, isPending
 	return explorerExecution, nil
 }
 
+// convertExplorerExecution converts an explorer execution to an action execution which is not broadcast
+func convertExplorerExecution(execution explorer.Execution) (*action.Execution, error) {
+	data, err := hex.DecodeString(execution.Data)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := hex.DecodeString(execution.Signature)
+	if err != nil {
+		return nil, err
+	}
+	actPb := &pb.ActionPb{
+		Action: &pb.ActionPb_Execution{
+			Execution: &pb.ExecutionPb{
+				Amount:         big.NewInt(execution.Amount).Bytes(),
+				Executor:       execution.Executor,
+				Contract:       execution.Contract,
+				ExecutorPubKey: nil,
+				Data:           data,
+			},
+		},
+		Version:   uint32(execution.Version),
+		Nonce:     uint64(execution.Nonce),
+		GasLimit:  uint64(execution.GasLimit),
+		GasPrice:  big.NewInt(execution.GasPrice).Bytes(),
+		Signature: signature,
+	}
+
+	sc := &action.Execution{}
+	sc.ConvertFromActionPb(actPb)
+	return sc, nil
+}
+
 func convertReceiptToExplorerReceipt(receipt *blockchain.Receipt) (explorer.Receipt, error) {
 	if receipt == nil {
 		return explorer.Receipt{}, errors.Wrap(ErrReceipt, "receipt cannot be nil")
 	}
 	logs := []explorer.Log{}
 	for _, log := range receipt.Logs {
-		topics := []string{}
-		for _, topic := range log.Topics {
-			topics = append(topics, hex.EncodeToString(topic[:]))
-		}
-		logs = append(logs, explorer.Log{
-			Address:     log.Address,
-			Topics:      topics,
-			Data:        hex.EncodeToString(log.Data),
-			BlockNumber: int64(log.BlockNumber),
-			TxnHash:     hex.EncodeToString(log.TxnHash[:]),
-			BlockHash:   hex.EncodeToString(log.BlockHash[:]),
-			Index:       int64(log.Index),
-		})
+		logs = append(logs, convertLogToExplorerLog(log))
 	}
 
 	return explorer.Receipt{
@@ -1269,5 +1590,137 @@ func convertReceiptToExplorerReceipt(receipt *blockchain.Receipt) (explorer.Rece
 		GasConsumed:     int64(receipt.GasConsumed),
 		ContractAddress: receipt.ContractAddress,
 		Logs:            logs,
+		ErrorCode:       int64(receipt.ErrorCode),
+	}, nil
+}
+
+func convertLogToExplorerLog(log *blockchain.Log) explorer.Log {
+	topics := []string{}
+	for _, topic := range log.Topics {
+		topics = append(topics, hex.EncodeToString(topic[:]))
+	}
+	return explorer.Log{
+		Address:     log.Address,
+		Topics:      topics,
+		Data:        hex.EncodeToString(log.Data),
+		BlockNumber: int64(log.BlockNumber),
+		TxnHash:     hex.EncodeToString(log.TxnHash[:]),
+		BlockHash:   hex.EncodeToString(log.BlockHash[:]),
+		Index:       int64(log.Index),
+	}
+}
+
+func convertTraceStepToExplorerTraceStep(step *blockchain.TraceStep) explorer.TraceStep {
+	stack := []string{}
+	for _, value := range step.Stack {
+		stack = append(stack, hex.EncodeToString(value.Bytes()))
+	}
+	storage := []explorer.StorageEntry{}
+	for key, value := range step.Storage {
+		storage = append(storage, explorer.StorageEntry{
+			Key:   hex.EncodeToString(key[:]),
+			Value: hex.EncodeToString(value[:]),
+		})
+	}
+	// map iteration order is random
+	sort.Slice(storage, func(i, j int) bool { return storage[i].Key < storage[j].Key })
+	return explorer.TraceStep{
+		Pc:      int64(step.PC),
+		Op:      step.Op,
+		Gas:     int64(step.Gas),
+		GasCost: int64(step.GasCost),
+		Depth:   int64(step.Depth),
+		Stack:   stack,
+		Memory:  hex.EncodeToString(step.Memory),
+		Storage: storage,
+		Error:   step.Err,
+	}
+}
+
+func convertExplorerLogFilter(filter explorer.LogFilter) (*blockchain.LogFilter, error) {
+	if filter.FromHeight < 0 || filter.ToHeight < 0 {
+		return nil, errors.New("invalid height range")
+	}
+	logFilter := &blockchain.LogFilter{
+		FromHeight: uint64(filter.FromHeight),
+		ToHeight:   uint64(filter.ToHeight),
+		Addresses:  filter.Addresses,
+	}
+	for _, logTopics := range filter.Topics {
+		topics := []hash.Hash32B{}
+		for _, topic := range logTopics.Topics {
+			bytes, err := hex.DecodeString(topic)
+			if err != nil {
+				return nil, err
+			}
+			if len(bytes) != hash.HashSize {
+				return nil, errors.Errorf("invalid topic %s", topic)
+			}
+			var h hash.Hash32B
+			copy(h[:], bytes)
+			topics = append(topics, h)
+		}
+		logFilter.Topics = append(logFilter.Topics, topics)
+	}
+	return logFilter, nil
+}
+
+func convertAddressHistoryRequest(request explorer.AddressHistoryRequest) (*blockchain.HistoryQuery, error) {
+	if request.Limit <= 0 {
+		return nil, errors.Errorf("invalid limit %d", request.Limit)
+	}
+	if request.StartHeight < 0 || request.EndHeight < 0 || request.StartTime < 0 || request.EndTime < 0 {
+		return nil, errors.New("invalid height or time range")
+	}
+	return &blockchain.HistoryQuery{
+		Cursor:      request.Cursor,
+		Limit:       uint64(request.Limit),
+		StartHeight: uint64(request.StartHeight),
+		EndHeight:   uint64(request.EndHeight),
+		StartTime:   uint64(request.StartTime),
+		EndTime:     uint64(request.EndTime),
 	}, nil
 }
+
+func convertActionToExplorerAction(act action.Action, isPending bool) (explorer.Action, error) {
+	if act == nil {
+		return explorer.Action{}, errors.New("action cannot be nil")
+	}
+	hash := act.Hash()
+	explorerAction := explorer.Action{
+		Version:      int64(act.Version()),
+		ID:           hex.EncodeToString(hash[:]),
+		Type:         actionType(act),
+		Nonce:        int64(act.Nonce()),
+		Sender:       act.SrcAddr(),
+		Recipient:    act.DstAddr(),
+		SenderPubKey: keypair.EncodePublicKey(act.SrcPubkey()),
+		GasLimit:     int64(act.GasLimit()),
+		Signature:    hex.EncodeToString(act.Signature()),
+		IsPending:    isPending,
+	}
+	if act.GasPrice() != nil && len(act.GasPrice().Bytes()) > 0 {
+		explorerAction.GasPrice = act.GasPrice().Int64()
+	}
+	return explorerAction, nil
+}
+
+func actionType(act action.Action) string {
+	switch act.(type) {
+	case *action.Transfer:
+		return "transfer"
+	case *action.Vote:
+		return "vote"
+	case *action.Execution:
+		return "execution"
+	case *action.SecretProposal:
+		return "secretProposal"
+	case *action.SecretWitness:
+		return "secretWitness"
+	case *action.StartSubChain:
+		return "startSubChain"
+	case *action.StopSubChain:
+		return "stopSubChain"
+	}
+	return "unknown"
+}
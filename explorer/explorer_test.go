@@ -18,16 +18,21 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
 
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus/scheme"
+	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/explorer/idl/explorer"
 	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
 	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
 	"github.com/iotexproject/iotex-core/test/mock/mock_consensus"
 	"github.com/iotexproject/iotex-core/test/mock/mock_dispatcher"
@@ -49,9 +54,10 @@ const (
 )
 
 func addTestingBlocks(bc blockchain.Blockchain) error {
+	// the accounts are funded with the gas fees of the transfers and votes they send, which are 100000 each
 	// Add block 1
 	// test --> A, B, C, D, E, F
-	tsf, _ := action.NewTransfer(1, big.NewInt(10), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["charlie"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	tsf, _ := action.NewTransfer(1, big.NewInt(700010), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["charlie"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	if err := action.Sign(tsf, ta.Addrinfo["producer"].PrivateKey); err != nil {
 		return err
 	}
@@ -68,7 +74,7 @@ func addTestingBlocks(bc blockchain.Blockchain) error {
 
 	// Add block 2
 	// Charlie --> A, B, D, E, test
-	tsf1, _ := action.NewTransfer(1, big.NewInt(1), ta.Addrinfo["charlie"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	tsf1, _ := action.NewTransfer(1, big.NewInt(100001), ta.Addrinfo["charlie"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	_ = action.Sign(tsf1, ta.Addrinfo["charlie"].PrivateKey)
 	tsf2, _ := action.NewTransfer(2, big.NewInt(1), ta.Addrinfo["charlie"].RawAddress, ta.Addrinfo["bravo"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	_ = action.Sign(tsf2, ta.Addrinfo["charlie"].PrivateKey)
@@ -213,6 +219,63 @@ func TestExplorerApi(t *testing.T) {
 	require.Nil(err)
 	require.Equal(1, len(executions))
 
+	// page through the transfers of charlie
+	transferPage, err := svc.GetTransfersByAddressPage(explorer.AddressHistoryRequest{
+		Address: ta.Addrinfo["charlie"].RawAddress,
+		Limit:   3,
+	})
+	require.NoError(err)
+	require.Equal(3, len(transferPage.Transfers))
+	require.NotEmpty(transferPage.Cursor)
+	transfers, err = svc.GetTransfersByAddress(ta.Addrinfo["charlie"].RawAddress, 3, 10)
+	require.NoError(err)
+	transferPage, err = svc.GetTransfersByAddressPage(explorer.AddressHistoryRequest{
+		Address: ta.Addrinfo["charlie"].RawAddress,
+		Cursor:  transferPage.Cursor,
+		Limit:   3,
+	})
+	require.NoError(err)
+	require.Equal(transfers, transferPage.Transfers)
+	require.Empty(transferPage.Cursor)
+
+	votePage, err := svc.GetVotesByAddressPage(explorer.AddressHistoryRequest{
+		Address:   ta.Addrinfo["charlie"].RawAddress,
+		Limit:     10,
+		EndHeight: 1,
+	})
+	require.NoError(err)
+	require.Equal(0, len(votePage.Votes))
+	require.Empty(votePage.Cursor)
+
+	executionPage, err := svc.GetExecutionsByAddressPage(explorer.AddressHistoryRequest{
+		Address: ta.Addrinfo["charlie"].RawAddress,
+		Limit:   10,
+	})
+	require.NoError(err)
+	require.Equal(2, len(executionPage.Executions))
+
+	_, err = svc.GetTransfersByAddressPage(explorer.AddressHistoryRequest{Address: ta.Addrinfo["charlie"].RawAddress})
+	require.Error(err)
+	_, err = svc.GetTransfersByAddressPage(explorer.AddressHistoryRequest{
+		Address: ta.Addrinfo["charlie"].RawAddress,
+		Cursor:  "invalid",
+		Limit:   3,
+	})
+	require.Error(err)
+
+	// txs of all types are indexed by address
+	actionPage, err := svc.GetActionsByAddress(explorer.AddressHistoryRequest{
+		Address: ta.Addrinfo["charlie"].RawAddress,
+		Limit:   100,
+	})
+	require.NoError(err)
+	require.Equal(10, len(actionPage.Actions))
+	require.Empty(actionPage.Cursor)
+	act, err := svc.GetActionByID(executionPage.Executions[0].ID)
+	require.NoError(err)
+	require.Equal("execution", act.Type)
+	require.Equal(executionPage.Executions[0].BlockID, act.BlockID)
+
 	transfers, err = svc.GetLastTransfersByRange(4, 1, 3, true)
 	require.Equal(3, len(transfers))
 	require.Nil(err)
@@ -351,6 +414,80 @@ func TestExplorerApi(t *testing.T) {
 	_, err = svc.GetAddressDetails("")
 	require.Error(err)
 
+	// success
+	accountProof, err := svc.GetAccountProof(ta.Addrinfo["charlie"].RawAddress, int64(bc.TipHeight()))
+	require.Nil(err)
+	require.Equal(int64(bc.TipHeight()), accountProof.BlockHeight)
+	tipHash, err := bc.GetHashByHeight(bc.TipHeight())
+	require.Nil(err)
+	require.Equal(hex.EncodeToString(tipHash[:]), accountProof.BlockID)
+	stateRoot, err := hex.DecodeString(accountProof.StateRoot)
+	require.Nil(err)
+	proof := [][]byte{}
+	for _, node := range accountProof.Proof {
+		n, err := hex.DecodeString(node)
+		require.Nil(err)
+		proof = append(proof, n)
+	}
+	provedState, err := state.VerifyStateProof(
+		byteutil.BytesTo32B(stateRoot), ta.Addrinfo["charlie"].RawAddress, proof)
+	require.Nil(err)
+	require.Equal(big.NewInt(6), provedState.Balance)
+
+	// error
+	_, err = svc.GetAccountProof("", int64(bc.TipHeight()))
+	require.Error(err)
+	_, err = svc.GetAccountProof(ta.Addrinfo["charlie"].RawAddress, -1)
+	require.Error(err)
+
+	// success
+	for _, id := range []string{votes[0].ID, executions[0].ID} {
+		actionProof, err := svc.GetActionProof(id)
+		require.Nil(err)
+		header, err := hex.DecodeString(actionProof.BlockHeader)
+		require.Nil(err)
+		blkHash := blake2b.Sum256(header)
+		require.Equal(actionProof.BlockID, hex.EncodeToString(blkHash[:]))
+		txRoot, err := hex.DecodeString(actionProof.TxRoot)
+		require.Nil(err)
+		actionHash, err := hex.DecodeString(id)
+		require.Nil(err)
+		path := []hash.Hash32B{}
+		for _, sibling := range actionProof.Path {
+			h, err := hex.DecodeString(sibling)
+			require.Nil(err)
+			path = append(path, byteutil.BytesTo32B(h))
+		}
+		require.True(crypto.VerifyAuditPath(
+			byteutil.BytesTo32B(txRoot), byteutil.BytesTo32B(actionHash), int(actionProof.Index), path))
+	}
+
+	// error
+	_, err = svc.GetActionProof("")
+	require.Error(err)
+
+	// success
+	receiptProof, err := svc.GetReceiptProof(executions[0].ID)
+	require.Nil(err)
+	require.Equal(executions[0].ID, receiptProof.Receipt.Hash)
+	receiptRoot, err := hex.DecodeString(receiptProof.ReceiptRoot)
+	require.Nil(err)
+	receiptHash, err := hex.DecodeString(receiptProof.ReceiptHash)
+	require.Nil(err)
+	path := []hash.Hash32B{}
+	for _, sibling := range receiptProof.Path {
+		h, err := hex.DecodeString(sibling)
+		require.Nil(err)
+		path = append(path, byteutil.BytesTo32B(h))
+	}
+	require.NotEqual(hash.ZeroHash32B, byteutil.BytesTo32B(receiptRoot))
+	require.True(crypto.VerifyAuditPath(
+		byteutil.BytesTo32B(receiptRoot), byteutil.BytesTo32B(receiptHash), int(receiptProof.Index), path))
+
+	// error
+	_, err = svc.GetReceiptProof(votes[0].ID)
+	require.Error(err)
+
 	tip, err := svc.GetBlockchainHeight()
 	require.Nil(err)
 	require.Equal(4, int(tip))
@@ -458,6 +595,40 @@ func TestService_StateByAddr(t *testing.T) {
 	require.Equal("456", state.Votee)
 }
 
+func TestService_StateAtHeight(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := state.State{
+		Balance:      big.NewInt(46),
+		Nonce:        uint64(3),
+		IsCandidate:  true,
+		VotingWeight: big.NewInt(100),
+	}
+
+	mBc := mock_blockchain.NewMockBlockchain(ctrl)
+	mBc.EXPECT().BalanceAtHeight("123", uint64(5)).Times(1).Return(big.NewInt(46), nil)
+	mBc.EXPECT().StateAtHeight("123", uint64(5)).Times(1).Return(&s, nil)
+	svc := Service{bc: mBc}
+
+	balance, err := svc.GetAddressBalanceAtHeight("123", 5)
+	require.Nil(err)
+	require.Equal(int64(46), balance)
+
+	details, err := svc.GetAddressDetailsAtHeight("123", 5)
+	require.Nil(err)
+	require.Equal("123", details.Address)
+	require.Equal(int64(46), details.TotalBalance)
+	require.Equal(int64(3), details.Nonce)
+	require.Equal(int64(4), details.PendingNonce)
+	require.True(details.IsCandidate)
+
+	_, err = svc.GetAddressBalanceAtHeight("123", -1)
+	require.Error(err)
+}
+
 func TestService_GetConsensusMetrics(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -520,7 +691,8 @@ func TestService_SendTransfer(t *testing.T) {
 	chain := mock_blockchain.NewMockBlockchain(ctrl)
 	mDp := mock_dispatcher.NewMockDispatcher(ctrl)
 	p2p := mock_network.NewMockOverlay(ctrl)
-	svc := Service{bc: chain, dp: mDp, p2p: p2p}
+	ap := mock_actpool.NewMockActPool(ctrl)
+	svc := Service{bc: chain, dp: mDp, p2p: p2p, ap: ap}
 
 	request := explorer.SendTransferRequest{}
 	response, err := svc.SendTransfer(request)
@@ -530,6 +702,7 @@ func TestService_SendTransfer(t *testing.T) {
 	chain.EXPECT().ChainID().Return(uint32(1)).Times(2)
 	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Times(1)
+	ap.EXPECT().MarkLocal(gomock.Any()).Times(1)
 
 	r := explorer.SendTransferRequest{
 		Version:      0x1,
@@ -555,7 +728,8 @@ func TestService_SendVote(t *testing.T) {
 	chain := mock_blockchain.NewMockBlockchain(ctrl)
 	mDp := mock_dispatcher.NewMockDispatcher(ctrl)
 	p2p := mock_network.NewMockOverlay(ctrl)
-	svc := Service{bc: chain, dp: mDp, p2p: p2p}
+	ap := mock_actpool.NewMockActPool(ctrl)
+	svc := Service{bc: chain, dp: mDp, p2p: p2p, ap: ap}
 
 	request := explorer.SendVoteRequest{}
 	response, err := svc.SendVote(request)
@@ -565,6 +739,7 @@ func TestService_SendVote(t *testing.T) {
 	chain.EXPECT().ChainID().Return(uint32(1)).Times(2)
 	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Times(1)
+	ap.EXPECT().MarkLocal(gomock.Any()).Times(1)
 
 	r := explorer.SendVoteRequest{
 		Version:     0x1,
@@ -589,7 +764,8 @@ func TestService_SendSmartContract(t *testing.T) {
 	chain := mock_blockchain.NewMockBlockchain(ctrl)
 	mDp := mock_dispatcher.NewMockDispatcher(ctrl)
 	p2p := mock_network.NewMockOverlay(ctrl)
-	svc := Service{bc: chain, dp: mDp, p2p: p2p}
+	ap := mock_actpool.NewMockActPool(ctrl)
+	svc := Service{bc: chain, dp: mDp, p2p: p2p, ap: ap}
 
 	execution, _ := action.NewExecution(ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["delta"].RawAddress, 1, big.NewInt(1), 1000000, big.NewInt(10), []byte{1})
 	_ = action.Sign(execution, ta.Addrinfo["producer"].PrivateKey)
@@ -601,12 +777,179 @@ func TestService_SendSmartContract(t *testing.T) {
 	chain.EXPECT().ChainID().Return(uint32(1)).Times(2)
 	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Times(1)
+	ap.EXPECT().MarkLocal(gomock.Any()).Times(1)
 
 	response, err := svc.SendSmartContract(explorerExecution)
 	require.NotNil(response.Hash)
 	require.Nil(err)
 }
 
+func TestService_SendActionsWithFailingBroadcast(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	mDp := mock_dispatcher.NewMockDispatcher(ctrl)
+	p2p := mock_network.NewMockOverlay(ctrl)
+	ap := mock_actpool.NewMockActPool(ctrl)
+	svc := Service{bc: chain, dp: mDp, p2p: p2p, ap: ap}
+
+	// the actions are put into actpool and marked to rebroadcast even if the overlay fails to broadcast them
+	chain.EXPECT().ChainID().Return(uint32(1)).Times(6)
+	p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Return(errors.New("failed to broadcast")).Times(3)
+	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any()).Times(3)
+	ap.EXPECT().MarkLocal(gomock.Any()).Times(3)
+
+	tsfResponse, err := svc.SendTransfer(explorer.SendTransferRequest{
+		Version:      0x1,
+		Nonce:        1,
+		Sender:       senderRawAddr,
+		Recipient:    recipientRawAddr,
+		Amount:       1,
+		SenderPubKey: senderPubKey,
+	})
+	require.NoError(err)
+	require.NotEmpty(tsfResponse.Hash)
+
+	voteResponse, err := svc.SendVote(explorer.SendVoteRequest{
+		Version:     0x1,
+		Nonce:       1,
+		Voter:       senderRawAddr,
+		Votee:       senderRawAddr,
+		VoterPubKey: senderPubKey,
+	})
+	require.NoError(err)
+	require.NotEmpty(voteResponse.Hash)
+
+	execution, err := action.NewExecution(ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["delta"].RawAddress, 1,
+		big.NewInt(1), 1000000, big.NewInt(10), []byte{1})
+	require.NoError(err)
+	require.NoError(action.Sign(execution, ta.Addrinfo["producer"].PrivateKey))
+	explorerExecution, err := convertExecutionToExplorerExecution(execution, true)
+	require.NoError(err)
+	explorerExecution.Version = int64(execution.Version())
+	explorerExecution.ExecutorPubKey = keypair.EncodePublicKey(execution.ExecutorPublicKey())
+	explorerExecution.Signature = hex.EncodeToString(execution.Signature())
+	scResponse, err := svc.SendSmartContract(explorerExecution)
+	require.NoError(err)
+	require.NotEmpty(scResponse.Hash)
+}
+
+func TestService_GetLogs(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	svc := Service{bc: chain}
+
+	topic := hash.Hash256b([]byte("Transfer"))
+	log := &blockchain.Log{Address: "token", Topics: []hash.Hash32B{byteutil.BytesTo32B(topic)}, BlockNumber: 3}
+	chain.EXPECT().GetLogs(&blockchain.LogFilter{
+		FromHeight: 1,
+		ToHeight:   5,
+		Addresses:  []string{"token"},
+		Topics:     [][]hash.Hash32B{{}, {byteutil.BytesTo32B(topic)}},
+	}).Return([]*blockchain.Log{log}, nil).Times(1)
+	logs, err := svc.GetLogs(explorer.LogFilter{
+		FromHeight: 1,
+		ToHeight:   5,
+		Addresses:  []string{"token"},
+		Topics:     []explorer.LogTopics{{}, {Topics: []string{hex.EncodeToString(topic)}}},
+	})
+	require.Nil(err)
+	require.Equal(1, len(logs))
+	require.Equal("token", logs[0].Address)
+	require.Equal([]string{hex.EncodeToString(topic)}, logs[0].Topics)
+	require.Equal(int64(3), logs[0].BlockNumber)
+
+	_, err = svc.GetLogs(explorer.LogFilter{Topics: []explorer.LogTopics{{Topics: []string{"0102"}}}})
+	require.Error(err)
+	_, err = svc.GetLogs(explorer.LogFilter{FromHeight: -1})
+	require.Error(err)
+}
+
+func TestService_EstimateGasForSmartContract(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	svc := Service{bc: chain}
+
+	execution, _ := action.NewExecution(ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["delta"].RawAddress, 1, big.NewInt(1), 1000000, big.NewInt(10), []byte{1})
+	explorerExecution, _ := convertExecutionToExplorerExecution(execution, true)
+
+	chain.EXPECT().EstimateGas(gomock.Any()).Return(uint64(21000), nil).Times(1)
+	gas, err := svc.EstimateGasForSmartContract(explorerExecution)
+	require.Nil(err)
+	require.Equal(int64(21000), gas)
+
+	chain.EXPECT().EstimateGas(gomock.Any()).Return(uint64(0), errors.New("execution fails")).Times(1)
+	_, err = svc.EstimateGasForSmartContract(explorerExecution)
+	require.Error(err)
+
+	explorerExecution.Data = "invalid hex"
+	_, err = svc.EstimateGasForSmartContract(explorerExecution)
+	require.Error(err)
+}
+
+func TestService_TraceExecution(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	svc := Service{bc: chain}
+
+	execution, _ := action.NewExecution(ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["delta"].RawAddress, 1, big.NewInt(1), 1000000, big.NewInt(10), []byte{1})
+	executionHash := execution.Hash()
+	_, err := svc.TraceExecution(hex.EncodeToString(executionHash[:]))
+	require.Error(err)
+
+	svc.cfg.DebugEnabled = true
+	key := byteutil.BytesTo32B(hash.Hash256b([]byte("key")))
+	value := byteutil.BytesTo32B(hash.Hash256b([]byte("value")))
+	trace := &blockchain.ExecutionTrace{
+		Receipt: &blockchain.Receipt{Hash: executionHash, Status: blockchain.SuccessStatus, GasConsumed: 21000},
+		Steps: []*blockchain.TraceStep{{
+			PC:      2,
+			Op:      "SSTORE",
+			Gas:     979000,
+			GasCost: 20000,
+			Depth:   1,
+			Stack:   []*big.Int{big.NewInt(255)},
+			Memory:  []byte{1, 2},
+			Storage: map[hash.Hash32B]hash.Hash32B{key: value},
+		}},
+	}
+	chain.EXPECT().TraceExecution(executionHash).Return(trace, nil).Times(1)
+	res, err := svc.TraceExecution(hex.EncodeToString(executionHash[:]))
+	require.Nil(err)
+	require.Equal(hex.EncodeToString(executionHash[:]), res.Receipt.Hash)
+	require.Equal(int64(21000), res.Receipt.GasConsumed)
+	require.Equal(1, len(res.Steps))
+	step := res.Steps[0]
+	require.Equal(int64(2), step.Pc)
+	require.Equal("SSTORE", step.Op)
+	require.Equal(int64(20000), step.GasCost)
+	require.Equal([]string{"ff"}, step.Stack)
+	require.Equal("0102", step.Memory)
+	require.Equal([]explorer.StorageEntry{{Key: hex.EncodeToString(key[:]), Value: hex.EncodeToString(value[:])}}, step.Storage)
+
+	chain.EXPECT().TraceExecution(gomock.Any()).Return(nil, errors.New("execution not found")).Times(1)
+	_, err = svc.TraceExecution(hex.EncodeToString(executionHash[:]))
+	require.Error(err)
+
+	_, err = svc.TraceExecution("invalid hex")
+	require.Error(err)
+}
+
 func TestServiceGetPeers(t *testing.T) {
 	require := require.New(t)
 
@@ -750,3 +1093,55 @@ func TestExplorerGetReceiptByExecutionID(t *testing.T) {
 	require.NoError(err)
 	require.Equal(eHashStr, receipt.Hash)
 }
+
+func TestExplorerGetReceiptByActionID(t *testing.T) {
+	require := require.New(t)
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+
+	sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, blockchain.Gen.TotalSupply)
+	require.NoError(err)
+	_, err = sf.RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	// Disable block reward to make bookkeeping easier
+	blockchain.Gen.BlockReward = uint64(0)
+
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(&cfg, blockchain.PrecreatedStateFactoryOption(sf), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	svc := Service{bc: bc}
+
+	// alfa has nothing to transfer
+	tsf1, err := action.NewTransfer(1, big.NewInt(10), ta.Addrinfo["producer"].RawAddress,
+		ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(action.Sign(tsf1, ta.Addrinfo["producer"].PrivateKey))
+	tsf2, err := action.NewTransfer(1, big.NewInt(100), ta.Addrinfo["alfa"].RawAddress,
+		ta.Addrinfo["bravo"].RawAddress, []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(action.Sign(tsf2, ta.Addrinfo["alfa"].PrivateKey))
+	blk, err := bc.MintNewBlock([]*action.Transfer{tsf1, tsf2}, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.NoError(bc.CommitBlock(blk))
+
+	tsf1Hash := tsf1.Hash()
+	receipt, err := svc.GetReceiptByActionID(hex.EncodeToString(tsf1Hash[:]))
+	require.NoError(err)
+	require.Equal(int64(blockchain.SuccessStatus), receipt.Status)
+	require.Equal(int64(blockchain.NoErrorCode), receipt.ErrorCode)
+	tsf2Hash := tsf2.Hash()
+	receipt, err = svc.GetReceiptByActionID(hex.EncodeToString(tsf2Hash[:]))
+	require.NoError(err)
+	require.Equal(hex.EncodeToString(tsf2Hash[:]), receipt.Hash)
+	require.Equal(int64(blockchain.FailureStatus), receipt.Status)
+	require.Equal(int64(blockchain.NotEnoughBalanceErrorCode), receipt.ErrorCode)
+	_, err = svc.GetReceiptByActionID("invalid")
+	require.Error(err)
+}
@@ -9,8 +9,8 @@ import (
 )
 
 const BarristerVersion string = "0.1.6"
-const BarristerChecksum string = "97e0f2efe11dee3190b33a6c0cb7fda8"
-const BarristerDateGenerated int64 = 1538418246052000000
+const BarristerChecksum string = "4882827ab8764a2859652378ba76b890"
+const BarristerDateGenerated int64 = 1539628612681000000
 
 type CoinStatistic struct {
 	Height     int64 `json:"height"`
@@ -85,6 +85,17 @@ type Log struct {
 	Index       int64    `json:"index"`
 }
 
+type LogTopics struct {
+	Topics []string `json:"topics"`
+}
+
+type LogFilter struct {
+	FromHeight int64       `json:"fromHeight"`
+	ToHeight   int64       `json:"toHeight"`
+	Addresses  []string    `json:"addresses"`
+	Topics     []LogTopics `json:"topics"`
+}
+
 type Receipt struct {
 	ReturnValue     string `json:"returnValue"`
 	Status          int64  `json:"status"`
@@ -92,6 +103,29 @@ type Receipt struct {
 	GasConsumed     int64  `json:"gasConsumed"`
 	ContractAddress string `json:"contractAddress"`
 	Logs            []Log  `json:"logs"`
+	ErrorCode       int64  `json:"errorCode"`
+}
+
+type StorageEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type TraceStep struct {
+	Pc      int64          `json:"pc"`
+	Op      string         `json:"op"`
+	Gas     int64          `json:"gas"`
+	GasCost int64          `json:"gasCost"`
+	Depth   int64          `json:"depth"`
+	Stack   []string       `json:"stack"`
+	Memory  string         `json:"memory"`
+	Storage []StorageEntry `json:"storage"`
+	Error   string         `json:"error"`
+}
+
+type ExecutionTrace struct {
+	Receipt Receipt     `json:"receipt"`
+	Steps   []TraceStep `json:"steps"`
 }
 
 type SendExecutionResponse struct {
@@ -113,6 +147,27 @@ type Vote struct {
 	IsPending   bool   `json:"isPending"`
 }
 
+type Action struct {
+	Version      int64  `json:"version"`
+	ID           string `json:"ID"`
+	Type         string `json:"type"`
+	Nonce        int64  `json:"nonce"`
+	Sender       string `json:"sender"`
+	Recipient    string `json:"recipient"`
+	SenderPubKey string `json:"senderPubKey"`
+	GasLimit     int64  `json:"gasLimit"`
+	GasPrice     int64  `json:"gasPrice"`
+	Signature    string `json:"signature"`
+	Timestamp    int64  `json:"timestamp"`
+	BlockID      string `json:"blockID"`
+	IsPending    bool   `json:"isPending"`
+}
+
+type ActionPage struct {
+	Actions []Action `json:"actions"`
+	Cursor  string   `json:"cursor"`
+}
+
 type AddressDetails struct {
 	Address      string `json:"address"`
 	TotalBalance int64  `json:"totalBalance"`
@@ -121,6 +176,61 @@ type AddressDetails struct {
 	IsCandidate  bool   `json:"isCandidate"`
 }
 
+type AccountProof struct {
+	Address     string   `json:"address"`
+	BlockID     string   `json:"blockID"`
+	BlockHeight int64    `json:"blockHeight"`
+	StateRoot   string   `json:"stateRoot"`
+	Proof       []string `json:"proof"`
+}
+
+type ActionProof struct {
+	ActionID    string   `json:"actionID"`
+	BlockID     string   `json:"blockID"`
+	BlockHeight int64    `json:"blockHeight"`
+	BlockHeader string   `json:"blockHeader"`
+	TxRoot      string   `json:"txRoot"`
+	Index       int64    `json:"index"`
+	Path        []string `json:"path"`
+}
+
+type ReceiptProof struct {
+	ExecutionID string   `json:"executionID"`
+	BlockID     string   `json:"blockID"`
+	BlockHeight int64    `json:"blockHeight"`
+	BlockHeader string   `json:"blockHeader"`
+	ReceiptRoot string   `json:"receiptRoot"`
+	Receipt     Receipt  `json:"receipt"`
+	ReceiptHash string   `json:"receiptHash"`
+	Index       int64    `json:"index"`
+	Path        []string `json:"path"`
+}
+
+type AddressHistoryRequest struct {
+	Address     string `json:"address"`
+	Cursor      string `json:"cursor"`
+	Limit       int64  `json:"limit"`
+	StartHeight int64  `json:"startHeight"`
+	EndHeight   int64  `json:"endHeight"`
+	StartTime   int64  `json:"startTime"`
+	EndTime     int64  `json:"endTime"`
+}
+
+type TransferPage struct {
+	Transfers []Transfer `json:"transfers"`
+	Cursor    string     `json:"cursor"`
+}
+
+type VotePage struct {
+	Votes  []Vote `json:"votes"`
+	Cursor string `json:"cursor"`
+}
+
+type ExecutionPage struct {
+	Executions []Execution `json:"executions"`
+	Cursor     string      `json:"cursor"`
+}
+
 type Candidate struct {
 	Address          string `json:"address"`
 	PubKey           string `json:"pubKey"`
@@ -195,25 +305,34 @@ type GetBlkOrActResponse struct {
 	Transfer  *Transfer  `json:"transfer,omitempty"`
 	Vote      *Vote      `json:"vote,omitempty"`
 	Execution *Execution `json:"execution,omitempty"`
+	Action    *Action    `json:"action,omitempty"`
 }
 
 type Explorer interface {
 	GetBlockchainHeight() (int64, error)
 	GetAddressBalance(address string) (int64, error)
 	GetAddressDetails(address string) (AddressDetails, error)
+	GetAddressBalanceAtHeight(address string, height int64) (int64, error)
+	GetAddressDetailsAtHeight(address string, height int64) (AddressDetails, error)
+	GetAccountProof(address string, height int64) (AccountProof, error)
+	GetActionProof(actionID string) (ActionProof, error)
+	GetReceiptProof(executionID string) (ReceiptProof, error)
 	GetLastTransfersByRange(startBlockHeight int64, offset int64, limit int64, showCoinBase bool) ([]Transfer, error)
 	GetTransferByID(transferID string) (Transfer, error)
 	GetTransfersByAddress(address string, offset int64, limit int64) ([]Transfer, error)
+	GetTransfersByAddressPage(request AddressHistoryRequest) (TransferPage, error)
 	GetUnconfirmedTransfersByAddress(address string, offset int64, limit int64) ([]Transfer, error)
 	GetTransfersByBlockID(blkID string, offset int64, limit int64) ([]Transfer, error)
 	GetLastVotesByRange(startBlockHeight int64, offset int64, limit int64) ([]Vote, error)
 	GetVoteByID(voteID string) (Vote, error)
 	GetVotesByAddress(address string, offset int64, limit int64) ([]Vote, error)
+	GetVotesByAddressPage(request AddressHistoryRequest) (VotePage, error)
 	GetUnconfirmedVotesByAddress(address string, offset int64, limit int64) ([]Vote, error)
 	GetVotesByBlockID(blkID string, offset int64, limit int64) ([]Vote, error)
 	GetLastExecutionsByRange(startBlockHeight int64, offset int64, limit int64) ([]Execution, error)
 	GetExecutionByID(executionID string) (Execution, error)
 	GetExecutionsByAddress(address string, offset int64, limit int64) ([]Execution, error)
+	GetExecutionsByAddressPage(request AddressHistoryRequest) (ExecutionPage, error)
 	GetUnconfirmedExecutionsByAddress(address string, offset int64, limit int64) ([]Execution, error)
 	GetExecutionsByBlockID(blkID string, offset int64, limit int64) ([]Execution, error)
 	GetLastBlocksByRange(offset int64, limit int64) ([]Block, error)
@@ -227,7 +346,13 @@ type Explorer interface {
 	SendSmartContract(request Execution) (SendSmartContractResponse, error)
 	GetPeers() (GetPeersResponse, error)
 	GetReceiptByExecutionID(id string) (Receipt, error)
+	GetReceiptByActionID(id string) (Receipt, error)
+	GetLogs(filter LogFilter) ([]Log, error)
+	GetActionByID(actionID string) (Action, error)
+	GetActionsByAddress(request AddressHistoryRequest) (ActionPage, error)
 	ReadExecutionState(request Execution) (string, error)
+	EstimateGasForSmartContract(request Execution) (int64, error)
+	TraceExecution(id string) (ExecutionTrace, error)
 	GetBlockOrActionByHash(hashStr string) (GetBlkOrActResponse, error)
 }
 
@@ -294,6 +419,96 @@ func (_p ExplorerProxy) GetAddressDetails(address string) (AddressDetails, error
 	return AddressDetails{}, _err
 }
 
+func (_p ExplorerProxy) GetAddressBalanceAtHeight(address string, height int64) (int64, error) {
+	_res, _err := _p.client.Call("Explorer.getAddressBalanceAtHeight", address, height)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getAddressBalanceAtHeight").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(int64(0)), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(int64)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getAddressBalanceAtHeight returned invalid type: %v", _t)
+			return int64(0), &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return int64(0), _err
+}
+
+func (_p ExplorerProxy) GetAddressDetailsAtHeight(address string, height int64) (AddressDetails, error) {
+	_res, _err := _p.client.Call("Explorer.getAddressDetailsAtHeight", address, height)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getAddressDetailsAtHeight").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(AddressDetails{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(AddressDetails)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getAddressDetailsAtHeight returned invalid type: %v", _t)
+			return AddressDetails{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return AddressDetails{}, _err
+}
+
+func (_p ExplorerProxy) GetAccountProof(address string, height int64) (AccountProof, error) {
+	_res, _err := _p.client.Call("Explorer.getAccountProof", address, height)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getAccountProof").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(AccountProof{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(AccountProof)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getAccountProof returned invalid type: %v", _t)
+			return AccountProof{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return AccountProof{}, _err
+}
+
+func (_p ExplorerProxy) GetActionProof(actionID string) (ActionProof, error) {
+	_res, _err := _p.client.Call("Explorer.getActionProof", actionID)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getActionProof").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(ActionProof{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(ActionProof)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getActionProof returned invalid type: %v", _t)
+			return ActionProof{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return ActionProof{}, _err
+}
+
+func (_p ExplorerProxy) GetReceiptProof(executionID string) (ReceiptProof, error) {
+	_res, _err := _p.client.Call("Explorer.getReceiptProof", executionID)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getReceiptProof").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(ReceiptProof{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(ReceiptProof)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getReceiptProof returned invalid type: %v", _t)
+			return ReceiptProof{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return ReceiptProof{}, _err
+}
+
 func (_p ExplorerProxy) GetLastTransfersByRange(startBlockHeight int64, offset int64, limit int64, showCoinBase bool) ([]Transfer, error) {
 	_res, _err := _p.client.Call("Explorer.getLastTransfersByRange", startBlockHeight, offset, limit, showCoinBase)
 	if _err == nil {
@@ -348,6 +563,24 @@ func (_p ExplorerProxy) GetTransfersByAddress(address string, offset int64, limi
 	return []Transfer{}, _err
 }
 
+func (_p ExplorerProxy) GetTransfersByAddressPage(request AddressHistoryRequest) (TransferPage, error) {
+	_res, _err := _p.client.Call("Explorer.getTransfersByAddressPage", request)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getTransfersByAddressPage").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(TransferPage{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(TransferPage)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getTransfersByAddressPage returned invalid type: %v", _t)
+			return TransferPage{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return TransferPage{}, _err
+}
+
 func (_p ExplorerProxy) GetUnconfirmedTransfersByAddress(address string, offset int64, limit int64) ([]Transfer, error) {
 	_res, _err := _p.client.Call("Explorer.getUnconfirmedTransfersByAddress", address, offset, limit)
 	if _err == nil {
@@ -438,6 +671,24 @@ func (_p ExplorerProxy) GetVotesByAddress(address string, offset int64, limit in
 	return []Vote{}, _err
 }
 
+func (_p ExplorerProxy) GetVotesByAddressPage(request AddressHistoryRequest) (VotePage, error) {
+	_res, _err := _p.client.Call("Explorer.getVotesByAddressPage", request)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getVotesByAddressPage").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(VotePage{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(VotePage)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getVotesByAddressPage returned invalid type: %v", _t)
+			return VotePage{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return VotePage{}, _err
+}
+
 func (_p ExplorerProxy) GetUnconfirmedVotesByAddress(address string, offset int64, limit int64) ([]Vote, error) {
 	_res, _err := _p.client.Call("Explorer.getUnconfirmedVotesByAddress", address, offset, limit)
 	if _err == nil {
@@ -528,6 +779,24 @@ func (_p ExplorerProxy) GetExecutionsByAddress(address string, offset int64, lim
 	return []Execution{}, _err
 }
 
+func (_p ExplorerProxy) GetExecutionsByAddressPage(request AddressHistoryRequest) (ExecutionPage, error) {
+	_res, _err := _p.client.Call("Explorer.getExecutionsByAddressPage", request)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getExecutionsByAddressPage").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(ExecutionPage{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(ExecutionPage)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getExecutionsByAddressPage returned invalid type: %v", _t)
+			return ExecutionPage{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return ExecutionPage{}, _err
+}
+
 func (_p ExplorerProxy) GetUnconfirmedExecutionsByAddress(address string, offset int64, limit int64) ([]Execution, error) {
 	_res, _err := _p.client.Call("Explorer.getUnconfirmedExecutionsByAddress", address, offset, limit)
 	if _err == nil {
@@ -762,6 +1031,78 @@ func (_p ExplorerProxy) GetReceiptByExecutionID(id string) (Receipt, error) {
 	return Receipt{}, _err
 }
 
+func (_p ExplorerProxy) GetReceiptByActionID(id string) (Receipt, error) {
+	_res, _err := _p.client.Call("Explorer.getReceiptByActionID", id)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getReceiptByActionID").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(Receipt{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(Receipt)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getReceiptByActionID returned invalid type: %v", _t)
+			return Receipt{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return Receipt{}, _err
+}
+
+func (_p ExplorerProxy) GetLogs(filter LogFilter) ([]Log, error) {
+	_res, _err := _p.client.Call("Explorer.getLogs", filter)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getLogs").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf([]Log{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.([]Log)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getLogs returned invalid type: %v", _t)
+			return []Log{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return []Log{}, _err
+}
+
+func (_p ExplorerProxy) GetActionByID(actionID string) (Action, error) {
+	_res, _err := _p.client.Call("Explorer.getActionByID", actionID)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getActionByID").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(Action{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(Action)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getActionByID returned invalid type: %v", _t)
+			return Action{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return Action{}, _err
+}
+
+func (_p ExplorerProxy) GetActionsByAddress(request AddressHistoryRequest) (ActionPage, error) {
+	_res, _err := _p.client.Call("Explorer.getActionsByAddress", request)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getActionsByAddress").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(ActionPage{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(ActionPage)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getActionsByAddress returned invalid type: %v", _t)
+			return ActionPage{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return ActionPage{}, _err
+}
+
 func (_p ExplorerProxy) ReadExecutionState(request Execution) (string, error) {
 	_res, _err := _p.client.Call("Explorer.readExecutionState", request)
 	if _err == nil {
@@ -780,6 +1121,42 @@ func (_p ExplorerProxy) ReadExecutionState(request Execution) (string, error) {
 	return "", _err
 }
 
+func (_p ExplorerProxy) EstimateGasForSmartContract(request Execution) (int64, error) {
+	_res, _err := _p.client.Call("Explorer.estimateGasForSmartContract", request)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.estimateGasForSmartContract").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(int64(0)), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(int64)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.estimateGasForSmartContract returned invalid type: %v", _t)
+			return int64(0), &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return int64(0), _err
+}
+
+func (_p ExplorerProxy) TraceExecution(id string) (ExecutionTrace, error) {
+	_res, _err := _p.client.Call("Explorer.traceExecution", id)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.traceExecution").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(ExecutionTrace{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(ExecutionTrace)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.traceExecution returned invalid type: %v", _t)
+			return ExecutionTrace{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return ExecutionTrace{}, _err
+}
+
 func (_p ExplorerProxy) GetBlockOrActionByHash(hashStr string) (GetBlkOrActResponse, error) {
 	_res, _err := _p.client.Call("Explorer.getBlockOrActionByHash", hashStr)
 	if _err == nil {
@@ -1332,49 +1709,56 @@ var IdlJsonRaw = `[
     },
     {
         "type": "struct",
-        "name": "Receipt",
+        "name": "LogTopics",
         "comment": "",
         "value": "",
         "extends": "",
         "fields": [
             {
-                "name": "returnValue",
+                "name": "topics",
                 "type": "string",
                 "optional": false,
-                "is_array": false,
+                "is_array": true,
                 "comment": ""
-            },
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "LogFilter",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
             {
-                "name": "status",
+                "name": "fromHeight",
                 "type": "int",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "hash",
-                "type": "string",
-                "optional": false,
-                "is_array": false,
-                "comment": ""
-            },
-            {
-                "name": "gasConsumed",
+                "name": "toHeight",
                 "type": "int",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "contractAddress",
+                "name": "addresses",
                 "type": "string",
                 "optional": false,
-                "is_array": false,
+                "is_array": true,
                 "comment": ""
             },
             {
-                "name": "logs",
-                "type": "Log",
+                "name": "topics",
+                "type": "LogTopics",
                 "optional": false,
                 "is_array": true,
                 "comment": ""
@@ -1388,32 +1772,326 @@ var IdlJsonRaw = `[
     },
     {
         "type": "struct",
-        "name": "SendExecutionResponse",
+        "name": "Receipt",
         "comment": "",
         "value": "",
         "extends": "",
         "fields": [
             {
-                "name": "receipt",
-                "type": "Receipt",
+                "name": "returnValue",
+                "type": "string",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
-            }
-        ],
-        "values": null,
-        "functions": null,
-        "barrister_version": "",
-        "date_generated": 0,
-        "checksum": ""
-    },
-    {
-        "type": "struct",
-        "name": "Vote",
-        "comment": "",
-        "value": "",
-        "extends": "",
-        "fields": [
+            },
+            {
+                "name": "status",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "hash",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gasConsumed",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "contractAddress",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "logs",
+                "type": "Log",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "errorCode",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "StorageEntry",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "key",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "value",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "TraceStep",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "pc",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "op",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gas",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gasCost",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "depth",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "stack",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "memory",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "storage",
+                "type": "StorageEntry",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "error",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "ExecutionTrace",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "receipt",
+                "type": "Receipt",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "steps",
+                "type": "TraceStep",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "SendExecutionResponse",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "receipt",
+                "type": "Receipt",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "Vote",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "version",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "ID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "nonce",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "timestamp",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "voter",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "votee",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "voterPubKey",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gasLimit",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gasPrice",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "signature",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "blockID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "isPending",
+                "type": "bool",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "Action",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
             {
                 "name": "version",
                 "type": "int",
@@ -1422,8 +2100,141 @@ var IdlJsonRaw = `[
                 "comment": ""
             },
             {
-                "name": "ID",
-                "type": "string",
+                "name": "ID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "type",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "nonce",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "sender",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "recipient",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "senderPubKey",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gasLimit",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gasPrice",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "signature",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "timestamp",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "blockID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "isPending",
+                "type": "bool",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "ActionPage",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "actions",
+                "type": "Action",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "cursor",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "AddressDetails",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "address",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "totalBalance",
+                "type": "int",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
@@ -1436,49 +2247,147 @@ var IdlJsonRaw = `[
                 "comment": ""
             },
             {
-                "name": "timestamp",
+                "name": "pendingNonce",
                 "type": "int",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "voter",
+                "name": "isCandidate",
+                "type": "bool",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "AccountProof",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "address",
                 "type": "string",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "votee",
+                "name": "blockID",
                 "type": "string",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "voterPubKey",
+                "name": "blockHeight",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "stateRoot",
                 "type": "string",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "gasLimit",
+                "name": "proof",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "ActionProof",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "actionID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "blockID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "blockHeight",
                 "type": "int",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "gasPrice",
+                "name": "blockHeader",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "txRoot",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "index",
                 "type": "int",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "signature",
+                "name": "path",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "ReceiptProof",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "executionID",
                 "type": "string",
                 "optional": false,
                 "is_array": false,
@@ -1492,11 +2401,53 @@ var IdlJsonRaw = `[
                 "comment": ""
             },
             {
-                "name": "isPending",
-                "type": "bool",
+                "name": "blockHeight",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "blockHeader",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "receiptRoot",
+                "type": "string",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
+            },
+            {
+                "name": "receipt",
+                "type": "Receipt",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "receiptHash",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "index",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "path",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
             }
         ],
         "values": null,
@@ -1507,7 +2458,7 @@ var IdlJsonRaw = `[
     },
     {
         "type": "struct",
-        "name": "AddressDetails",
+        "name": "AddressHistoryRequest",
         "comment": "",
         "value": "",
         "extends": "",
@@ -1520,29 +2471,127 @@ var IdlJsonRaw = `[
                 "comment": ""
             },
             {
-                "name": "totalBalance",
+                "name": "cursor",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "limit",
                 "type": "int",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "nonce",
+                "name": "startHeight",
                 "type": "int",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "pendingNonce",
+                "name": "endHeight",
                 "type": "int",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "isCandidate",
-                "type": "bool",
+                "name": "startTime",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "endTime",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "TransferPage",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "transfers",
+                "type": "Transfer",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "cursor",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "VotePage",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "votes",
+                "type": "Vote",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "cursor",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "ExecutionPage",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "executions",
+                "type": "Execution",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "cursor",
+                "type": "string",
                 "optional": false,
                 "is_array": false,
                 "comment": ""
@@ -1996,8 +3045,15 @@ var IdlJsonRaw = `[
                 "comment": ""
             },
             {
-                "name": "execution",
-                "type": "Execution",
+                "name": "execution",
+                "type": "Execution",
+                "optional": true,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "action",
+                "type": "Action",
                 "optional": true,
                 "is_array": false,
                 "comment": ""
@@ -2070,6 +3126,127 @@ var IdlJsonRaw = `[
                     "comment": ""
                 }
             },
+            {
+                "name": "getAddressBalanceAtHeight",
+                "comment": "get the balance of an address at a given block height",
+                "params": [
+                    {
+                        "name": "address",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    },
+                    {
+                        "name": "height",
+                        "type": "int",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "int",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getAddressDetailsAtHeight",
+                "comment": "get the address detail of an iotex address at a given block height",
+                "params": [
+                    {
+                        "name": "address",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    },
+                    {
+                        "name": "height",
+                        "type": "int",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "AddressDetails",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getAccountProof",
+                "comment": "get the merkle proof of the state of an address against the state root of the block at a given height",
+                "params": [
+                    {
+                        "name": "address",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    },
+                    {
+                        "name": "height",
+                        "type": "int",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "AccountProof",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getActionProof",
+                "comment": "get the merkle proof of a transfer, vote or execution being included in a block",
+                "params": [
+                    {
+                        "name": "actionID",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "ActionProof",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getReceiptProof",
+                "comment": "get the merkle proof of the receipt of an execution being included in a block",
+                "params": [
+                    {
+                        "name": "executionID",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "ReceiptProof",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
             {
                 "name": "getLastTransfersByRange",
                 "comment": "get list of transfers by start block height, transfer offset and limit",
@@ -2165,6 +3342,26 @@ var IdlJsonRaw = `[
                     "comment": ""
                 }
             },
+            {
+                "name": "getTransfersByAddressPage",
+                "comment": "get a page of transfers belonging to an address in reverse chronological order, optionally in a height or time range",
+                "params": [
+                    {
+                        "name": "request",
+                        "type": "AddressHistoryRequest",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "TransferPage",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
             {
                 "name": "getUnconfirmedTransfersByAddress",
                 "comment": "get list of unconfirmed transfers in actpool belonging to an address",
@@ -2321,6 +3518,26 @@ var IdlJsonRaw = `[
                     "comment": ""
                 }
             },
+            {
+                "name": "getVotesByAddressPage",
+                "comment": "get a page of votes belonging to an address in reverse chronological order, optionally in a height or time range",
+                "params": [
+                    {
+                        "name": "request",
+                        "type": "AddressHistoryRequest",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "VotePage",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
             {
                 "name": "getUnconfirmedVotesByAddress",
                 "comment": "get list of unconfirmed votes in actpool belonging to an address",
@@ -2477,6 +3694,26 @@ var IdlJsonRaw = `[
                     "comment": ""
                 }
             },
+            {
+                "name": "getExecutionsByAddressPage",
+                "comment": "get a page of executions belonging to an address in reverse chronological order, optionally in a height or time range",
+                "params": [
+                    {
+                        "name": "request",
+                        "type": "AddressHistoryRequest",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "ExecutionPage",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
             {
                 "name": "getUnconfirmedExecutionsByAddress",
                 "comment": "get list of unconfirmed executions in actpool belonging to an address",
@@ -2740,6 +3977,86 @@ var IdlJsonRaw = `[
                     "comment": ""
                 }
             },
+            {
+                "name": "getReceiptByActionID",
+                "comment": "get receipt by the id of transfer, vote, execution or action of any other type",
+                "params": [
+                    {
+                        "name": "id",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "Receipt",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getLogs",
+                "comment": "get logs emitted by contracts in a height range, filtered by contract addresses and topics at each position",
+                "params": [
+                    {
+                        "name": "filter",
+                        "type": "LogFilter",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "Log",
+                    "optional": false,
+                    "is_array": true,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getActionByID",
+                "comment": "get a tx or action of any type from action id",
+                "params": [
+                    {
+                        "name": "actionID",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "Action",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getActionsByAddress",
+                "comment": "get a page of txs and actions of any type belonging to an address in reverse chronological order, optionally in\na height or time range",
+                "params": [
+                    {
+                        "name": "request",
+                        "type": "AddressHistoryRequest",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "ActionPage",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
             {
                 "name": "readExecutionState",
                 "comment": "read execution state",
@@ -2760,6 +4077,46 @@ var IdlJsonRaw = `[
                     "comment": ""
                 }
             },
+            {
+                "name": "estimateGasForSmartContract",
+                "comment": "estimate the minimal gas limit with which an execution succeeds",
+                "params": [
+                    {
+                        "name": "request",
+                        "type": "Execution",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "int",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "traceExecution",
+                "comment": "re-run an execution and get the opcode-level trace of it, only available if debug is enabled",
+                "params": [
+                    {
+                        "name": "id",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "ExecutionTrace",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
             {
                 "name": "getBlockOrActionByHash",
                 "comment": "get block or action by a hash",
@@ -2795,7 +4152,7 @@ var IdlJsonRaw = `[
         "values": null,
         "functions": null,
         "barrister_version": "0.1.6",
-        "date_generated": 1538418246052,
-        "checksum": "97e0f2efe11dee3190b33a6c0cb7fda8"
+        "date_generated": 1539628612681,
+        "checksum": "4882827ab8764a2859652378ba76b890"
     }
 ]`
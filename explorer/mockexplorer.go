@@ -36,6 +36,49 @@ func (exp *MockExplorer) GetAddressDetails(address string) (explorer.AddressDeta
 	}, nil
 }
 
+// GetAddressBalanceAtHeight returns the balance of an address at a given block height
+func (exp *MockExplorer) GetAddressBalanceAtHeight(address string, height int64) (int64, error) {
+	return randInt64(), nil
+}
+
+// GetAddressDetailsAtHeight returns the properties of an address at a given block height
+func (exp *MockExplorer) GetAddressDetailsAtHeight(address string, height int64) (explorer.AddressDetails, error) {
+	return explorer.AddressDetails{
+		Address:      address,
+		TotalBalance: randInt64(),
+	}, nil
+}
+
+// GetAccountProof returns the merkle proof of the state of an address at a given block height
+func (exp *MockExplorer) GetAccountProof(address string, height int64) (explorer.AccountProof, error) {
+	return explorer.AccountProof{
+		Address:     address,
+		BlockID:     randString(),
+		BlockHeight: height,
+		StateRoot:   randString(),
+	}, nil
+}
+
+// GetActionProof returns the merkle proof of an action being included in a block
+func (exp *MockExplorer) GetActionProof(actionID string) (explorer.ActionProof, error) {
+	return explorer.ActionProof{
+		ActionID:    actionID,
+		BlockID:     randString(),
+		BlockHeight: randInt64(),
+		TxRoot:      randString(),
+	}, nil
+}
+
+// GetReceiptProof returns the merkle proof of the receipt of an execution being included in a block
+func (exp *MockExplorer) GetReceiptProof(executionID string) (explorer.ReceiptProof, error) {
+	return explorer.ReceiptProof{
+		ExecutionID: executionID,
+		BlockID:     randString(),
+		BlockHeight: randInt64(),
+		ReceiptRoot: randString(),
+	}, nil
+}
+
 // GetLastTransfersByRange return transfers in [-(offset+limit-1), -offset] from block
 // with height startBlockHeight
 func (exp *MockExplorer) GetLastTransfersByRange(startBlockHeight int64, offset int64, limit int64, showCoinBase bool) ([]explorer.Transfer, error) {
@@ -56,6 +99,12 @@ func (exp *MockExplorer) GetTransfersByAddress(address string, offset int64, lim
 	return exp.GetLastTransfersByRange(0, offset, limit, true)
 }
 
+// GetTransfersByAddressPage returns a page of transfers associated with an address
+func (exp *MockExplorer) GetTransfersByAddressPage(request explorer.AddressHistoryRequest) (explorer.TransferPage, error) {
+	transfers, err := exp.GetLastTransfersByRange(0, 0, request.Limit, true)
+	return explorer.TransferPage{Transfers: transfers, Cursor: randString()}, err
+}
+
 // GetUnconfirmedTransfersByAddress returns all unconfirmed transfers in actpool associated with an address
 func (exp *MockExplorer) GetUnconfirmedTransfersByAddress(address string, offset int64, limit int64) ([]explorer.Transfer, error) {
 	return exp.GetLastTransfersByRange(0, offset, limit, true)
@@ -86,6 +135,12 @@ func (exp *MockExplorer) GetVotesByAddress(address string, offset int64, limit i
 	return exp.GetLastVotesByRange(0, offset, limit)
 }
 
+// GetVotesByAddressPage returns a page of votes associated with an address
+func (exp *MockExplorer) GetVotesByAddressPage(request explorer.AddressHistoryRequest) (explorer.VotePage, error) {
+	votes, err := exp.GetLastVotesByRange(0, 0, request.Limit)
+	return explorer.VotePage{Votes: votes, Cursor: randString()}, err
+}
+
 // GetUnconfirmedVotesByAddress returns all unconfirmed votes in actpool associated with an address
 func (exp *MockExplorer) GetUnconfirmedVotesByAddress(address string, offset int64, limit int64) ([]explorer.Vote, error) {
 	return exp.GetLastVotesByRange(0, offset, limit)
@@ -101,6 +156,30 @@ func (exp *MockExplorer) GetReceiptByExecutionID(id string) (explorer.Receipt, e
 	return explorer.Receipt{}, nil
 }
 
+// GetReceiptByActionID gets receipt with corresponding action id of any type
+func (exp *MockExplorer) GetReceiptByActionID(id string) (explorer.Receipt, error) {
+	return explorer.Receipt{}, nil
+}
+
+// GetLogs gets logs satisfying the filter
+func (exp *MockExplorer) GetLogs(filter explorer.LogFilter) ([]explorer.Log, error) {
+	return []explorer.Log{}, nil
+}
+
+// GetActionByID returns tx or action of any type by action id
+func (exp *MockExplorer) GetActionByID(actionID string) (explorer.Action, error) {
+	return randAction(), nil
+}
+
+// GetActionsByAddress returns a page of txs and actions of any type associated with an address
+func (exp *MockExplorer) GetActionsByAddress(request explorer.AddressHistoryRequest) (explorer.ActionPage, error) {
+	var actions []explorer.Action
+	for i := int64(0); i < request.Limit; i++ {
+		actions = append(actions, randAction())
+	}
+	return explorer.ActionPage{Actions: actions, Cursor: randString()}, nil
+}
+
 // GetLastExecutionsByRange return executions in [-(offset+limit-1), -offset] from block
 // with height startBlockHeight
 func (exp *MockExplorer) GetLastExecutionsByRange(startBlockHeight int64, offset int64, limit int64) ([]explorer.Execution, error) {
@@ -121,6 +200,12 @@ func (exp *MockExplorer) GetExecutionsByAddress(address string, offset int64, li
 	return exp.GetLastExecutionsByRange(0, offset, limit)
 }
 
+// GetExecutionsByAddressPage returns a page of executions associated with an address
+func (exp *MockExplorer) GetExecutionsByAddressPage(request explorer.AddressHistoryRequest) (explorer.ExecutionPage, error) {
+	executions, err := exp.GetLastExecutionsByRange(0, 0, request.Limit)
+	return explorer.ExecutionPage{Executions: executions, Cursor: randString()}, err
+}
+
 // GetUnconfirmedExecutionsByAddress returns all unconfirmed executions in actpool associated with an address
 func (exp *MockExplorer) GetUnconfirmedExecutionsByAddress(address string, offset int64, limit int64) ([]explorer.Execution, error) {
 	return exp.GetLastExecutionsByRange(0, offset, limit)
@@ -223,6 +308,16 @@ func (exp *MockExplorer) ReadExecutionState(request explorer.Execution) (string,
 	return "100", nil
 }
 
+// EstimateGasForSmartContract estimates the gas of a smart contract
+func (exp *MockExplorer) EstimateGasForSmartContract(request explorer.Execution) (int64, error) {
+	return randInt64(), nil
+}
+
+// TraceExecution gets the trace of an execution
+func (exp *MockExplorer) TraceExecution(id string) (explorer.ExecutionTrace, error) {
+	return explorer.ExecutionTrace{}, nil
+}
+
 // GetBlockOrActionByHash get block or action by a hash
 func (exp *MockExplorer) GetBlockOrActionByHash(hash string) (explorer.GetBlkOrActResponse, error) {
 	return explorer.GetBlkOrActResponse{}, nil
@@ -278,6 +373,18 @@ func randExecution() explorer.Execution {
 	}
 }
 
+func randAction() explorer.Action {
+	return explorer.Action{
+		ID:        randString(),
+		Type:      "transfer",
+		Nonce:     randInt64(),
+		Sender:    randString(),
+		Recipient: randString(),
+		Timestamp: randInt64(),
+		BlockID:   randString(),
+	}
+}
+
 func randBlock() explorer.Block {
 	return explorer.Block{
 		ID:        randString(),
@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/explorer/idl/explorer"
 )
 
 func TestMockExplorerApi(t *testing.T) {
@@ -26,6 +28,33 @@ func TestMockExplorerApi(t *testing.T) {
 	_, err = svc.GetAddressDetails("")
 	require.Nil(err)
 
+	_, err = svc.GetAddressBalanceAtHeight("", 0)
+	require.Nil(err)
+
+	_, err = svc.GetAddressDetailsAtHeight("", 0)
+	require.Nil(err)
+
+	_, err = svc.GetAccountProof("", 0)
+	require.Nil(err)
+
+	_, err = svc.GetActionProof("")
+	require.Nil(err)
+
+	_, err = svc.GetReceiptProof("")
+	require.Nil(err)
+
+	_, err = svc.EstimateGasForSmartContract(explorer.Execution{})
+	require.Nil(err)
+
+	_, err = svc.GetLogs(explorer.LogFilter{})
+	require.Nil(err)
+
+	_, err = svc.TraceExecution("")
+	require.Nil(err)
+
+	_, err = svc.GetReceiptByActionID("")
+	require.Nil(err)
+
 	_, err = svc.GetLastTransfersByRange(0, 0, 10, true)
 	require.Nil(err)
 
@@ -35,6 +64,9 @@ func TestMockExplorerApi(t *testing.T) {
 	_, err = svc.GetTransfersByAddress("", 0, 10)
 	require.Nil(err)
 
+	_, err = svc.GetTransfersByAddressPage(explorer.AddressHistoryRequest{Limit: 10})
+	require.Nil(err)
+
 	_, err = svc.GetTransfersByBlockID("", 0, 10)
 	require.Nil(err)
 
@@ -47,6 +79,9 @@ func TestMockExplorerApi(t *testing.T) {
 	_, err = svc.GetVotesByAddress("", 0, 10)
 	require.Nil(err)
 
+	_, err = svc.GetVotesByAddressPage(explorer.AddressHistoryRequest{Limit: 10})
+	require.Nil(err)
+
 	_, err = svc.GetVotesByBlockID("", 0, 10)
 	require.Nil(err)
 
@@ -59,9 +94,18 @@ func TestMockExplorerApi(t *testing.T) {
 	_, err = svc.GetExecutionsByAddress("", 0, 10)
 	require.Nil(err)
 
+	_, err = svc.GetExecutionsByAddressPage(explorer.AddressHistoryRequest{Limit: 10})
+	require.Nil(err)
+
 	_, err = svc.GetExecutionsByBlockID("", 0, 10)
 	require.Nil(err)
 
+	_, err = svc.GetActionByID("")
+	require.Nil(err)
+
+	_, err = svc.GetActionsByAddress(explorer.AddressHistoryRequest{Limit: 10})
+	require.Nil(err)
+
 	_, err = svc.GetLastBlocksByRange(0, 10)
 	require.Nil(err)
 
@@ -445,6 +445,7 @@ type ReceiptPb struct {
 	GasConsumed          uint64   `protobuf:"varint,4,opt,name=gasConsumed,proto3" json:"gasConsumed,omitempty"`
 	ContractAddress      string   `protobuf:"bytes,5,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
 	Logs                 []*LogPb `protobuf:"bytes,6,rep,name=logs,proto3" json:"logs,omitempty"`
+	ErrorCode            uint64   `protobuf:"varint,7,opt,name=errorCode,proto3" json:"errorCode,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -516,6 +517,13 @@ func (m *ReceiptPb) GetLogs() []*LogPb {
 	return nil
 }
 
+func (m *ReceiptPb) GetErrorCode() uint64 {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return 0
+}
+
 type StartSubChainPb struct {
 	// TODO: chainID chould be assigned by system and returned via a receipt
 	ChainID              uint32   `protobuf:"varint,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
@@ -1160,7 +1168,7 @@ type BlockHeaderPb struct {
 	TxRoot               []byte   `protobuf:"bytes,6,opt,name=txRoot,proto3" json:"txRoot,omitempty"`
 	StateRoot            []byte   `protobuf:"bytes,7,opt,name=stateRoot,proto3" json:"stateRoot,omitempty"`
 	ReceiptRoot          []byte   `protobuf:"bytes,8,opt,name=receiptRoot,proto3" json:"receiptRoot,omitempty"`
-	Reserved             []byte   `protobuf:"bytes,9,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	LogBloom             []byte   `protobuf:"bytes,9,opt,name=logBloom,proto3" json:"logBloom,omitempty"`
 	Signature            []byte   `protobuf:"bytes,10,opt,name=signature,proto3" json:"signature,omitempty"`
 	Pubkey               []byte   `protobuf:"bytes,11,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
 	DkgID                []byte   `protobuf:"bytes,12,opt,name=dkgID,proto3" json:"dkgID,omitempty"`
@@ -1251,9 +1259,9 @@ func (m *BlockHeaderPb) GetReceiptRoot() []byte {
 	return nil
 }
 
-func (m *BlockHeaderPb) GetReserved() []byte {
+func (m *BlockHeaderPb) GetLogBloom() []byte {
 	if m != nil {
-		return m.Reserved
+		return m.LogBloom
 	}
 	return nil
 }
@@ -1794,98 +1802,98 @@ func init() {
 func init() { proto.RegisterFile("blockchain.proto", fileDescriptor_blockchain_f3f6779518493ae1) }
 
 var fileDescriptor_blockchain_f3f6779518493ae1 = []byte{
-	// 1487 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0x4b, 0x6f, 0x1c, 0xc5,
-	0x13, 0xf7, 0xec, 0xae, 0xf7, 0x51, 0xfb, 0xf0, 0xa6, 0xff, 0xf9, 0x9b, 0x21, 0x42, 0xd1, 0x32,
-	0x0a, 0xd1, 0x2a, 0x0a, 0x16, 0x38, 0x07, 0x38, 0x20, 0xa1, 0xd8, 0x89, 0xb4, 0x16, 0x4e, 0xbc,
-	0x6a, 0x9b, 0x70, 0x84, 0x79, 0xb4, 0xd7, 0x23, 0xef, 0x4e, 0x8f, 0xa6, 0x7b, 0x1c, 0xfb, 0x4b,
-	0x70, 0xe7, 0x02, 0xe2, 0x94, 0x3b, 0x27, 0x2e, 0xdc, 0xf9, 0x12, 0x7c, 0x17, 0xd4, 0xd5, 0xdd,
-	0xf3, 0x8a, 0x63, 0x71, 0xf2, 0xd6, 0xaf, 0xab, 0x6b, 0xea, 0xf5, 0xeb, 0x2a, 0xc3, 0x34, 0x58,
-	0xf3, 0xf0, 0x32, 0xbc, 0xf0, 0xe3, 0x64, 0x2f, 0xcd, 0xb8, 0xe4, 0xa4, 0x1b, 0xe3, 0x5f, 0xef,
-	0x4f, 0x07, 0xe0, 0x2c, 0xf3, 0x13, 0x71, 0xce, 0xb2, 0x65, 0x40, 0x76, 0xa1, 0xeb, 0x6f, 0x78,
-	0x9e, 0x48, 0xd7, 0x99, 0x39, 0xf3, 0x11, 0x35, 0x92, 0xc2, 0x05, 0x4b, 0x22, 0x96, 0xb9, 0xad,
-	0x99, 0x33, 0x1f, 0x50, 0x23, 0x91, 0x4f, 0x60, 0x90, 0xb1, 0x30, 0x4e, 0x63, 0x96, 0x48, 0xb7,
-	0x8d, 0x47, 0x25, 0x40, 0x5c, 0xe8, 0xa5, 0xfe, 0xcd, 0x9a, 0xfb, 0x91, 0xdb, 0x41, 0x73, 0x56,
-	0x24, 0x1e, 0x8c, 0xb4, 0x85, 0x65, 0x1e, 0x7c, 0xc7, 0x6e, 0xdc, 0x6d, 0x3c, 0xae, 0x61, 0xe4,
-	0x21, 0x40, 0x2c, 0x0e, 0x79, 0x9c, 0x04, 0xbe, 0x60, 0x6e, 0x77, 0xe6, 0xcc, 0xfb, 0xb4, 0x82,
-	0x78, 0x3f, 0x3b, 0xd0, 0x7d, 0xc3, 0x25, 0x5b, 0x06, 0xca, 0x0d, 0x19, 0x6f, 0x98, 0x90, 0xfe,
-	0x26, 0x45, 0xcf, 0x3b, 0xb4, 0x04, 0x94, 0x21, 0xc1, 0xd6, 0xe7, 0xcb, 0x3c, 0xb8, 0x64, 0x37,
-	0x18, 0xc0, 0x88, 0x56, 0x10, 0xe5, 0xcc, 0x15, 0x97, 0x2c, 0x7b, 0x1e, 0x45, 0x19, 0x13, 0xc2,
-	0xc4, 0x51, 0xc3, 0xac, 0x0e, 0xb3, 0x3a, 0x9d, 0x52, 0xc7, 0x62, 0xde, 0x2f, 0x0e, 0x0c, 0x5f,
-	0x5e, 0xb3, 0x30, 0x97, 0x31, 0x4f, 0xee, 0x48, 0xe6, 0x03, 0xe8, 0x33, 0x54, 0xe3, 0x36, 0x9d,
-	0x85, 0xac, 0xce, 0x42, 0x9e, 0xc8, 0xcc, 0x0f, 0x6d, 0x3e, 0x0b, 0x99, 0x3c, 0x86, 0x89, 0xd5,
-	0x33, 0x69, 0xd3, 0x59, 0x6d, 0xa0, 0x84, 0x40, 0x27, 0xf2, 0xa5, 0x6f, 0x92, 0x8a, 0xbf, 0xbd,
-	0x9f, 0x60, 0x7a, 0xca, 0xc2, 0x8c, 0xc9, 0x65, 0xc6, 0x53, 0x2e, 0xfc, 0xb5, 0xf6, 0xcf, 0x14,
-	0xd5, 0xf9, 0x70, 0x51, 0x5b, 0xcd, 0xa2, 0xe2, 0x2d, 0x65, 0xc9, 0x6d, 0xcf, 0xda, 0xf3, 0x31,
-	0x35, 0x92, 0x77, 0x08, 0x3b, 0xfa, 0x0b, 0x3f, 0xc4, 0x32, 0x61, 0x42, 0xdc, 0xf1, 0x01, 0x17,
-	0x7a, 0x6f, 0xb5, 0x92, 0xdb, 0x9a, 0xb5, 0x55, 0x5f, 0x18, 0xd1, 0xfb, 0xcb, 0x81, 0xed, 0x63,
-	0xbe, 0x5a, 0x06, 0x4a, 0xc7, 0x37, 0xb9, 0xd6, 0x97, 0xad, 0xa8, 0xac, 0x4a, 0x9e, 0xc6, 0xa1,
-	0xbd, 0x6c, 0xa4, 0x22, 0xec, 0x76, 0x19, 0x36, 0x99, 0xc1, 0x10, 0x5b, 0xff, 0x75, 0xbe, 0x09,
-	0x58, 0x86, 0xf9, 0xea, 0xd0, 0x2a, 0xa4, 0xbe, 0x23, 0xaf, 0x93, 0x85, 0x2f, 0x2e, 0x4c, 0xbe,
-	0xac, 0xa8, 0xd2, 0x80, 0x8a, 0x78, 0xd6, 0xc5, 0xb3, 0x12, 0x20, 0xf7, 0x61, 0x3b, 0x4e, 0x22,
-	0x76, 0xed, 0xf6, 0x66, 0xce, 0x7c, 0x4c, 0xb5, 0xe0, 0xfd, 0xed, 0xc0, 0x80, 0xb2, 0x90, 0xc5,
-	0xa9, 0x5c, 0x06, 0xea, 0xeb, 0x19, 0x93, 0x79, 0x96, 0xbc, 0xf1, 0xd7, 0x39, 0x33, 0x5d, 0x50,
-	0x85, 0x30, 0x43, 0xd2, 0x97, 0xb9, 0xc0, 0x3c, 0x77, 0xa8, 0x91, 0x54, 0x2c, 0x17, 0xea, 0xb3,
-	0x26, 0x16, 0xf5, 0x5b, 0x59, 0x5b, 0xf9, 0xe2, 0x90, 0x27, 0x22, 0xdf, 0xb0, 0xc8, 0xc6, 0x52,
-	0x81, 0xc8, 0x1c, 0x76, 0x6c, 0xb3, 0xd8, 0x3e, 0xdd, 0xc6, 0xdc, 0x35, 0x61, 0xf2, 0x29, 0x74,
-	0xd6, 0x7c, 0x25, 0xdc, 0xee, 0xac, 0x3d, 0x1f, 0xee, 0x8f, 0xf7, 0xf4, 0x6b, 0xb0, 0x87, 0xa9,
-	0xa7, 0x78, 0xe4, 0xfd, 0xd6, 0x82, 0x9d, 0x53, 0xe9, 0x67, 0xf2, 0x34, 0x0f, 0x0e, 0xd5, 0xcb,
-	0xa1, 0x8b, 0x82, 0x8f, 0xc8, 0xd1, 0x0b, 0x0c, 0x66, 0x4c, 0xad, 0xa8, 0x3e, 0x2d, 0x58, 0x98,
-	0x67, 0xb1, 0xbc, 0x79, 0xc1, 0x52, 0x2e, 0x62, 0x69, 0x88, 0xd6, 0x84, 0xc9, 0x13, 0x98, 0xf2,
-	0x94, 0x65, 0xbe, 0x22, 0x89, 0x55, 0xd5, 0x61, 0xbe, 0x87, 0xab, 0x90, 0x85, 0x72, 0x61, 0xc1,
-	0xe2, 0xd5, 0x85, 0xb4, 0x21, 0x57, 0x20, 0xb2, 0x07, 0x24, 0xf5, 0x33, 0x96, 0x18, 0xf9, 0xe4,
-	0xfc, 0x5c, 0x30, 0x89, 0x51, 0x77, 0xe8, 0x2d, 0x27, 0x8a, 0xc7, 0xfc, 0x6d, 0x52, 0x72, 0xbd,
-	0xab, 0x79, 0x5c, 0xc5, 0x14, 0xcf, 0x50, 0x5e, 0xe6, 0xc1, 0x3a, 0x0e, 0x15, 0xcf, 0x7a, 0x9a,
-	0x67, 0x75, 0xd4, 0xfb, 0xc3, 0x81, 0xc9, 0xa9, 0xe4, 0xe9, 0x7f, 0x4a, 0x90, 0x7a, 0x84, 0x24,
-	0x4f, 0x4d, 0x24, 0xba, 0xda, 0x15, 0x44, 0xf5, 0x13, 0x9a, 0x37, 0xac, 0xd7, 0xc2, 0x2d, 0xae,
-	0x74, 0x6e, 0x73, 0x05, 0xd3, 0x6f, 0xbc, 0x68, 0x54, 0xbe, 0x01, 0x7b, 0xbf, 0xb6, 0x00, 0x96,
-	0xb9, 0x3c, 0x50, 0x8d, 0x7c, 0xa7, 0xc3, 0xbb, 0xd0, 0xbd, 0xa8, 0x3a, 0x6b, 0xa4, 0x5b, 0x5b,
-	0xf3, 0x21, 0x80, 0x1f, 0xaa, 0xc2, 0x51, 0xce, 0xa5, 0x71, 0xb1, 0x82, 0x28, 0x2a, 0xa9, 0xc6,
-	0x66, 0x78, 0xac, 0x69, 0x56, 0x02, 0xe4, 0x29, 0xdc, 0x4b, 0x33, 0x1e, 0xe5, 0x61, 0x35, 0x4e,
-	0x4d, 0xb8, 0xf7, 0x0f, 0x54, 0xc5, 0x59, 0x12, 0xf1, 0x4c, 0xf0, 0x12, 0x14, 0x6e, 0x0f, 0x9f,
-	0x82, 0x5b, 0x4e, 0xaa, 0xfa, 0xa7, 0xf1, 0x2a, 0xf1, 0x65, 0x9e, 0x31, 0xe1, 0xf6, 0xeb, 0xfa,
-	0xe5, 0x89, 0xf7, 0x4f, 0x07, 0xfa, 0xcf, 0x43, 0xf3, 0x84, 0xbb, 0xd0, 0xbb, 0x62, 0x99, 0x88,
-	0x79, 0x62, 0xd3, 0x63, 0x44, 0x55, 0xaf, 0x84, 0x27, 0x21, 0x33, 0xd9, 0xd1, 0x82, 0x7a, 0xbe,
-	0x57, 0xbe, 0x38, 0x8e, 0x37, 0xa6, 0xa9, 0x3b, 0xb4, 0x90, 0xcd, 0xd9, 0x32, 0x8b, 0x43, 0x66,
-	0x52, 0x54, 0xc8, 0x98, 0x20, 0xeb, 0x42, 0x91, 0x20, 0x0b, 0x90, 0x2f, 0xa0, 0x2f, 0xcd, 0x8c,
-	0x76, 0x61, 0xe6, 0xcc, 0x87, 0xfb, 0xc4, 0x32, 0xb6, 0x9c, 0xdd, 0x8b, 0x2d, 0x5a, 0x68, 0x91,
-	0x47, 0xd0, 0x51, 0xa3, 0xc9, 0x1d, 0xa2, 0xf6, 0xc4, 0x6a, 0xeb, 0x71, 0xb9, 0xd8, 0xa2, 0x78,
-	0x4a, 0x9e, 0xc1, 0x80, 0xd9, 0x79, 0xe5, 0x8e, 0x50, 0xf5, 0x7f, 0x56, 0xb5, 0x32, 0xc8, 0x16,
-	0x5b, 0xb4, 0xd4, 0x23, 0x07, 0x30, 0x11, 0xb5, 0x49, 0xe2, 0x8e, 0xf1, 0xa6, 0x6b, 0x6f, 0x36,
-	0xe7, 0xcc, 0x62, 0x8b, 0x36, 0x6e, 0x90, 0x6f, 0x61, 0x2c, 0xaa, 0xb3, 0xc2, 0x9d, 0xa0, 0x89,
-	0x8f, 0xea, 0x26, 0x8a, 0x41, 0xb2, 0xd8, 0xa2, 0x75, 0x7d, 0x34, 0x50, 0x7d, 0x9b, 0xdc, 0x9d,
-	0x86, 0x81, 0xfa, 0xc3, 0x85, 0x06, 0xaa, 0x10, 0xf9, 0x06, 0x46, 0xa2, 0x42, 0x5d, 0x77, 0x8a,
-	0xf7, 0x77, 0xcb, 0xfb, 0x55, 0x5a, 0x2f, 0xb6, 0x68, 0x4d, 0x5b, 0x15, 0x24, 0x35, 0x1c, 0x72,
-	0xef, 0xd5, 0x0b, 0x52, 0x72, 0x4b, 0x15, 0xc4, 0x6a, 0x1d, 0xf4, 0xa1, 0xab, 0xf9, 0xe0, 0xfd,
-	0xde, 0x86, 0x31, 0x62, 0x0b, 0xe6, 0x47, 0xb8, 0x74, 0x7d, 0xb8, 0xc9, 0x2a, 0xec, 0x6c, 0x7d,
-	0x88, 0x9d, 0xed, 0x1a, 0x3b, 0x6b, 0x9b, 0x50, 0xa7, 0xb9, 0x09, 0x3d, 0x82, 0x71, 0x9a, 0xb1,
-	0xab, 0x83, 0x62, 0xac, 0xe9, 0x56, 0xab, 0x83, 0x38, 0x60, 0xaf, 0x91, 0xaa, 0x9a, 0x84, 0x46,
-	0xaa, 0xb3, 0xb8, 0xd7, 0x64, 0x31, 0x0e, 0x3b, 0x9c, 0x7c, 0x78, 0xde, 0xb7, 0xc3, 0xae, 0x80,
-	0x14, 0x01, 0x32, 0x26, 0x58, 0x76, 0xc5, 0x22, 0x77, 0xa0, 0x09, 0x60, 0xe5, 0x3a, 0x01, 0xa0,
-	0x49, 0x80, 0x5d, 0xe8, 0xa6, 0x7a, 0x7b, 0x1b, 0x6a, 0x8f, 0xb4, 0xa4, 0x48, 0x18, 0x5d, 0xae,
-	0x8e, 0x5e, 0x60, 0xf3, 0x8e, 0xa8, 0x16, 0x94, 0xad, 0xe8, 0x72, 0x65, 0xd6, 0xbd, 0xb1, 0xb6,
-	0x55, 0x00, 0x6a, 0x02, 0x44, 0x97, 0xab, 0x82, 0xf0, 0xd8, 0x7a, 0x23, 0x5a, 0xc3, 0xbc, 0x08,
-	0x7a, 0xf6, 0x81, 0xfc, 0x5c, 0x25, 0xda, 0xb7, 0x3b, 0xcc, 0x70, 0xff, 0xff, 0xb6, 0xd0, 0xb5,
-	0x1a, 0x52, 0xa3, 0x44, 0x9e, 0x40, 0x4f, 0xd7, 0x59, 0x6f, 0x27, 0xc3, 0xfd, 0xa9, 0xd5, 0xb7,
-	0x6f, 0x0a, 0xb5, 0x0a, 0xde, 0x31, 0x00, 0x1a, 0x39, 0x52, 0xab, 0x83, 0x8a, 0x05, 0x5b, 0xd4,
-	0xec, 0xaf, 0x5a, 0x20, 0x53, 0x68, 0xb3, 0x24, 0x32, 0x8f, 0x8c, 0xfa, 0xa9, 0x72, 0xc1, 0xf5,
-	0x94, 0x33, 0xfb, 0x97, 0x96, 0xbc, 0x67, 0x30, 0x40, 0x6b, 0xa7, 0x37, 0x49, 0x58, 0x1a, 0x6b,
-	0xdd, 0x62, 0xac, 0x5d, 0x18, 0xf3, 0xbe, 0x82, 0x09, 0x5e, 0x3a, 0xe4, 0x89, 0xf4, 0x63, 0x35,
-	0x71, 0x3e, 0x83, 0x6d, 0x5c, 0x72, 0x4c, 0xb8, 0x3b, 0xb5, 0x70, 0x97, 0x01, 0xd5, 0xa7, 0xde,
-	0x6b, 0x18, 0x68, 0x36, 0xab, 0xf5, 0xfb, 0x01, 0xf4, 0x53, 0x2d, 0xd8, 0x4d, 0xaf, 0x90, 0x4b,
-	0x7b, 0xad, 0x3b, 0xed, 0xbd, 0x6b, 0xc1, 0xe0, 0x25, 0x3e, 0xc6, 0x4c, 0x2f, 0x8e, 0xa6, 0xbb,
-	0x9d, 0x66, 0x77, 0x97, 0x2b, 0x59, 0xab, 0xb9, 0x92, 0x7d, 0x0d, 0xdb, 0xb8, 0x0a, 0x62, 0x80,
-	0x93, 0x7d, 0xaf, 0x78, 0xca, 0xac, 0x5d, 0xfb, 0x6b, 0xc3, 0x12, 0x79, 0xa6, 0x34, 0xa9, 0xbe,
-	0x80, 0x1b, 0xb9, 0x3e, 0xca, 0xcc, 0x66, 0x5f, 0xc8, 0xb8, 0x75, 0x9b, 0xdf, 0xb5, 0x7f, 0x56,
-	0x1a, 0xa8, 0xb2, 0x11, 0xb1, 0x30, 0x46, 0x1a, 0xeb, 0x7f, 0x56, 0x0a, 0xb9, 0xde, 0xdd, 0xbd,
-	0x46, 0x77, 0x7b, 0x4f, 0x61, 0xda, 0x74, 0x8c, 0x8c, 0xa0, 0xbf, 0xa4, 0x27, 0xcb, 0x93, 0xd3,
-	0xe7, 0xc7, 0xd3, 0x2d, 0x02, 0xd0, 0x3d, 0x3c, 0x79, 0xf5, 0xea, 0xe8, 0x6c, 0xea, 0x78, 0xef,
-	0x1c, 0x18, 0x1c, 0xfa, 0x49, 0x14, 0x47, 0xbe, 0x64, 0x77, 0xac, 0xc9, 0xf7, 0x61, 0x5b, 0x3d,
-	0xf2, 0xc2, 0xe4, 0x49, 0x0b, 0x86, 0x49, 0x2a, 0x8a, 0x76, 0xc1, 0x24, 0xe5, 0xfd, 0x63, 0x98,
-	0x84, 0x19, 0xc3, 0xe5, 0xab, 0xb6, 0x6c, 0x35, 0x50, 0xb5, 0xbd, 0xad, 0x7d, 0x21, 0xbf, 0x4f,
-	0xd5, 0xd7, 0x8d, 0xa6, 0xde, 0xb6, 0xde, 0xc3, 0xbd, 0x03, 0x18, 0x17, 0x8e, 0x1e, 0xc7, 0x42,
-	0x92, 0x2f, 0x01, 0x42, 0x0b, 0x28, 0x7f, 0x15, 0x3f, 0xee, 0xd9, 0x2a, 0x15, 0xaa, 0xb4, 0xa2,
-	0xe4, 0xcd, 0x61, 0x78, 0xc6, 0x84, 0x5c, 0x9a, 0xff, 0x1b, 0x3f, 0x86, 0xfe, 0x46, 0xac, 0x7e,
-	0x0c, 0x78, 0x74, 0x63, 0xd6, 0xe9, 0xde, 0x46, 0xac, 0x0e, 0x78, 0x74, 0x13, 0x74, 0xd1, 0xcc,
-	0xb3, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x5a, 0xf2, 0x22, 0xac, 0xec, 0x0e, 0x00, 0x00,
+	// 1479 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8d, 0x57, 0x4b, 0x6f, 0x1c, 0x45,
+	0x10, 0xce, 0xbe, 0x77, 0x6b, 0xbd, 0xeb, 0x75, 0x03, 0x61, 0x40, 0x08, 0x85, 0x51, 0x40, 0x16,
+	0x0a, 0x16, 0x38, 0x07, 0x38, 0x20, 0xa1, 0xd8, 0x89, 0xe4, 0x08, 0x27, 0x5e, 0x8d, 0x4d, 0x38,
+	0xc2, 0xec, 0x4c, 0x7b, 0x3d, 0xca, 0xee, 0xf4, 0x68, 0x66, 0x36, 0xb1, 0xff, 0x04, 0x77, 0x2e,
+	0x20, 0x4e, 0xb9, 0x73, 0xe2, 0xc2, 0x4f, 0xe1, 0xca, 0xef, 0xa0, 0xaa, 0xba, 0x7b, 0x5e, 0x76,
+	0x2c, 0x4e, 0x3b, 0xf5, 0x55, 0x75, 0x75, 0x55, 0x75, 0xbd, 0x16, 0x66, 0x8b, 0x95, 0x0a, 0x5e,
+	0x06, 0x17, 0x7e, 0x14, 0xef, 0x25, 0xa9, 0xca, 0x95, 0xe8, 0x47, 0xfc, 0xeb, 0xfe, 0xd5, 0x02,
+	0x38, 0x4b, 0xfd, 0x38, 0x3b, 0x97, 0xe9, 0x7c, 0x21, 0xee, 0x42, 0xdf, 0x5f, 0xab, 0x4d, 0x9c,
+	0x3b, 0xad, 0x7b, 0xad, 0xdd, 0x2d, 0xcf, 0x50, 0x84, 0x67, 0x32, 0x0e, 0x65, 0xea, 0xb4, 0x11,
+	0x1f, 0x79, 0x86, 0x12, 0x1f, 0xc1, 0x28, 0x95, 0x41, 0x94, 0x44, 0x12, 0x8f, 0x74, 0x98, 0x55,
+	0x02, 0xc2, 0x81, 0x41, 0xe2, 0x5f, 0xad, 0x94, 0x1f, 0x3a, 0x5d, 0x56, 0x67, 0x49, 0xe1, 0xc2,
+	0x96, 0xd6, 0x30, 0xdf, 0x2c, 0xbe, 0x97, 0x57, 0x4e, 0x8f, 0xd9, 0x35, 0x4c, 0x7c, 0x0c, 0x10,
+	0x65, 0x87, 0x2a, 0x8a, 0x17, 0x7e, 0x26, 0x9d, 0x3e, 0x4a, 0x0c, 0xbd, 0x0a, 0xe2, 0xfe, 0xd2,
+	0x82, 0xfe, 0x0b, 0x95, 0x4b, 0x34, 0x1b, 0xcd, 0xc8, 0xa3, 0xb5, 0xcc, 0x72, 0x7f, 0x9d, 0xb0,
+	0xe5, 0x5d, 0xaf, 0x04, 0x48, 0x51, 0x26, 0x57, 0xe7, 0xa8, 0xf6, 0x25, 0x5e, 0xd5, 0xe6, 0xab,
+	0x2a, 0x08, 0x19, 0xf3, 0x0a, 0xf5, 0xa4, 0x8f, 0xc2, 0x30, 0x95, 0x59, 0x66, 0xfc, 0xa8, 0x61,
+	0x56, 0x46, 0x5a, 0x99, 0x6e, 0x29, 0x63, 0x31, 0xf7, 0xd7, 0x16, 0x8c, 0x9f, 0x5c, 0xca, 0x60,
+	0x93, 0x47, 0x2a, 0xbe, 0x25, 0x98, 0x1f, 0xc2, 0x50, 0xb2, 0x98, 0xb2, 0xe1, 0x2c, 0x68, 0xe2,
+	0x05, 0x2a, 0xce, 0x53, 0x3f, 0xb0, 0xf1, 0x2c, 0x68, 0xf1, 0x19, 0x4c, 0xad, 0x9c, 0x09, 0x9b,
+	0x8e, 0x6a, 0x03, 0x15, 0x02, 0xba, 0xa1, 0x9f, 0xfb, 0x26, 0xa8, 0xfc, 0xed, 0xfe, 0x0c, 0xb3,
+	0x53, 0x19, 0xa4, 0x32, 0x9f, 0xa7, 0x2a, 0x51, 0x99, 0xbf, 0xd2, 0xf6, 0x99, 0x47, 0x6d, 0xbd,
+	0xfd, 0x51, 0xdb, 0xcd, 0x47, 0xe5, 0x53, 0xa4, 0x09, 0xed, 0xeb, 0xec, 0x4e, 0x3c, 0x43, 0xb9,
+	0x87, 0xb0, 0xad, 0x6f, 0xf8, 0x31, 0xca, 0x63, 0x0c, 0xc7, 0x2d, 0x17, 0x60, 0x5e, 0xbc, 0xd6,
+	0x42, 0xa8, 0xbe, 0x43, 0x79, 0x61, 0x48, 0xf7, 0xef, 0x16, 0xf4, 0x8e, 0xd5, 0x12, 0xcf, 0xa2,
+	0x8c, 0x6f, 0x62, 0xad, 0x0f, 0x5b, 0x92, 0xb4, 0xe6, 0x2a, 0x89, 0x02, 0x7b, 0xd8, 0x50, 0x85,
+	0xdb, 0x9d, 0xd2, 0x6d, 0x71, 0x0f, 0xc6, 0x9c, 0xfa, 0xcf, 0x37, 0xeb, 0x05, 0x9a, 0xd1, 0xe5,
+	0xd4, 0xa8, 0x42, 0x74, 0x4f, 0x7e, 0x19, 0x1f, 0xf9, 0xd9, 0x85, 0x89, 0x97, 0x25, 0x29, 0x0c,
+	0x2c, 0xc8, 0xbc, 0x3e, 0xf3, 0x4a, 0x40, 0xbc, 0x0b, 0xbd, 0x08, 0x9d, 0xb9, 0x74, 0x06, 0xc8,
+	0x99, 0x78, 0x9a, 0x70, 0xff, 0x6d, 0xc1, 0xc8, 0x93, 0x81, 0x8c, 0x92, 0x1c, 0x7d, 0xc0, 0xdb,
+	0x31, 0x1e, 0x9b, 0x34, 0x7e, 0xe1, 0xaf, 0x36, 0xd2, 0x64, 0x41, 0x15, 0xe2, 0x08, 0xe5, 0x7e,
+	0xbe, 0xc9, 0x38, 0xce, 0x5d, 0xcf, 0x50, 0xe4, 0xcb, 0x05, 0x5d, 0x6b, 0x7c, 0xa1, 0x6f, 0xd2,
+	0xb6, 0xf4, 0x31, 0xfd, 0xe3, 0x6c, 0xb3, 0x96, 0xa1, 0xf5, 0xa5, 0x02, 0x89, 0x5d, 0xd8, 0xb6,
+	0xc9, 0x62, 0xf3, 0xb4, 0xc7, 0xb1, 0x6b, 0xc2, 0xe2, 0x13, 0xe8, 0xae, 0xd4, 0x32, 0x43, 0xb7,
+	0x3a, 0xbb, 0xe3, 0xfd, 0xc9, 0x9e, 0xee, 0x06, 0x7b, 0x1c, 0x7a, 0x8f, 0x59, 0xe4, 0xbe, 0x4c,
+	0x53, 0x95, 0x1e, 0xaa, 0x50, 0xb2, 0x93, 0x58, 0x53, 0x05, 0xe0, 0xfe, 0xde, 0xc6, 0xe7, 0xce,
+	0xfd, 0x34, 0x3f, 0xdd, 0x2c, 0x0e, 0xa9, 0xaf, 0xe8, 0x27, 0xe3, 0x16, 0xf3, 0xf4, 0x31, 0xbb,
+	0x3a, 0xf1, 0x2c, 0x49, 0x86, 0x61, 0x96, 0x6c, 0xd2, 0x28, 0xbf, 0x7a, 0x2c, 0x31, 0xfd, 0xa2,
+	0xdc, 0x94, 0x61, 0x13, 0x16, 0x9f, 0xc3, 0x4c, 0x25, 0x32, 0xf5, 0xa9, 0x84, 0xac, 0xa8, 0x0e,
+	0xc2, 0x35, 0x9c, 0x02, 0x92, 0x91, 0x09, 0x47, 0x32, 0x5a, 0x5e, 0xe4, 0x36, 0x20, 0x15, 0x48,
+	0xec, 0x81, 0x48, 0xfc, 0x14, 0xb3, 0x56, 0xd3, 0x27, 0xe7, 0xe7, 0x19, 0xe6, 0x6d, 0x8f, 0x05,
+	0x6f, 0xe0, 0x50, 0x95, 0xab, 0xd7, 0x71, 0xd9, 0x09, 0xfa, 0xba, 0xca, 0xab, 0x18, 0x55, 0x21,
+	0xd3, 0x58, 0x6c, 0xab, 0x28, 0xa0, 0x2a, 0x1c, 0xe8, 0x2a, 0xac, 0xa3, 0xee, 0x9f, 0x2d, 0x98,
+	0x9e, 0x62, 0x6a, 0xfe, 0xaf, 0x00, 0x51, 0x8b, 0x42, 0x59, 0xe3, 0x89, 0xce, 0x85, 0x0a, 0x42,
+	0xd9, 0xc6, 0xea, 0x4d, 0x4f, 0xd0, 0xc4, 0x0d, 0xa6, 0x74, 0x6f, 0x32, 0x85, 0xc3, 0x6f, 0xac,
+	0x68, 0xe4, 0x45, 0x03, 0x76, 0x7f, 0x6b, 0x03, 0xcc, 0x37, 0xf9, 0x01, 0xa5, 0xf9, 0xad, 0x06,
+	0x63, 0xe2, 0x5e, 0x54, 0x8d, 0x35, 0xd4, 0x8d, 0x89, 0x8b, 0xce, 0x61, 0xea, 0xe1, 0xc3, 0x79,
+	0x4a, 0xe5, 0xc6, 0xc4, 0x0a, 0x42, 0x99, 0x46, 0x69, 0x2f, 0x99, 0xad, 0x8b, 0xb0, 0x04, 0xc4,
+	0x03, 0xd8, 0xc1, 0xe4, 0x0c, 0x37, 0x41, 0xd5, 0x4f, 0x5d, 0x8e, 0xd7, 0x19, 0xf4, 0xe2, 0xd8,
+	0x63, 0x54, 0x9a, 0xa9, 0x12, 0xcc, 0xf0, 0x85, 0xa8, 0x51, 0xdc, 0xc0, 0xa9, 0xca, 0x9f, 0x46,
+	0xcb, 0x18, 0x8b, 0x0f, 0xe3, 0xe0, 0x0c, 0xeb, 0xf2, 0x25, 0xc7, 0xfd, 0xa7, 0x0b, 0xc3, 0x47,
+	0x81, 0x69, 0xf0, 0x18, 0x9e, 0x57, 0x32, 0xcd, 0x90, 0xb0, 0xe1, 0x31, 0x24, 0xbd, 0x57, 0xac,
+	0xe2, 0x40, 0x9a, 0xe8, 0x68, 0x82, 0x9a, 0x3b, 0x96, 0xeb, 0x71, 0xb4, 0x36, 0x49, 0xdd, 0xf5,
+	0x0a, 0xda, 0xf0, 0xe6, 0x69, 0x84, 0x87, 0x74, 0x88, 0x0a, 0x9a, 0x03, 0x64, 0x4d, 0x28, 0x02,
+	0x64, 0x01, 0xf1, 0x25, 0x0c, 0x73, 0x33, 0xc1, 0x1d, 0x40, 0xe6, 0x78, 0x5f, 0xd8, 0x7a, 0x2e,
+	0x27, 0xfb, 0xd1, 0x1d, 0xaf, 0x90, 0x12, 0xf7, 0xa1, 0x4b, 0x83, 0xcb, 0x19, 0xb3, 0xf4, 0xd4,
+	0x4a, 0xeb, 0x61, 0x8a, 0x92, 0xcc, 0x15, 0x0f, 0xb1, 0x01, 0xd8, 0x69, 0xe6, 0x6c, 0xb1, 0xe8,
+	0x3b, 0x56, 0xb4, 0x32, 0xe6, 0x50, 0xbe, 0x94, 0x13, 0x07, 0x30, 0xcd, 0x6a, 0x73, 0xc6, 0x99,
+	0xf0, 0x49, 0xc7, 0x9e, 0x6c, 0x4e, 0x21, 0x3c, 0xde, 0x38, 0x21, 0xbe, 0x83, 0x49, 0x56, 0x9d,
+	0x24, 0xce, 0x94, 0x55, 0xbc, 0x5f, 0x57, 0x51, 0x8c, 0x19, 0xd4, 0x50, 0x97, 0x67, 0x05, 0xd5,
+	0xde, 0xe4, 0x6c, 0x37, 0x14, 0xd4, 0x1b, 0x17, 0x2b, 0xa8, 0x42, 0xe2, 0x5b, 0x5c, 0x4f, 0x2a,
+	0xa5, 0xeb, 0xcc, 0xf8, 0xfc, 0xdd, 0xf2, 0x7c, 0xb5, 0xac, 0xf1, 0x78, 0x4d, 0x9a, 0x1e, 0x24,
+	0x31, 0x35, 0xe4, 0xec, 0xd4, 0x1f, 0xa4, 0xac, 0x2d, 0x7a, 0x10, 0x2b, 0x75, 0x30, 0xc4, 0x4d,
+	0x81, 0x93, 0xca, 0xfd, 0xa3, 0x03, 0x13, 0xc6, 0x8e, 0xa4, 0x1f, 0xf2, 0x4a, 0xf6, 0xf6, 0x24,
+	0xab, 0x54, 0x67, 0xfb, 0x6d, 0xd5, 0xd9, 0xa9, 0x55, 0x67, 0x6d, 0x4f, 0xea, 0x36, 0xf7, 0xa4,
+	0xfb, 0x30, 0x49, 0x52, 0xf9, 0xea, 0xa0, 0x18, 0x7a, 0x3a, 0xd5, 0xea, 0x20, 0x8f, 0xdf, 0x4b,
+	0x2e, 0x55, 0x5d, 0x84, 0x86, 0xaa, 0x57, 0xf1, 0xa0, 0x59, 0xc5, 0x3c, 0x0a, 0x79, 0x2e, 0x32,
+	0x7f, 0x68, 0x47, 0x61, 0x01, 0x51, 0x01, 0xe0, 0xdc, 0xc1, 0x7b, 0xd4, 0xda, 0x19, 0xe9, 0x02,
+	0xb0, 0x74, 0xbd, 0x00, 0xa0, 0x59, 0x00, 0x68, 0x51, 0xa2, 0x77, 0xbb, 0xb1, 0xb6, 0x48, 0x53,
+	0x54, 0x84, 0xe1, 0xcb, 0x25, 0x46, 0x67, 0x8b, 0x61, 0x4d, 0x90, 0x2e, 0xfc, 0x30, 0xcb, 0xe0,
+	0x44, 0xeb, 0x2a, 0x00, 0x9a, 0x00, 0x48, 0x14, 0x05, 0xcf, 0xa9, 0x87, 0x8b, 0x69, 0x15, 0x73,
+	0x43, 0x18, 0xd8, 0x06, 0xf9, 0x05, 0x05, 0xda, 0xb7, 0x1b, 0xce, 0x78, 0xff, 0x3d, 0xfb, 0xd0,
+	0xb5, 0x37, 0xf4, 0x8c, 0x10, 0x4e, 0xb7, 0x81, 0x7e, 0x67, 0xbd, 0xbb, 0x8c, 0xf7, 0x67, 0x56,
+	0xde, 0xf6, 0x14, 0xcf, 0x0a, 0xb8, 0xc7, 0x00, 0xac, 0xe4, 0x29, 0x2d, 0x16, 0xe4, 0x0b, 0xa7,
+	0xa8, 0xd9, 0x6e, 0x35, 0x21, 0x66, 0xd0, 0xc1, 0x1e, 0x65, 0x9a, 0x0c, 0x7d, 0x52, 0x2c, 0x94,
+	0x9e, 0x72, 0x66, 0x3b, 0xd3, 0x94, 0x8b, 0xc5, 0xcc, 0xda, 0x4e, 0xaf, 0xe2, 0xa0, 0x54, 0xd6,
+	0xbe, 0x41, 0x59, 0xa7, 0x50, 0xe6, 0x7e, 0x0d, 0x53, 0x3e, 0x84, 0x0b, 0x46, 0x8e, 0x89, 0x85,
+	0x0e, 0x7c, 0x0a, 0x3d, 0x5e, 0x81, 0x8c, 0xbb, 0xdb, 0x35, 0x77, 0xd1, 0x7a, 0xcd, 0x75, 0x9f,
+	0xc3, 0x48, 0x57, 0x33, 0x2d, 0xe7, 0xf8, 0xb0, 0x89, 0x26, 0xec, 0x1e, 0x58, 0xd0, 0xa5, 0xbe,
+	0xf6, 0xad, 0xfa, 0xde, 0xb4, 0x61, 0xf4, 0x84, 0x9b, 0xb1, 0xd4, 0x6b, 0xa5, 0xc9, 0xee, 0x56,
+	0x33, 0xbb, 0xcb, 0x85, 0xad, 0xdd, 0x5c, 0xd8, 0xbe, 0x81, 0x1e, 0x2f, 0x8a, 0xec, 0xe0, 0x74,
+	0xdf, 0x2d, 0x5a, 0x99, 0xd5, 0x6b, 0xbf, 0xd6, 0xb8, 0x15, 0x9c, 0x91, 0xa4, 0xa7, 0x0f, 0xf0,
+	0xbe, 0xae, 0x59, 0xa9, 0xd9, 0xfb, 0x0b, 0x9a, 0x77, 0x72, 0xf3, 0x5d, 0xfb, 0x2b, 0xd3, 0x40,
+	0x49, 0x47, 0x88, 0x2b, 0x34, 0x97, 0xb1, 0xfe, 0x2b, 0x53, 0xd0, 0xf5, 0xec, 0x1e, 0x34, 0xb2,
+	0xdb, 0x7d, 0x00, 0xb3, 0xa6, 0x61, 0x62, 0x0b, 0x86, 0x73, 0xef, 0x64, 0x7e, 0x72, 0xfa, 0xe8,
+	0x78, 0x76, 0x47, 0x00, 0xf4, 0x0f, 0x4f, 0x9e, 0x3d, 0x7b, 0x7a, 0x36, 0x6b, 0xb9, 0x6f, 0x70,
+	0x01, 0x3d, 0xf4, 0xe3, 0x30, 0xc2, 0xf5, 0x57, 0xde, 0xb2, 0x44, 0x63, 0x0a, 0x50, 0x93, 0xcf,
+	0x4c, 0x9c, 0x34, 0x61, 0x2a, 0x89, 0xbc, 0xe8, 0x14, 0x95, 0x44, 0xd6, 0xa3, 0x97, 0xd8, 0x5f,
+	0x79, 0xf9, 0xaa, 0x2d, 0x5b, 0x0d, 0x94, 0xb6, 0xb7, 0x95, 0x9f, 0xe5, 0x3f, 0x24, 0x74, 0xbb,
+	0x91, 0xd4, 0xdb, 0xd6, 0x35, 0xdc, 0x3d, 0x80, 0x49, 0x61, 0xe8, 0x71, 0x94, 0xe5, 0xe2, 0x2b,
+	0x80, 0xc0, 0x02, 0x64, 0x2f, 0xd5, 0xc7, 0x8e, 0x7d, 0xa5, 0x42, 0xd4, 0xab, 0x08, 0xb9, 0xbb,
+	0x30, 0x3e, 0xc3, 0xe6, 0x35, 0x37, 0xff, 0x2a, 0x3f, 0x80, 0xe1, 0x3a, 0x5b, 0xfe, 0xb4, 0x50,
+	0xe1, 0x95, 0x59, 0xb6, 0x07, 0x48, 0x1f, 0x20, 0xb9, 0xe8, 0xb3, 0x9a, 0x87, 0xff, 0x01, 0x8c,
+	0x96, 0xca, 0xdf, 0x0a, 0x0f, 0x00, 0x00,
 }
@@ -20,8 +20,11 @@ type (
 	Contract interface {
 		GetState(hash.Hash32B) ([]byte, error)
 		SetState(hash.Hash32B, []byte) error
+		DeleteState(hash.Hash32B) error
+		IterateState(trie.Visitor) error
 		GetCode() ([]byte, error)
 		SetCode(hash.Hash32B, []byte)
+		DeleteCode()
 		SelfState() *State
 		Commit() error
 		RootHash() hash.Hash32B
@@ -51,6 +54,17 @@ func (c *contract) SetState(key hash.Hash32B, value []byte) error {
 	return c.trie.Upsert(key[:], value)
 }
 
+// DeleteState deletes the value from contract storage
+func (c *contract) DeleteState(key hash.Hash32B) error {
+	c.dirtyState = true
+	return c.trie.Delete(key[:])
+}
+
+// IterateState visits all entries in contract storage in the order of key
+func (c *contract) IterateState(visitor trie.Visitor) error {
+	return c.trie.Iterate(visitor)
+}
+
 // GetCode gets the contract's byte-code
 func (c *contract) GetCode() ([]byte, error) {
 	if c.code != nil {
@@ -66,6 +80,13 @@ func (c *contract) SetCode(hash hash.Hash32B, code []byte) {
 	c.dirtyCode = true
 }
 
+// DeleteCode removes the contract's byte-code, as if it has never been set
+func (c *contract) DeleteCode() {
+	c.State.CodeHash = nil
+	c.code = nil
+	c.dirtyCode = false
+}
+
 // State returns this contract's state
 func (c *contract) SelfState() *State {
 	return c.State
@@ -8,6 +8,7 @@ package state
 
 import (
 	"context"
+	"io"
 	"math/big"
 	"sort"
 	"sync"
@@ -18,6 +19,7 @@ import (
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
@@ -40,6 +42,12 @@ var (
 
 	// ErrFailedToUnmarshalState is the error that the state un-marshaling is failed
 	ErrFailedToUnmarshalState = errors.New("failed to unmarshal state")
+
+	// ErrHistoryStateNotEnabled is the error that the states of past heights are not kept
+	ErrHistoryStateNotEnabled = errors.New("history state is not enabled")
+
+	// ErrHistoryStateNotAvailable is the error that the states of a height before the history is kept are queried
+	ErrHistoryStateNotAvailable = errors.New("history state is not available")
 )
 
 const (
@@ -47,6 +55,8 @@ const (
 	CurrentHeightKey = "currentHeight"
 	// AccountTrieRootKey indicates the key of accountTrie root hash in underlying DB
 	AccountTrieRootKey = "accountTrieRoot"
+	// HistoryHeightKey indicates the key of the height from which the states of past heights are kept in underlying DB
+	HistoryHeightKey = "historyHeight"
 )
 
 type (
@@ -58,12 +68,18 @@ type (
 		Balance(string) (*big.Int, error)
 		Nonce(string) (uint64, error) // Note that Nonce starts with 1.
 		State(string) (*State, error)
+		StateAtHeight(string, uint64) (*State, error)
 		CachedState(string) (*State, error)
+		StateProof(string, uint64) (hash.Hash32B, [][]byte, error)
 		RootHash() hash.Hash32B
 		Height() (uint64, error)
 		NewWorkingSet() (WorkingSet, error)
+		NewWorkingSetAtHeight(uint64) (WorkingSet, error)
 		RunActions(uint64, []*action.Transfer, []*action.Vote, []*action.Execution, []action.Action) (hash.Hash32B, error)
 		Commit(WorkingSet) error
+		RollbackTo(uint64) error
+		ExportSnapshot(io.Writer, uint64) error
+		ImportSnapshot(io.Reader, uint64, hash.Hash32B) error
 		// Contracts
 		GetCodeHash(hash.PKHash) (hash.Hash32B, error)
 		GetCode(hash.PKHash) ([]byte, error)
@@ -85,6 +101,7 @@ type (
 		rootHash           hash.Hash32B    // new root hash after running executions in this block
 		dao                db.KVStore      // the underlying DB for account/contract storage
 		actionHandlers     []ActionHandler // the handlers to handle actions
+		keepHistory        bool            // keep the states of past heights in DB
 	}
 
 	// ActionHandler is the interface for the action handlers. For each incoming action, the assembled actions will be
@@ -171,6 +188,7 @@ func NewFactory(cfg *config.Config, opts ...FactoryOption) (Factory, error) {
 	sf := &factory{
 		currentChainHeight: 0,
 		numCandidates:      cfg.Chain.NumCandidates,
+		keepHistory:        cfg.Chain.EnableHistoryState,
 	}
 
 	for _, opt := range opts {
@@ -227,6 +245,32 @@ func (sf *factory) State(addr string) (*State, error) {
 	return sf.activeWs.state(addr)
 }
 
+// StateAtHeight returns the confirmed state on the chain at a given height
+func (sf *factory) StateAtHeight(addr string, height uint64) (*State, error) {
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	root, err := sf.rootHashAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := sf.readOnlyAccountTrie(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load state trie on height %d", height)
+	}
+	pkHash, err := iotxaddress.GetPubkeyHash(addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when getting the pubkey hash")
+	}
+	mstate, err := tr.Get(pkHash)
+	if errors.Cause(err) == trie.ErrNotExist {
+		return nil, errors.Wrapf(ErrAccountNotExist, "addrHash = %x", pkHash)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get state of %x on height %d", pkHash, height)
+	}
+	return bytesToState(mstate)
+}
+
 // CachedState returns the cached state if the address exists in local cache
 func (sf *factory) CachedState(addr string) (*State, error) {
 	sf.mutex.RLock()
@@ -234,6 +278,47 @@ func (sf *factory) CachedState(addr string) (*State, error) {
 	return sf.activeWs.CachedState(addr)
 }
 
+// StateProof returns the merkle proof of the confirmed state of an address at a given height, together with the state
+// root it is proved against
+func (sf *factory) StateProof(addr string, height uint64) (hash.Hash32B, [][]byte, error) {
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	pkHash, err := iotxaddress.GetPubkeyHash(addr)
+	if err != nil {
+		return hash.ZeroHash32B, nil, errors.Wrap(err, "error when getting the pubkey hash")
+	}
+	root, err := sf.rootHashAtHeight(height)
+	if err != nil {
+		return hash.ZeroHash32B, nil, err
+	}
+	tr, err := sf.readOnlyAccountTrie(root)
+	if err != nil {
+		return hash.ZeroHash32B, nil, errors.Wrapf(err, "failed to load state trie at root %x", root)
+	}
+	proof, err := tr.Prove(pkHash)
+	if err != nil {
+		return hash.ZeroHash32B, nil, errors.Wrapf(err, "failed to generate the proof of %x", pkHash)
+	}
+	return root, proof, nil
+}
+
+// VerifyStateProof verifies the merkle proof of the state of an address against a state root, and returns the proved
+// state. It returns ErrAccountNotExist if the proof shows the address does not exist
+func VerifyStateProof(root hash.Hash32B, addr string, proof [][]byte) (*State, error) {
+	pkHash, err := iotxaddress.GetPubkeyHash(addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when getting the pubkey hash")
+	}
+	mstate, err := trie.VerifyProof(root, pkHash, proof)
+	if errors.Cause(err) == trie.ErrNotExist {
+		return nil, errors.Wrapf(ErrAccountNotExist, "addrHash = %x", pkHash)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to verify the proof of %x", pkHash)
+	}
+	return bytesToState(mstate)
+}
+
 // RootHash returns the hash of the root node of the state trie
 func (sf *factory) RootHash() hash.Hash32B {
 	sf.mutex.RLock()
@@ -255,7 +340,19 @@ func (sf *factory) Height() (uint64, error) {
 func (sf *factory) NewWorkingSet() (WorkingSet, error) {
 	sf.mutex.Lock()
 	defer sf.mutex.Unlock()
-	return NewWorkingSet(sf.currentChainHeight, sf.dao, sf.rootHash, sf.actionHandlers)
+	return NewWorkingSet(sf.currentChainHeight, sf.dao, sf.rootHash, sf.keepHistory, sf.actionHandlers)
+}
+
+// NewWorkingSetAtHeight returns a working set on top of the confirmed states at a given height, which is used to re-run
+// the actions of the next block. Changes to it should never be committed
+func (sf *factory) NewWorkingSetAtHeight(height uint64) (WorkingSet, error) {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+	root, err := sf.rootHashAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return NewWorkingSet(height, sf.dao, root, sf.keepHistory, sf.actionHandlers)
 }
 
 // RunActions will be called 2 times in
@@ -296,6 +393,49 @@ func (sf *factory) Commit(ws WorkingSet) error {
 	return nil
 }
 
+// RollbackTo rolls the states back to a given height, and discards the states of all heights after it
+func (sf *factory) RollbackTo(height uint64) error {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+	value, err := sf.dao.Get(trie.AccountKVNameSpace, []byte(CurrentHeightKey))
+	if err != nil {
+		return errors.Wrap(err, "failed to get factory's height from underlying DB")
+	}
+	currentHeight := byteutil.BytesToUint64(value)
+	if height > currentHeight {
+		return errors.Errorf("cannot roll back to height %d higher than current height %d", height, currentHeight)
+	}
+	if height == currentHeight {
+		return nil
+	}
+	if !sf.keepHistory {
+		return errors.Wrapf(ErrHistoryStateNotEnabled, "failed to roll back to height %d", height)
+	}
+	root, err := sf.rootHashAtHeight(height)
+	if err != nil {
+		return err
+	}
+	batch := db.NewBatch()
+	batch.Put(trie.AccountKVNameSpace, []byte(AccountTrieRootKey), root[:], "failed to store accountTrie's root hash")
+	batch.Put(trie.AccountKVNameSpace, []byte(CurrentHeightKey), byteutil.Uint64ToBytes(height),
+		"failed to store accountTrie's current height")
+	for h := height + 1; h <= currentHeight; h++ {
+		batch.Delete(trie.AccountKVNameSpace, accountTrieRootKey(h), "failed to delete accountTrie's root hash on height %d", h)
+		batch.Delete(trie.CandidateKVNameSpace, byteutil.Uint64ToBytes(h), "failed to delete Candidates on height %d", h)
+	}
+	if err := sf.dao.Commit(batch); err != nil {
+		return errors.Wrapf(err, "failed to roll back to height %d", height)
+	}
+	ws, err := NewWorkingSet(height, sf.dao, root, sf.keepHistory, sf.actionHandlers)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load working set on height %d", height)
+	}
+	sf.activeWs = ws
+	sf.currentChainHeight = height
+	sf.rootHash = root
+	return nil
+}
+
 //======================================
 // Contract functions
 //======================================
@@ -382,3 +522,60 @@ func (sf *factory) getRoot(nameSpace string, key string) (hash.Hash32B, error) {
 	}
 	return trieRoot, nil
 }
+
+// rootHashAtHeight returns the accountTrie's root hash on a given height. With history kept, the heights before the
+// history height are not available
+func (sf *factory) rootHashAtHeight(height uint64) (hash.Hash32B, error) {
+	if !sf.keepHistory {
+		currentHeight, err := sf.dao.Get(trie.AccountKVNameSpace, []byte(CurrentHeightKey))
+		if err != nil {
+			return hash.ZeroHash32B, errors.Wrap(err, "failed to get factory's height from underlying DB")
+		}
+		if height != byteutil.BytesToUint64(currentHeight) {
+			return hash.ZeroHash32B, errors.Wrapf(ErrHistoryStateNotEnabled, "failed to get state on height %d", height)
+		}
+	} else {
+		historyHeight, err := sf.dao.Get(trie.AccountKVNameSpace, []byte(HistoryHeightKey))
+		switch errors.Cause(err) {
+		case nil:
+			if h := byteutil.BytesToUint64(historyHeight); height < h {
+				return hash.ZeroHash32B, errors.Wrapf(
+					ErrHistoryStateNotAvailable,
+					"failed to get state on height %d, history is not available before height %d",
+					height,
+					h,
+				)
+			}
+		case db.ErrNotExist, bolt.ErrBucketNotFound:
+			return hash.ZeroHash32B, errors.Wrapf(
+				ErrHistoryStateNotAvailable,
+				"failed to get state on height %d, history is not kept yet",
+				height,
+			)
+		default:
+			return hash.ZeroHash32B, errors.Wrap(err, "failed to get factory's history height from underlying DB")
+		}
+	}
+	root, err := sf.dao.Get(trie.AccountKVNameSpace, accountTrieRootKey(height))
+	if err != nil {
+		return hash.ZeroHash32B, errors.Wrapf(err, "failed to get accountTrie's root hash on height %d", height)
+	}
+	return byteutil.BytesTo32B(root), nil
+}
+
+// readOnlyAccountTrie opens the accountTrie at a given root hash, changes to it will never be committed to DB
+func (sf *factory) readOnlyAccountTrie(root hash.Hash32B) (trie.Trie, error) {
+	tr, err := trie.NewTrieSharedDB(db.NewCachedKVStore(sf.dao), trie.AccountKVNameSpace, root)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate state trie")
+	}
+	if err := tr.Start(context.Background()); err != nil {
+		return nil, errors.Wrapf(err, "failed to load state trie from root = %x", root)
+	}
+	return tr, nil
+}
+
+// accountTrieRootKey returns the key of accountTrie's root hash on a given height
+func accountTrieRootKey(height uint64) []byte {
+	return append([]byte(AccountTrieRootKey), byteutil.Uint64ToBytes(height)...)
+}
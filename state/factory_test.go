@@ -16,6 +16,7 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/action"
@@ -711,6 +712,394 @@ func TestUnvote(t *testing.T) {
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{b.RawAddress + ":200"}))
 }
 
+func TestFailedTransfer(t *testing.T) {
+	require := require.New(t)
+
+	a := testaddress.Addrinfo["alfa"]
+	b := testaddress.Addrinfo["bravo"]
+	cfg := config.Default
+	sf, err := NewFactory(&cfg, InMemTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = sf.RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+
+	// the second transfer spends more than the balance left, which fails without aborting the others
+	tx1, err := action.NewTransfer(uint64(1), big.NewInt(60), a.RawAddress, b.RawAddress, nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tx2, err := action.NewTransfer(uint64(2), big.NewInt(60), a.RawAddress, b.RawAddress, nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tx3, err := action.NewTransfer(uint64(3), big.NewInt(40), a.RawAddress, b.RawAddress, nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	ws, err := sf.NewWorkingSet()
+	require.NoError(err)
+	_, err = ws.RunActions(1, []*action.Transfer{tx1, tx2, tx3}, nil, nil, nil)
+	require.NoError(err)
+	require.Equal(1, len(ws.FailedActions()))
+	require.Equal(ErrNotEnoughBalance, errors.Cause(ws.FailedActions()[tx2.Hash()]))
+	require.NoError(sf.Commit(ws))
+	balance, err := sf.Balance(a.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(0), balance)
+	nonce, err := sf.Nonce(a.RawAddress)
+	require.NoError(err)
+	require.Equal(uint64(3), nonce)
+	balance, err = sf.Balance(b.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(100), balance)
+
+	// failed actions are reset in every run
+	ws, err = sf.NewWorkingSet()
+	require.NoError(err)
+	_, err = ws.RunActions(2, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Empty(ws.FailedActions())
+}
+
+func TestFailedVote(t *testing.T) {
+	require := require.New(t)
+
+	a := testaddress.Addrinfo["alfa"]
+	b := testaddress.Addrinfo["bravo"]
+	cfg := config.Default
+	sf, err := NewFactory(&cfg, InMemTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = sf.LoadOrCreateState(b.RawAddress, uint64(200))
+	require.NoError(err)
+	_, err = sf.RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+
+	// the vote to an invalid votee fails without aborting the others, and only consumes the nonce
+	vote1, err := action.NewVote(1, b.RawAddress, b.RawAddress, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	vote1.SetVoterPublicKey(b.PublicKey)
+	vote2, err := action.NewVote(1, a.RawAddress, b.RawAddress, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	vote2.SetVoterPublicKey(a.PublicKey)
+	vote3, err := action.NewVote(2, a.RawAddress, "invalid", uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	vote3.SetVoterPublicKey(a.PublicKey)
+	ws, err := sf.NewWorkingSet()
+	require.NoError(err)
+	_, err = ws.RunActions(1, nil, []*action.Vote{vote1, vote2, vote3}, nil, nil)
+	require.NoError(err)
+	require.Equal(1, len(ws.FailedActions()))
+	require.Error(ws.FailedActions()[vote3.Hash()])
+	require.NoError(sf.Commit(ws))
+	state, err := sf.State(a.RawAddress)
+	require.NoError(err)
+	require.Equal(uint64(2), state.Nonce)
+	require.Equal(b.RawAddress, state.Votee)
+	require.True(compareStrings(voteForm(sf.Candidates()), []string{b.RawAddress + ":300"}))
+}
+
+func TestParallelTransfers(t *testing.T) {
+	require := require.New(t)
+
+	a := testaddress.Addrinfo["alfa"]
+	b := testaddress.Addrinfo["bravo"]
+	c := testaddress.Addrinfo["charlie"]
+	d := testaddress.Addrinfo["delta"]
+	e := testaddress.Addrinfo["echo"]
+	f := testaddress.Addrinfo["foxtrot"]
+	newFactory := func() Factory {
+		cfg := config.Default
+		sf, err := NewFactory(&cfg, InMemTrieOption())
+		require.NoError(err)
+		require.NoError(sf.Start(context.Background()))
+		_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+		require.NoError(err)
+		_, err = sf.LoadOrCreateState(b.RawAddress, uint64(200))
+		require.NoError(err)
+		_, err = sf.RunActions(0, nil, nil, nil, nil)
+		require.NoError(err)
+		require.NoError(sf.Commit(nil))
+		// charlie nominates itself, and bravo votes for charlie
+		vote1, err := action.NewVote(1, c.RawAddress, c.RawAddress, uint64(100000), big.NewInt(0))
+		require.NoError(err)
+		vote1.SetVoterPublicKey(c.PublicKey)
+		vote2, err := action.NewVote(1, b.RawAddress, c.RawAddress, uint64(100000), big.NewInt(0))
+		require.NoError(err)
+		vote2.SetVoterPublicKey(b.PublicKey)
+		_, err = sf.RunActions(1, nil, []*action.Vote{vote1, vote2}, nil, nil)
+		require.NoError(err)
+		require.NoError(sf.Commit(nil))
+		return sf
+	}
+	newTransfer := func(nonce uint64, amount int64, sender, recipient string) *action.Transfer {
+		tsf, err := action.NewTransfer(nonce, big.NewInt(amount), sender, recipient, nil, uint64(0), big.NewInt(0))
+		require.NoError(err)
+		return tsf
+	}
+	// the transfers fall into 3 groups: alfa and delta, bravo, charlie and echo, and foxtrot
+	tsfs := []*action.Transfer{
+		newTransfer(1, 10, a.RawAddress, d.RawAddress),
+		newTransfer(2, 20, b.RawAddress, e.RawAddress),
+		action.NewCoinBaseTransfer(big.NewInt(5), f.RawAddress),
+		newTransfer(2, 200, a.RawAddress, d.RawAddress),
+		newTransfer(1, 5, e.RawAddress, c.RawAddress),
+	}
+	// so many transfers are run in parallel
+	for len(tsfs) < minParallelTsfs {
+		tsfs = append(tsfs, action.NewCoinBaseTransfer(big.NewInt(1), f.RawAddress))
+	}
+
+	sf := newFactory()
+	ws, err := sf.NewWorkingSet()
+	require.NoError(err)
+	groups, err := ws.(*workingSet).groupTsf(tsfs, 2)
+	require.NoError(err)
+	require.Equal(3, len(groups))
+	require.Equal([]*action.Transfer{tsfs[0], tsfs[3]}, groups[0].transfers)
+	require.Equal([]*action.Transfer{tsfs[1], tsfs[4]}, groups[1].transfers)
+	require.Equal(append([]*action.Transfer{tsfs[2]}, tsfs[5:]...), groups[2].transfers)
+	_, err = ws.RunActions(2, tsfs, nil, nil, nil)
+	require.NoError(err)
+	require.Equal(1, len(ws.FailedActions()))
+	require.Equal(ErrNotEnoughBalance, errors.Cause(ws.FailedActions()[tsfs[3].Hash()]))
+	require.NoError(sf.Commit(ws))
+	for addr, balance := range map[string]int64{
+		a.RawAddress: 90,
+		b.RawAddress: 180,
+		c.RawAddress: 5,
+		d.RawAddress: 10,
+		e.RawAddress: 15,
+		f.RawAddress: 5 + minParallelTsfs - 5,
+	} {
+		actual, err := sf.Balance(addr)
+		require.NoError(err)
+		require.Equal(big.NewInt(balance), actual)
+	}
+	state, err := sf.State(c.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(180), state.VotingWeight)
+	nonce, err := sf.Nonce(a.RawAddress)
+	require.NoError(err)
+	require.Equal(uint64(2), nonce)
+
+	// running the transfers one by one yields the same states
+	serial := newFactory()
+	for i, tsf := range tsfs {
+		_, err = serial.RunActions(uint64(2+i), []*action.Transfer{tsf}, nil, nil, nil)
+		require.NoError(err)
+		require.NoError(serial.Commit(nil))
+	}
+	require.Equal(serial.RootHash(), sf.RootHash())
+	_, candidates := sf.Candidates()
+	_, serialCandidates := serial.Candidates()
+	require.Equal(voteForm(0, serialCandidates), voteForm(0, candidates))
+}
+
+func TestStateAtHeight(t *testing.T) {
+	require := require.New(t)
+
+	a := testaddress.Addrinfo["alfa"]
+	b := testaddress.Addrinfo["bravo"]
+	cfg := config.Default
+	cfg.Chain.EnableHistoryState = true
+	sf, err := NewFactory(&cfg, InMemTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = sf.RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+
+	tx1, err := action.NewTransfer(uint64(1), big.NewInt(10), a.RawAddress, b.RawAddress, nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	_, err = sf.RunActions(1, []*action.Transfer{tx1}, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	tx2, err := action.NewTransfer(uint64(2), big.NewInt(20), a.RawAddress, b.RawAddress, nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	_, err = sf.RunActions(2, []*action.Transfer{tx2}, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+
+	for height, balance := range []int64{100, 90, 70} {
+		state, err := sf.StateAtHeight(a.RawAddress, uint64(height))
+		require.NoError(err)
+		require.Equal(big.NewInt(balance), state.Balance)
+		require.Equal(uint64(height), state.Nonce)
+	}
+	_, err = sf.StateAtHeight(b.RawAddress, 0)
+	require.Equal(ErrAccountNotExist, errors.Cause(err))
+	state, err := sf.StateAtHeight(b.RawAddress, 2)
+	require.NoError(err)
+	require.Equal(big.NewInt(30), state.Balance)
+	_, err = sf.StateAtHeight(a.RawAddress, 3)
+	require.Error(err)
+
+	// working set on a past height sees the states at that height, and changes to it are not committed
+	ws, err := sf.NewWorkingSetAtHeight(1)
+	require.NoError(err)
+	state, err = ws.CachedState(a.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(90), state.Balance)
+	state.Balance = big.NewInt(0)
+	state, err = sf.State(a.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(70), state.Balance)
+
+	// without history, only the state at current height could be queried
+	cfg.Chain.EnableHistoryState = false
+	kv := db.NewMemKVStore()
+	sf, err = NewFactory(&cfg, PrecreatedTrieDBOption(kv))
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = sf.RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	_, err = sf.RunActions(1, []*action.Transfer{tx1}, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	_, err = sf.StateAtHeight(a.RawAddress, 0)
+	require.Equal(ErrHistoryStateNotEnabled, errors.Cause(err))
+	state, err = sf.StateAtHeight(a.RawAddress, 1)
+	require.NoError(err)
+	require.Equal(big.NewInt(90), state.Balance)
+	_, err = sf.NewWorkingSetAtHeight(0)
+	require.Equal(ErrHistoryStateNotEnabled, errors.Cause(err))
+
+	// once history is enabled, the states before are not available
+	cfg.Chain.EnableHistoryState = true
+	sf, err = NewFactory(&cfg, PrecreatedTrieDBOption(kv))
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.StateAtHeight(a.RawAddress, 1)
+	require.Equal(ErrHistoryStateNotAvailable, errors.Cause(err))
+	_, err = sf.RunActions(2, []*action.Transfer{tx2}, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	_, err = sf.StateAtHeight(a.RawAddress, 1)
+	require.Equal(ErrHistoryStateNotAvailable, errors.Cause(err))
+	require.Error(sf.RollbackTo(1))
+	state, err = sf.StateAtHeight(a.RawAddress, 2)
+	require.NoError(err)
+	require.Equal(big.NewInt(70), state.Balance)
+}
+
+func TestRollbackTo(t *testing.T) {
+	require := require.New(t)
+
+	a := testaddress.Addrinfo["alfa"]
+	b := testaddress.Addrinfo["bravo"]
+	cfg := config.Default
+	cfg.Chain.EnableHistoryState = true
+	sf, err := NewFactory(&cfg, InMemTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = sf.RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	root0 := sf.RootHash()
+	for i := 1; i <= 3; i++ {
+		tx, err := action.NewTransfer(uint64(i), big.NewInt(10), a.RawAddress, b.RawAddress, nil, uint64(0), big.NewInt(0))
+		require.NoError(err)
+		_, err = sf.RunActions(uint64(i), []*action.Transfer{tx}, nil, nil, nil)
+		require.NoError(err)
+		require.NoError(sf.Commit(nil))
+	}
+	balance, err := sf.Balance(a.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(70), balance)
+
+	// cannot roll back to a future height
+	require.Error(sf.RollbackTo(4))
+
+	require.NoError(sf.RollbackTo(1))
+	height, err := sf.Height()
+	require.NoError(err)
+	require.Equal(uint64(1), height)
+	balance, err = sf.Balance(a.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(90), balance)
+	_, err = sf.StateAtHeight(a.RawAddress, 2)
+	require.Error(err)
+	_, err = sf.CandidatesByHeight(2)
+	require.Error(err)
+
+	// continue running actions on top of the rolled back height
+	tx, err := action.NewTransfer(uint64(2), big.NewInt(5), a.RawAddress, b.RawAddress, nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	_, err = sf.RunActions(2, []*action.Transfer{tx}, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	balance, err = sf.Balance(a.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(85), balance)
+
+	require.NoError(sf.RollbackTo(0))
+	require.Equal(root0, sf.RootHash())
+	_, err = sf.State(b.RawAddress)
+	require.Equal(ErrAccountNotExist, errors.Cause(err))
+
+	// without history, only rolling back to current height is allowed
+	cfg.Chain.EnableHistoryState = false
+	kv := db.NewMemKVStore()
+	sf, err = NewFactory(&cfg, PrecreatedTrieDBOption(kv))
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = sf.RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	_, err = sf.RunActions(1, []*action.Transfer{tx}, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	require.NoError(sf.RollbackTo(1))
+	require.Equal(ErrHistoryStateNotEnabled, errors.Cause(sf.RollbackTo(0)))
+}
+
+func TestStateProof(t *testing.T) {
+	require := require.New(t)
+
+	a := testaddress.Addrinfo["alfa"]
+	b := testaddress.Addrinfo["bravo"]
+	c := testaddress.Addrinfo["charlie"]
+	sf, err := NewFactory(cfg, InMemTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = sf.LoadOrCreateState(b.RawAddress, uint64(200))
+	require.NoError(err)
+	_, err = sf.RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+
+	root, proof, err := sf.StateProof(a.RawAddress, 0)
+	require.NoError(err)
+	require.Equal(sf.RootHash(), root)
+	state, err := VerifyStateProof(root, a.RawAddress, proof)
+	require.NoError(err)
+	require.Equal(big.NewInt(100), state.Balance)
+	// the proof cannot be used for another address
+	_, err = VerifyStateProof(root, b.RawAddress, proof)
+	require.Error(err)
+
+	root, proof, err = sf.StateProof(c.RawAddress, 0)
+	require.NoError(err)
+	_, err = VerifyStateProof(root, c.RawAddress, proof)
+	require.Equal(ErrAccountNotExist, errors.Cause(err))
+	// without history, only the state at current height could be proved
+	_, _, err = sf.StateProof(a.RawAddress, 1)
+	require.Equal(ErrHistoryStateNotEnabled, errors.Cause(err))
+}
+
 func TestLoadStoreHeight(t *testing.T) {
 	require := require.New(t)
 
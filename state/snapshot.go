@@ -0,0 +1,391 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/enc"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+// snapshot record types
+const (
+	snapshotEnd byte = iota
+	snapshotAccount
+	snapshotCode
+	snapshotStorage
+	snapshotCandidates
+)
+
+const (
+	// snapshotVersion is the version of the snapshot format
+	snapshotVersion uint32 = 1
+	// maxSnapshotFieldSize is the max size of the key or value of a snapshot record
+	maxSnapshotFieldSize = 64 << 20
+)
+
+var (
+	// snapshotMagic is the magic bytes at the beginning of a snapshot
+	snapshotMagic = []byte("IOTXSNP")
+
+	// ErrInvalidSnapshot is the error that the snapshot is malformed or does not match the expected state root
+	ErrInvalidSnapshot = errors.New("invalid state snapshot")
+)
+
+// ExportSnapshot writes the snapshot of the states on a given height, which consists of a header carrying the height
+// and the state root, followed by records of all accounts, contract code and contract storage, and the candidates
+func (sf *factory) ExportSnapshot(w io.Writer, height uint64) error {
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	root, err := sf.rootHashAtHeight(height)
+	if err != nil {
+		return err
+	}
+	candidates, err := sf.dao.Get(trie.CandidateKVNameSpace, byteutil.Uint64ToBytes(height))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get Candidates on height %d", height)
+	}
+	accountTrie, err := sf.readOnlyAccountTrie(root)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load state trie on height %d", height)
+	}
+	if err := writeSnapshotHeader(w, height, root); err != nil {
+		return err
+	}
+	if err := accountTrie.Iterate(func(addr []byte, value []byte) error {
+		return sf.exportAccount(w, addr, value)
+	}); err != nil {
+		return errors.Wrapf(err, "failed to export accounts on height %d", height)
+	}
+	if err := writeSnapshotRecord(w, snapshotCandidates, nil, candidates); err != nil {
+		return err
+	}
+	return writeSnapshotRecord(w, snapshotEnd, nil, nil)
+}
+
+// ImportSnapshot loads the states from a snapshot taken on a given height into a state factory lower than the height.
+// The states are committed to DB only if the state root rebuilt from the snapshot matches the given root
+func (sf *factory) ImportSnapshot(r io.Reader, height uint64, root hash.Hash32B) error {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+	snapshotHeight, snapshotRoot, err := readSnapshotHeader(r)
+	if err != nil {
+		return err
+	}
+	if currentHeight, err := sf.dao.Get(trie.AccountKVNameSpace, []byte(CurrentHeightKey)); err == nil &&
+		byteutil.BytesToUint64(currentHeight) >= height {
+		return errors.Errorf("cannot import snapshot on height %d into state factory on height %d", height,
+			byteutil.BytesToUint64(currentHeight))
+	}
+	if snapshotHeight != height || snapshotRoot != root {
+		return errors.Wrapf(ErrInvalidSnapshot, "snapshot of root %x on height %d does not match root %x on height %d",
+			snapshotRoot, snapshotHeight, root, height)
+	}
+
+	dao := db.NewCachedKVStore(sf.dao)
+	accountTrie, err := newSnapshotTrie(dao, trie.AccountKVNameSpace)
+	if err != nil {
+		return err
+	}
+	// storage trie of the contract being imported
+	var contract *State
+	var storageTrie trie.Trie
+	finishContract := func() error {
+		if storageTrie != nil && storageTrie.RootHash() != contract.Root {
+			return errors.Wrapf(ErrInvalidSnapshot, "storage root %x does not match %x", storageTrie.RootHash(),
+				contract.Root)
+		}
+		contract, storageTrie = nil, nil
+		return nil
+	}
+	// votes of the candidates according to the states, to check the Candidates against
+	candidateVotes := make(map[hash.PKHash]*big.Int)
+	hasCandidates := false
+	numAccounts := 0
+	for {
+		recordType, key, value, err := readSnapshotRecord(r)
+		if err != nil {
+			return err
+		}
+		if recordType == snapshotEnd {
+			break
+		}
+		switch recordType {
+		case snapshotAccount:
+			if err := finishContract(); err != nil {
+				return err
+			}
+			state, err := bytesToState(value)
+			if err != nil {
+				return errors.Wrapf(ErrInvalidSnapshot, "failed to decode state of %x: %v", key, err)
+			}
+			if err := accountTrie.Upsert(key, value); err != nil {
+				return errors.Wrapf(err, "failed to import state of %x", key)
+			}
+			if state.IsCandidate {
+				candidateVotes[byteutil.BytesTo20B(key)] = candidateVotesOf(key, state)
+			}
+			if state.Root != hash.ZeroHash32B && state.Root != trie.EmptyRoot {
+				contract = state
+				if storageTrie, err = newSnapshotTrie(dao, trie.ContractKVNameSpace); err != nil {
+					return err
+				}
+			}
+			numAccounts++
+		case snapshotCode:
+			if !bytes.Equal(hash.Hash256b(value), key) {
+				return errors.Wrapf(ErrInvalidSnapshot, "code does not match code hash %x", key)
+			}
+			if err := dao.Put(trie.CodeKVNameSpace, key, value); err != nil {
+				return errors.Wrapf(err, "failed to import code %x", key)
+			}
+		case snapshotStorage:
+			if storageTrie == nil {
+				return errors.Wrap(ErrInvalidSnapshot, "storage does not belong to a contract")
+			}
+			if err := storageTrie.Upsert(key, value); err != nil {
+				return errors.Wrapf(err, "failed to import storage %x", key)
+			}
+		case snapshotCandidates:
+			if hasCandidates {
+				return errors.Wrap(ErrInvalidSnapshot, "duplicate Candidates record")
+			}
+			hasCandidates = true
+			if err := verifySnapshotCandidates(value, candidateVotes); err != nil {
+				return err
+			}
+			if err := dao.Put(trie.CandidateKVNameSpace, byteutil.Uint64ToBytes(height), value); err != nil {
+				return errors.Wrapf(err, "failed to import Candidates on height %d", height)
+			}
+		default:
+			return errors.Wrapf(ErrInvalidSnapshot, "unknown record type %d", recordType)
+		}
+	}
+	if err := finishContract(); err != nil {
+		return err
+	}
+	// the Candidates are not part of the state root, so a snapshot without them cannot be trusted to elect delegates
+	if !hasCandidates {
+		return errors.Wrapf(ErrInvalidSnapshot, "missing Candidates on height %d", height)
+	}
+	if accountTrie.RootHash() != root {
+		return errors.Wrapf(ErrInvalidSnapshot, "state root %x does not match %x", accountTrie.RootHash(), root)
+	}
+
+	if err := dao.Put(trie.AccountKVNameSpace, []byte(AccountTrieRootKey), root[:]); err != nil {
+		return errors.Wrap(err, "failed to store accountTrie's root hash")
+	}
+	if err := dao.Put(trie.AccountKVNameSpace, accountTrieRootKey(height), root[:]); err != nil {
+		return errors.Wrapf(err, "failed to store accountTrie's root hash on height %d", height)
+	}
+	if err := dao.Put(trie.AccountKVNameSpace, []byte(CurrentHeightKey), byteutil.Uint64ToBytes(height)); err != nil {
+		return errors.Wrap(err, "failed to store accountTrie's current height")
+	}
+	// the states before the snapshot are not imported
+	if err := dao.Put(trie.AccountKVNameSpace, []byte(HistoryHeightKey), byteutil.Uint64ToBytes(height)); err != nil {
+		return errors.Wrap(err, "failed to store history height")
+	}
+	if err := accountTrie.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit the snapshot to underlying DB")
+	}
+	ws, err := NewWorkingSet(height, sf.dao, root, sf.keepHistory, sf.actionHandlers)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load working set on height %d", height)
+	}
+	sf.activeWs = ws
+	sf.currentChainHeight = height
+	sf.rootHash = root
+	logger.Info().
+		Uint64("height", height).
+		Hex("root", root[:]).
+		Int("accounts", numAccounts).
+		Msg("Imported state snapshot")
+	return nil
+}
+
+// exportAccount writes the records of an account, and its code and storage if it is a contract
+func (sf *factory) exportAccount(w io.Writer, addr []byte, value []byte) error {
+	if err := writeSnapshotRecord(w, snapshotAccount, addr, value); err != nil {
+		return err
+	}
+	state, err := bytesToState(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode state of %x", addr)
+	}
+	if len(state.CodeHash) > 0 {
+		code, err := sf.dao.Get(trie.CodeKVNameSpace, state.CodeHash)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get code of contract %x", addr)
+		}
+		if err := writeSnapshotRecord(w, snapshotCode, state.CodeHash, code); err != nil {
+			return err
+		}
+	}
+	if state.Root == hash.ZeroHash32B || state.Root == trie.EmptyRoot {
+		return nil
+	}
+	storageTrie, err := trie.NewTrieSharedDB(db.NewCachedKVStore(sf.dao), trie.ContractKVNameSpace, state.Root)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate storage trie of contract %x", addr)
+	}
+	if err := storageTrie.Start(context.Background()); err != nil {
+		return errors.Wrapf(err, "failed to load storage trie of contract %x", addr)
+	}
+	return storageTrie.Iterate(func(key []byte, value []byte) error {
+		return writeSnapshotRecord(w, snapshotStorage, key, value)
+	})
+}
+
+// candidateVotesOf returns the votes of a candidate, which are the voting weight plus its own balance if it votes to
+// itself
+func candidateVotesOf(addr []byte, state *State) *big.Int {
+	votes := big.NewInt(0)
+	if state.VotingWeight != nil {
+		votes.Add(votes, state.VotingWeight)
+	}
+	voteeAddr, _ := iotxaddress.GetPubkeyHash(state.Votee)
+	if bytes.Equal(addr, voteeAddr) {
+		votes.Add(votes, state.Balance)
+	}
+	return votes
+}
+
+// verifySnapshotCandidates checks the Candidates, which are not covered by the state root, against the states of the
+// candidates
+func verifySnapshotCandidates(value []byte, candidateVotes map[hash.PKHash]*big.Int) error {
+	candidates, err := Deserialize(value)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidSnapshot, "failed to decode Candidates: %v", err)
+	}
+	if len(candidates) != len(candidateVotes) {
+		return errors.Wrapf(ErrInvalidSnapshot, "number of Candidates %d does not match %d", len(candidates),
+			len(candidateVotes))
+	}
+	for _, candidate := range candidates {
+		addrHash, err := iotxaddress.GetPubkeyHash(candidate.Address)
+		if err != nil {
+			return errors.Wrapf(ErrInvalidSnapshot, "invalid address of candidate %s: %v", candidate.Address, err)
+		}
+		pkHash := byteutil.BytesTo20B(addrHash)
+		votes, ok := candidateVotes[pkHash]
+		if !ok {
+			return errors.Wrapf(ErrInvalidSnapshot, "%s is not a candidate", candidate.Address)
+		}
+		if keypair.HashPubKey(candidate.PublicKey) != pkHash {
+			return errors.Wrapf(ErrInvalidSnapshot, "public key of candidate %s does not match", candidate.Address)
+		}
+		if candidate.Votes == nil || candidate.Votes.Cmp(votes) != 0 {
+			return errors.Wrapf(ErrInvalidSnapshot, "votes of candidate %s does not match", candidate.Address)
+		}
+	}
+	return nil
+}
+
+// newSnapshotTrie creates an empty trie to import the snapshot into
+func newSnapshotTrie(dao db.CachedKVStore, name string) (trie.Trie, error) {
+	tr, err := trie.NewTrieSharedDB(dao, name, trie.EmptyRoot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate trie %s", name)
+	}
+	if err := tr.Start(context.Background()); err != nil {
+		return nil, errors.Wrapf(err, "failed to load trie %s", name)
+	}
+	return tr, nil
+}
+
+func writeSnapshotHeader(w io.Writer, height uint64, root hash.Hash32B) error {
+	stream := make([]byte, 0, len(snapshotMagic)+4+8+hash.HashSize)
+	stream = append(stream, snapshotMagic...)
+	tmp4B := make([]byte, 4)
+	enc.MachineEndian.PutUint32(tmp4B, snapshotVersion)
+	stream = append(stream, tmp4B...)
+	tmp8B := make([]byte, 8)
+	enc.MachineEndian.PutUint64(tmp8B, height)
+	stream = append(stream, tmp8B...)
+	stream = append(stream, root[:]...)
+	if _, err := w.Write(stream); err != nil {
+		return errors.Wrap(err, "failed to write snapshot header")
+	}
+	return nil
+}
+
+func readSnapshotHeader(r io.Reader) (uint64, hash.Hash32B, error) {
+	stream := make([]byte, len(snapshotMagic)+4+8+hash.HashSize)
+	if _, err := io.ReadFull(r, stream); err != nil {
+		return 0, hash.ZeroHash32B, errors.Wrapf(ErrInvalidSnapshot, "failed to read header: %v", err)
+	}
+	if !bytes.Equal(stream[:len(snapshotMagic)], snapshotMagic) {
+		return 0, hash.ZeroHash32B, errors.Wrap(ErrInvalidSnapshot, "magic bytes do not match")
+	}
+	stream = stream[len(snapshotMagic):]
+	if version := enc.MachineEndian.Uint32(stream[:4]); version != snapshotVersion {
+		return 0, hash.ZeroHash32B, errors.Wrapf(ErrInvalidSnapshot, "unsupported version %d", version)
+	}
+	height := enc.MachineEndian.Uint64(stream[4:12])
+	return height, byteutil.BytesTo32B(stream[12:]), nil
+}
+
+// writeSnapshotRecord writes a record as type, length-prefixed key and length-prefixed value
+func writeSnapshotRecord(w io.Writer, recordType byte, key []byte, value []byte) error {
+	stream := make([]byte, 0, 1+4+len(key)+4+len(value))
+	stream = append(stream, recordType)
+	tmp4B := make([]byte, 4)
+	enc.MachineEndian.PutUint32(tmp4B, uint32(len(key)))
+	stream = append(stream, tmp4B...)
+	stream = append(stream, key...)
+	enc.MachineEndian.PutUint32(tmp4B, uint32(len(value)))
+	stream = append(stream, tmp4B...)
+	stream = append(stream, value...)
+	if _, err := w.Write(stream); err != nil {
+		return errors.Wrap(err, "failed to write snapshot record")
+	}
+	return nil
+}
+
+func readSnapshotRecord(r io.Reader) (byte, []byte, []byte, error) {
+	recordType := make([]byte, 1)
+	if _, err := io.ReadFull(r, recordType); err != nil {
+		return 0, nil, nil, errors.Wrapf(ErrInvalidSnapshot, "failed to read record: %v", err)
+	}
+	key, err := readSnapshotField(r)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	value, err := readSnapshotField(r)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return recordType[0], key, value, nil
+}
+
+func readSnapshotField(r io.Reader) ([]byte, error) {
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(r, size); err != nil {
+		return nil, errors.Wrapf(ErrInvalidSnapshot, "failed to read record: %v", err)
+	}
+	length := enc.MachineEndian.Uint32(size)
+	if length > maxSnapshotFieldSize {
+		return nil, errors.Wrapf(ErrInvalidSnapshot, "record size %d exceeds the limit", length)
+	}
+	field := make([]byte, length)
+	if _, err := io.ReadFull(r, field); err != nil {
+		return nil, errors.Wrapf(ErrInvalidSnapshot, "failed to read record: %v", err)
+	}
+	return field, nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	a := testaddress.Addrinfo["alfa"]
+	b := testaddress.Addrinfo["bravo"]
+	c := testaddress.Addrinfo["charlie"]
+	cfg := config.Default
+	cfg.Chain.EnableHistoryState = true
+	sf, err := NewFactory(&cfg, InMemTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(err)
+	// c is a contract with code and storage
+	_, err = sf.LoadOrCreateState(c.RawAddress, uint64(0))
+	require.NoError(err)
+	pkHash, err := iotxaddress.GetPubkeyHash(c.RawAddress)
+	require.NoError(err)
+	contract := byteutil.BytesTo20B(pkHash)
+	code := []byte("test contract creation")
+	require.NoError(sf.SetCode(contract, code))
+	k1 := byteutil.BytesTo32B(hash.Hash256b([]byte("cat")))
+	v1 := byteutil.BytesTo32B(hash.Hash256b([]byte("dog")))
+	require.NoError(sf.SetContractState(contract, k1, v1))
+	_, err = sf.RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	vote, err := action.NewVote(uint64(1), a.RawAddress, a.RawAddress, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(action.Sign(vote, a.PrivateKey))
+	_, err = sf.RunActions(1, nil, []*action.Vote{vote}, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+	root1 := sf.RootHash()
+	tx, err := action.NewTransfer(uint64(2), big.NewInt(10), a.RawAddress, b.RawAddress, nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	_, err = sf.RunActions(2, []*action.Transfer{tx}, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf.Commit(nil))
+
+	var snapshot bytes.Buffer
+	require.NoError(sf.ExportSnapshot(&snapshot, 1))
+
+	// snapshot does not match the root
+	sf1, err := NewFactory(&cfg, InMemTrieOption())
+	require.NoError(err)
+	require.NoError(sf1.Start(context.Background()))
+	err = sf1.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), 1, hash.ZeroHash32B)
+	require.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	err = sf1.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), 2, root1)
+	require.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	// tampered snapshot
+	tampered := make([]byte, snapshot.Len())
+	copy(tampered, snapshot.Bytes())
+	tampered[len(tampered)-20]++
+	require.Error(sf1.ImportSnapshot(bytes.NewReader(tampered), 1, root1))
+	require.Error(sf1.ImportSnapshot(bytes.NewReader(snapshot.Bytes()[:snapshot.Len()-1]), 1, root1))
+	// snapshot missing the Candidates
+	var noCandidates bytes.Buffer
+	require.NoError(writeSnapshotHeader(&noCandidates, 1, root1))
+	require.NoError(writeSnapshotRecord(&noCandidates, snapshotEnd, nil, nil))
+	err = sf1.ImportSnapshot(bytes.NewReader(noCandidates.Bytes()), 1, root1)
+	require.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	require.Contains(err.Error(), "missing Candidates")
+
+	require.NoError(sf1.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), 1, root1))
+	require.Equal(root1, sf1.RootHash())
+	height, err := sf1.Height()
+	require.NoError(err)
+	require.Equal(uint64(1), height)
+	balance, err := sf1.Balance(a.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(100), balance)
+	v, err := sf1.GetCode(contract)
+	require.NoError(err)
+	require.Equal(code, v)
+	w, err := sf1.GetContractState(contract, k1)
+	require.NoError(err)
+	require.Equal(v1, w)
+	expected, err := sf.CandidatesByHeight(1)
+	require.NoError(err)
+	candidates, err := sf1.CandidatesByHeight(1)
+	require.NoError(err)
+	require.Equal(1, len(candidates))
+	require.Equal(expected, candidates)
+
+	// the imported states can be continued from the snapshot height
+	_, err = sf1.RunActions(2, []*action.Transfer{tx}, nil, nil, nil)
+	require.NoError(err)
+	require.NoError(sf1.Commit(nil))
+	require.Equal(sf.RootHash(), sf1.RootHash())
+
+	// cannot import into a factory not lower than the snapshot
+	require.Error(sf1.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), 1, root1))
+}
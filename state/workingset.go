@@ -9,8 +9,11 @@ package state
 import (
 	"context"
 	"math/big"
+	"runtime"
 	"sort"
+	"sync"
 
+	"github.com/boltdb/bolt"
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/action"
@@ -28,14 +31,21 @@ type (
 		LoadOrCreateState(string, uint64) (*State, error)
 		Nonce(string) (uint64, error) // Note that Nonce starts with 1.
 		CachedState(string) (*State, error)
+		DeleteCachedState(string) error
+		DeleteAccount(string) error
 		RunActions(uint64, []*action.Transfer, []*action.Vote, []*action.Execution, []action.Action) (hash.Hash32B, error)
+		FailedActions() map[hash.Hash32B]error
+		EnableGasFees(string)
 		commit() error
 		// contracts
 		GetCodeHash(hash.PKHash) (hash.Hash32B, error)
 		GetCode(hash.PKHash) ([]byte, error)
 		SetCode(hash.PKHash, []byte) error
+		DeleteCode(hash.PKHash) error
 		GetContractState(hash.PKHash, hash.Hash32B) (hash.Hash32B, error)
 		SetContractState(hash.PKHash, hash.Hash32B, hash.Hash32B) error
+		DeleteContractState(hash.PKHash, hash.Hash32B) error
+		IterateContractState(hash.PKHash, trie.Visitor) error
 		// private func
 		balance(string) (*big.Int, error)
 		state(string) (*State, error)
@@ -54,9 +64,21 @@ type (
 		savedAccount     map[string]*State        // save account state before being modified in this block
 		cachedAccount    map[hash.PKHash]*State   // accounts being modified in this block
 		cachedContract   map[hash.PKHash]Contract // contracts being modified in this block
+		deletedAccount   map[hash.PKHash]struct{} // accounts being deleted in this block
 		accountTrie      trie.Trie                // global state trie
 		dao              db.CachedKVStore         // the underlying DB for account/contract storage
+		trieOptions      []trie.Option            // the options to create account/contract tries
 		actionHandlers   []ActionHandler
+		failedActions    map[hash.Hash32B]error // actions failed in the last RunActions() without changing states
+		feeRecipient     string                 // the producer credited with the gas fees in RunActions(), if enabled
+		keepHistory      bool                   // keep the states of past heights in DB
+	}
+
+	// intrinsicGasAction is an action charged with the fee of its intrinsic gas
+	intrinsicGasAction interface {
+		Hash() hash.Hash32B
+		GasPrice() *big.Int
+		IntrinsicGas() (uint64, error)
 	}
 )
 
@@ -65,6 +87,7 @@ func NewWorkingSet(
 	version uint64,
 	kv db.KVStore,
 	root hash.Hash32B,
+	keepHistory bool,
 	actionHandlers []ActionHandler,
 ) (WorkingSet, error) {
 	ws := &workingSet{
@@ -73,10 +96,16 @@ func NewWorkingSet(
 		savedAccount:     make(map[string]*State),
 		cachedAccount:    make(map[hash.PKHash]*State),
 		cachedContract:   make(map[hash.PKHash]Contract),
+		deletedAccount:   make(map[hash.PKHash]struct{}),
 		dao:              db.NewCachedKVStore(kv),
 		actionHandlers:   actionHandlers,
+		failedActions:    make(map[hash.Hash32B]error),
+		keepHistory:      keepHistory,
 	}
-	tr, err := trie.NewTrieSharedDB(ws.dao, trie.AccountKVNameSpace, root)
+	if keepHistory {
+		ws.trieOptions = append(ws.trieOptions, trie.KeepHistoryOption())
+	}
+	tr, err := trie.NewTrieSharedDB(ws.dao, trie.AccountKVNameSpace, root, ws.trieOptions...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to generate state trie from config")
 	}
@@ -161,6 +190,33 @@ func (ws *workingSet) CachedState(addr string) (*State, error) {
 	return ws.cachedState(addrHash)
 }
 
+// DeleteCachedState removes the account and its contract from local cache, so that all changes made to it in this
+// working set are discarded. It is used to revert the creation of an account
+func (ws *workingSet) DeleteCachedState(addr string) error {
+	h, err := iotxaddress.GetPubkeyHash(addr)
+	if err != nil {
+		return errors.Wrap(err, "error when getting the pubkey hash")
+	}
+	addrHash := byteutil.BytesTo20B(h)
+	delete(ws.cachedAccount, addrHash)
+	delete(ws.cachedContract, addrHash)
+	return nil
+}
+
+// DeleteAccount deletes the account together with its contract code and storage, it is removed from the state trie
+// when running actions
+func (ws *workingSet) DeleteAccount(addr string) error {
+	h, err := iotxaddress.GetPubkeyHash(addr)
+	if err != nil {
+		return errors.Wrap(err, "error when getting the pubkey hash")
+	}
+	addrHash := byteutil.BytesTo20B(h)
+	delete(ws.cachedAccount, addrHash)
+	delete(ws.cachedContract, addrHash)
+	ws.deletedAccount[addrHash] = struct{}{}
+	return nil
+}
+
 // RootHash returns the hash of the root node of the accountTrie
 func (ws *workingSet) rootHash() hash.Hash32B {
 	return ws.accountTrie.RootHash()
@@ -184,6 +240,7 @@ func (ws *workingSet) RunActions(
 	executions []*action.Execution,
 	actions []action.Action) (hash.Hash32B, error) {
 	ws.blkHeight = blockHeight
+	ws.failedActions = make(map[hash.Hash32B]error)
 	// Recover cachedCandidates after restart factory
 	if blockHeight > 0 && len(ws.cachedCandidates) == 0 {
 		candidates, err := ws.getCandidates(blockHeight - 1)
@@ -194,13 +251,23 @@ func (ws *workingSet) RunActions(
 			return hash.ZeroHash32B, errors.Wrap(err, "failed to convert candidate list to map of cached Candidates")
 		}
 	}
-	if err := ws.handleTsf(tsf); err != nil {
+	fee := big.NewInt(0)
+	if err := ws.handleTsf(tsf, fee); err != nil {
 		return hash.ZeroHash32B, errors.Wrap(err, "failed to handle transfers")
 	}
-	if err := ws.handleVote(blockHeight, vote); err != nil {
+	if err := ws.handleVote(blockHeight, vote, fee); err != nil {
 		return hash.ZeroHash32B, errors.Wrap(err, "failed to handle votes")
 	}
+	if err := ws.creditGasFees(fee); err != nil {
+		return hash.ZeroHash32B, errors.Wrap(err, "failed to credit gas fees")
+	}
 
+	// remove deleted accounts from trie, an account could be created again after being deleted
+	for addr := range ws.deletedAccount {
+		if err := ws.accountTrie.Delete(addr[:]); err != nil && errors.Cause(err) != trie.ErrNotExist {
+			return hash.ZeroHash32B, errors.Wrapf(err, "failed to delete account %x from trie", addr)
+		}
+	}
 	// update pending state changes to trie
 	for addr, state := range ws.cachedAccount {
 		if err := ws.putState(addr[:], state); err != nil {
@@ -263,6 +330,22 @@ func (ws *workingSet) RunActions(
 	if err := ws.dao.Put(trie.AccountKVNameSpace, []byte(AccountTrieRootKey), rootHash[:]); err != nil {
 		return hash.ZeroHash32B, errors.Wrap(err, "failed to store accountTrie's root hash")
 	}
+	if err := ws.dao.Put(trie.AccountKVNameSpace, accountTrieRootKey(blockHeight), rootHash[:]); err != nil {
+		return hash.ZeroHash32B, errors.Wrapf(err, "failed to store accountTrie's root hash on height %d", blockHeight)
+	}
+	// the states of past heights are kept from the first height run with history
+	if ws.keepHistory {
+		switch _, err := ws.dao.Get(trie.AccountKVNameSpace, []byte(HistoryHeightKey)); errors.Cause(err) {
+		case nil:
+		case db.ErrNotExist, bolt.ErrBucketNotFound:
+			historyHeight := byteutil.Uint64ToBytes(blockHeight)
+			if err := ws.dao.Put(trie.AccountKVNameSpace, []byte(HistoryHeightKey), historyHeight); err != nil {
+				return hash.ZeroHash32B, errors.Wrap(err, "failed to store history height")
+			}
+		default:
+			return hash.ZeroHash32B, errors.Wrap(err, "failed to get history height")
+		}
+	}
 	// Persist new list of Candidates
 	candidates, err := MapToCandidates(ws.cachedCandidates)
 	if err != nil {
@@ -284,6 +367,19 @@ func (ws *workingSet) RunActions(
 	return ws.rootHash(), nil
 }
 
+// FailedActions returns the actions failed in the last RunActions() along with the errors. A failed action only
+// increases the nonce of its sender
+func (ws *workingSet) FailedActions() map[hash.Hash32B]error {
+	return ws.failedActions
+}
+
+// EnableGasFees makes RunActions() charge the intrinsic gas fees of transfers and votes from their senders right before
+// running them, and credit the fees to the producer. A sender who cannot afford the whole fee pays its balance, and its
+// action fails
+func (ws *workingSet) EnableGasFees(producer string) {
+	ws.feeRecipient = producer
+}
+
 // Commit persists all changes in RunActions() into the DB
 func (ws *workingSet) commit() error {
 	// commit all changes in a batch
@@ -335,6 +431,20 @@ func (ws *workingSet) SetCode(addr hash.PKHash, code []byte) error {
 	return nil
 }
 
+// DeleteCode removes contract's code
+func (ws *workingSet) DeleteCode(addr hash.PKHash) error {
+	if contract, ok := ws.cachedContract[addr]; ok {
+		contract.DeleteCode()
+		return nil
+	}
+	contract, err := ws.getContract(addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to DeleteCode for contract %x", addr)
+	}
+	contract.DeleteCode()
+	return nil
+}
+
 // GetContractState returns contract's storage value
 func (ws *workingSet) GetContractState(addr hash.PKHash, key hash.Hash32B) (hash.Hash32B, error) {
 	if contract, ok := ws.cachedContract[addr]; ok {
@@ -361,6 +471,30 @@ func (ws *workingSet) SetContractState(addr hash.PKHash, key, value hash.Hash32B
 	return contract.SetState(key, value[:])
 }
 
+// DeleteContractState deletes contract's storage value
+func (ws *workingSet) DeleteContractState(addr hash.PKHash, key hash.Hash32B) error {
+	if contract, ok := ws.cachedContract[addr]; ok {
+		return contract.DeleteState(key)
+	}
+	contract, err := ws.getContract(addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to DeleteContractState for contract %x", addr)
+	}
+	return contract.DeleteState(key)
+}
+
+// IterateContractState visits all entries in contract's storage in the order of key
+func (ws *workingSet) IterateContractState(addr hash.PKHash, visitor trie.Visitor) error {
+	if contract, ok := ws.cachedContract[addr]; ok {
+		return contract.IterateState(visitor)
+	}
+	contract, err := ws.getContract(addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to IterateContractState for contract %x", addr)
+	}
+	return contract.IterateState(visitor)
+}
+
 //======================================
 // private state/account functions
 //======================================
@@ -380,6 +514,9 @@ func (ws *workingSet) cachedState(hash hash.PKHash) (*State, error) {
 	if state, ok := ws.cachedAccount[hash]; ok {
 		return state, nil
 	}
+	if _, ok := ws.deletedAccount[hash]; ok {
+		return nil, errors.Wrapf(ErrAccountNotExist, "addrHash = %x", hash[:])
+	}
 	// add to local cache
 	state, err := ws.getState(hash)
 	if state != nil {
@@ -412,7 +549,7 @@ func (ws *workingSet) getContract(addr hash.PKHash) (Contract, error) {
 	if state.Root == hash.ZeroHash32B {
 		state.Root = trie.EmptyRoot
 	}
-	tr, err := trie.NewTrieSharedDB(ws.dao, trie.ContractKVNameSpace, state.Root)
+	tr, err := trie.NewTrieSharedDB(ws.dao, trie.ContractKVNameSpace, state.Root, ws.trieOptions...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create storage trie for new contract %x", addr)
 	}
@@ -427,9 +564,11 @@ func (ws *workingSet) clearCache() {
 	ws.savedAccount = nil
 	ws.cachedAccount = nil
 	ws.cachedContract = nil
+	ws.deletedAccount = nil
 	ws.savedAccount = make(map[string]*State)
 	ws.cachedAccount = make(map[hash.PKHash]*State)
 	ws.cachedContract = make(map[hash.PKHash]Contract)
+	ws.deletedAccount = make(map[hash.PKHash]struct{})
 }
 
 //======================================
@@ -453,25 +592,283 @@ func (ws *workingSet) getCandidates(height uint64) (CandidateList, error) {
 //======================================
 // private transfer/vote functions
 //======================================
-func (ws *workingSet) handleTsf(tsf []*action.Transfer) error {
+// tsfGroup is a group of transfers touching a set of accounts disjoint from the other groups, which is run
+// independently on its own cache of the account states
+type tsfGroup struct {
+	transfers []*action.Transfer
+	// confirmed states of the accounts before the transfers, nil if the account does not exist
+	states map[string]*State
+	// states being modified by the transfers, and the order in which they are loaded
+	cache  map[string]*State
+	loaded []string
+	failed map[hash.Hash32B]error
+	err    error
+	// whether the gas fees are charged, and the total fee charged by the group
+	gasFee bool
+	fee    *big.Int
+}
+
+// minParallelTsfs is the number of transfers from which they are run in parallel, below which starting the workers
+// costs more than running the transfers one by one
+const minParallelTsfs = 64
+
+// handleTsf runs the transfers in parallel. The transfers are grouped by the accounts they touch, i.e., the sender,
+// the recipient and their votees, so that the groups run concurrently on their own caches without conflicts. The
+// results are merged into the working set in order, which yields the same states as running the transfers one by one.
+// The gas fees charged are added to fee
+func (ws *workingSet) handleTsf(tsf []*action.Transfer, fee *big.Int) error {
+	workers := 1
+	if len(tsf) >= minParallelTsfs {
+		workers = runtime.NumCPU()
+	}
+	groups, err := ws.groupTsf(tsf, workers)
+	if err != nil {
+		return err
+	}
+	parallelize(len(groups), workers, func(i int) {
+		groups[i].run()
+	})
+	// merge the results into working set
+	for _, group := range groups {
+		if group.err != nil {
+			return group.err
+		}
+		for _, addr := range group.loaded {
+			state, err := ws.LoadOrCreateState(addr, 0)
+			if err != nil {
+				return errors.Wrapf(err, "failed to load or create the state of %s", addr)
+			}
+			// save state before modifying
+			ws.saveState(addr, state)
+			*state = *group.cache[addr]
+		}
+		for h, err := range group.failed {
+			ws.failedActions[h] = err
+		}
+		fee.Add(fee, group.fee)
+	}
+	return nil
+}
+
+// groupTsf splits the transfers into groups touching disjoint sets of accounts, in the order of their first transfers.
+// The states of the senders and recipients are read on the workers
+func (ws *workingSet) groupTsf(tsf []*action.Transfer, workers int) ([]*tsfGroup, error) {
+	var addrs []string
+	seen := make(map[string]bool)
+	prefetch := func(addr string) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
 	for _, tx := range tsf {
 		if tx.IsContract() {
 			continue
 		}
+		prefetch(tx.Recipient())
 		if !tx.IsCoinbase() {
-			// check sender
-			sender, err := ws.LoadOrCreateState(tx.Sender(), 0)
+			prefetch(tx.Sender())
+		}
+	}
+	prefetched := make([]*State, len(addrs))
+	errs := make([]error, len(addrs))
+	parallelize(len(addrs), workers, func(i int) {
+		prefetched[i], errs[i] = ws.peekState(addrs[i])
+	})
+	states := make(map[string]*State)
+	for i, addr := range addrs {
+		if errs[i] != nil {
+			return nil, errors.Wrapf(errs[i], "failed to get the state of %s", addr)
+		}
+		states[addr] = prefetched[i]
+	}
+
+	parent := make(map[string]string)
+	var find func(addr string) string
+	find = func(addr string) string {
+		if parent[addr] == addr {
+			return addr
+		}
+		parent[addr] = find(parent[addr])
+		return parent[addr]
+	}
+	union := func(a, b string) {
+		if ra, rb := find(a), find(b); ra != rb {
+			parent[rb] = ra
+		}
+	}
+	// node adds the account as a group of its own if it is not seen yet. Only the states of votees are not prefetched
+	node := func(addr string) error {
+		if _, ok := parent[addr]; ok {
+			return nil
+		}
+		if _, ok := states[addr]; !ok {
+			state, err := ws.peekState(addr)
 			if err != nil {
-				return errors.Wrapf(err, "failed to load or create the state of sender %s", tx.Sender())
+				return err
+			}
+			states[addr] = state
+		}
+		parent[addr] = addr
+		return nil
+	}
+	// add adds the account along with its votee into the same group, and returns the root of the group
+	add := func(addr string) (string, error) {
+		if err := node(addr); err != nil {
+			return "", err
+		}
+		if state := states[addr]; state != nil && len(state.Votee) > 0 && state.Votee != addr {
+			if err := node(state.Votee); err != nil {
+				return "", errors.Wrapf(err, "failed to get the state of votee %s", state.Votee)
+			}
+			union(addr, state.Votee)
+		}
+		return find(addr), nil
+	}
+
+	var txs []*action.Transfer
+	var roots []string
+	for _, tx := range tsf {
+		if tx.IsContract() {
+			continue
+		}
+		root, err := add(tx.Recipient())
+		if err != nil {
+			return nil, err
+		}
+		if !tx.IsCoinbase() {
+			sender, err := add(tx.Sender())
+			if err != nil {
+				return nil, err
+			}
+			union(root, sender)
+		}
+		txs = append(txs, tx)
+		roots = append(roots, root)
+	}
+	var groups []*tsfGroup
+	groupByRoot := make(map[string]*tsfGroup)
+	for i, tx := range txs {
+		root := find(roots[i])
+		group, ok := groupByRoot[root]
+		if !ok {
+			group = &tsfGroup{
+				states: states,
+				cache:  make(map[string]*State),
+				failed: make(map[hash.Hash32B]error),
+				gasFee: ws.feeRecipient != "",
+				fee:    big.NewInt(0),
+			}
+			groupByRoot[root] = group
+			groups = append(groups, group)
+		}
+		group.transfers = append(group.transfers, tx)
+	}
+	return groups, nil
+}
+
+// parallelize calls f with 0 to n-1 on the workers, and returns once all the calls complete
+func parallelize(n int, workers int, f func(i int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+	queue := make(chan int, n)
+	for i := 0; i < n; i++ {
+		queue <- i
+	}
+	close(queue)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				f(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// peekState returns the state of the account in working set without caching it, or nil if the account does not exist
+func (ws *workingSet) peekState(addr string) (*State, error) {
+	h, err := iotxaddress.GetPubkeyHash(addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when getting the pubkey hash")
+	}
+	addrHash := byteutil.BytesTo20B(h)
+	if state, ok := ws.cachedAccount[addrHash]; ok {
+		return state, nil
+	}
+	if _, ok := ws.deletedAccount[addrHash]; ok {
+		return nil, nil
+	}
+	state, err := ws.getState(addrHash)
+	if errors.Cause(err) == ErrAccountNotExist {
+		return nil, nil
+	}
+	return state, err
+}
+
+// loadOrCreateState loads the state of the account into the cache of the group, or creates an empty one
+func (g *tsfGroup) loadOrCreateState(addr string) *State {
+	if state, ok := g.cache[addr]; ok {
+		return state
+	}
+	state := &State{
+		Balance:      big.NewInt(0),
+		VotingWeight: big.NewInt(0),
+	}
+	if confirmed := g.states[addr]; confirmed != nil {
+		state = confirmed.clone()
+		state.Voters = confirmed.Voters
+	}
+	g.cache[addr] = state
+	g.loaded = append(g.loaded, addr)
+	return state
+}
+
+// run runs the transfers of the group on its cache, and stops at the first error
+func (g *tsfGroup) run() {
+	for _, tx := range g.transfers {
+		if !tx.IsCoinbase() {
+			// check sender
+			sender := g.loadOrCreateState(tx.Sender())
+			if g.gasFee {
+				enough, err := g.chargeGasFee(tx, sender)
+				if err != nil {
+					g.err = err
+					return
+				}
+				if !enough {
+					if tx.Nonce() > sender.Nonce {
+						sender.Nonce = tx.Nonce()
+					}
+					continue
+				}
 			}
-			// save state before modifying
-			ws.saveState(tx.Sender(), sender)
 			if tx.Amount().Cmp(sender.Balance) == 1 {
-				return errors.Wrapf(ErrNotEnoughBalance, "failed to verify the balance of sender %s", tx.Sender())
+				// the transfer fails without aborting the block, and only consumes the nonce
+				g.failed[tx.Hash()] = errors.Wrapf(
+					ErrNotEnoughBalance,
+					"failed to verify the balance of sender %s",
+					tx.Sender(),
+				)
+				if tx.Nonce() > sender.Nonce {
+					sender.Nonce = tx.Nonce()
+				}
+				continue
 			}
 			// update sender balance
 			if err := sender.SubBalance(tx.Amount()); err != nil {
-				return errors.Wrapf(err, "failed to update the balance of sender %s", tx.Sender())
+				g.err = errors.Wrapf(err, "failed to update the balance of sender %s", tx.Sender())
+				return
 			}
 			// update sender Nonce
 			if tx.Nonce() > sender.Nonce {
@@ -480,98 +877,191 @@ func (ws *workingSet) handleTsf(tsf []*action.Transfer) error {
 			// Update sender votes
 			if len(sender.Votee) > 0 && sender.Votee != tx.Sender() {
 				// sender already voted to a different person
-				voteeOfSender, err := ws.LoadOrCreateState(sender.Votee, 0)
-				if err != nil {
-					return errors.Wrapf(err, "failed to load or create the state of sender's votee %s", sender.Votee)
-				}
-				// save state before modifying
-				ws.saveState(sender.Votee, voteeOfSender)
+				voteeOfSender := g.loadOrCreateState(sender.Votee)
 				voteeOfSender.VotingWeight.Sub(voteeOfSender.VotingWeight, tx.Amount())
 			}
 		}
 		// check recipient
-		recipient, err := ws.LoadOrCreateState(tx.Recipient(), 0)
-		if err != nil {
-			return errors.Wrapf(err, "failed to laod or create the state of recipient %s", tx.Recipient())
-		}
-		// save state before modifying
-		ws.saveState(tx.Recipient(), recipient)
+		recipient := g.loadOrCreateState(tx.Recipient())
 		// update recipient balance
 		if err := recipient.AddBalance(tx.Amount()); err != nil {
-			return errors.Wrapf(err, "failed to update the balance of recipient %s", tx.Recipient())
+			g.err = errors.Wrapf(err, "failed to update the balance of recipient %s", tx.Recipient())
+			return
 		}
 		// Update recipient votes
 		if len(recipient.Votee) > 0 && recipient.Votee != tx.Recipient() {
 			// recipient already voted to a different person
-			voteeOfRecipient, err := ws.LoadOrCreateState(recipient.Votee, 0)
-			if err != nil {
-				return errors.Wrapf(err, "failed to load or create the state of recipient's votee %s", recipient.Votee)
-			}
-			// save state before modifying
-			ws.saveState(recipient.Votee, voteeOfRecipient)
+			voteeOfRecipient := g.loadOrCreateState(recipient.Votee)
 			voteeOfRecipient.VotingWeight.Add(voteeOfRecipient.VotingWeight, tx.Amount())
 		}
 	}
-	return nil
 }
 
-func (ws *workingSet) handleVote(blockHeight uint64, vote []*action.Vote) error {
-	for _, v := range vote {
-		voteFrom, err := ws.LoadOrCreateState(v.Voter(), 0)
+// chargeGasFee charges the gas fee of the transfer from the sender right before the transfer runs. The fee is capped at
+// the balance of sender, in which case the transfer fails
+func (g *tsfGroup) chargeGasFee(tx *action.Transfer, sender *State) (bool, error) {
+	fee, err := intrinsicGasFee(tx)
+	if err != nil {
+		return false, err
+	}
+	enough := fee.Cmp(sender.Balance) <= 0
+	if !enough {
+		fee.Set(sender.Balance)
+		h := tx.Hash()
+		g.failed[h] = errors.Wrapf(ErrNotEnoughBalance, "failed to pay the gas fee of transfer %x", h)
+	}
+	sender.Balance.Sub(sender.Balance, fee)
+	if len(sender.Votee) > 0 && sender.Votee != tx.Sender() {
+		votee := g.loadOrCreateState(sender.Votee)
+		votee.VotingWeight.Sub(votee.VotingWeight, fee)
+	}
+	g.fee.Add(g.fee, fee)
+	return enough, nil
+}
+
+// chargeGasFee charges the gas fee of the action from the sender right before the action runs, and adds it to fee. The
+// fee is capped at the balance of sender, in which case the action fails
+func (ws *workingSet) chargeGasFee(act intrinsicGasAction, addr string, sender *State, fee *big.Int) (bool, error) {
+	actFee, err := intrinsicGasFee(act)
+	if err != nil {
+		return false, err
+	}
+	enough := actFee.Cmp(sender.Balance) <= 0
+	if !enough {
+		actFee.Set(sender.Balance)
+		h := act.Hash()
+		ws.failedActions[h] = errors.Wrapf(ErrNotEnoughBalance, "failed to pay the gas fee of action %x", h)
+	}
+	if len(sender.Votee) > 0 && sender.Votee != addr {
+		votee, err := ws.LoadOrCreateState(sender.Votee, 0)
 		if err != nil {
-			return errors.Wrapf(err, "failed to load or create the state of voter %s", v.Voter())
+			return false, errors.Wrapf(err, "failed to load or create the state of votee %s", sender.Votee)
 		}
 		// save state before modifying
-		ws.saveState(v.Voter(), voteFrom)
-		// update voteFrom Nonce
-		if v.Nonce() > voteFrom.Nonce {
-			voteFrom.Nonce = v.Nonce()
-		}
-		// Update old votee's weight
-		if len(voteFrom.Votee) > 0 && voteFrom.Votee != v.Voter() {
-			// voter already voted
-			oldVotee, err := ws.LoadOrCreateState(voteFrom.Votee, 0)
-			if err != nil {
-				return errors.Wrapf(err, "failed to load or create the state of voter's old votee %s", voteFrom.Votee)
-			}
-			// save state before modifying
-			ws.saveState(voteFrom.Votee, oldVotee)
-			oldVotee.VotingWeight.Sub(oldVotee.VotingWeight, voteFrom.Balance)
-			voteFrom.Votee = ""
+		ws.saveState(sender.Votee, votee)
+		votee.VotingWeight.Sub(votee.VotingWeight, actFee)
+	}
+	sender.Balance.Sub(sender.Balance, actFee)
+	fee.Add(fee, actFee)
+	return enough, nil
+}
+
+// creditGasFees credits the gas fees charged in RunActions() to the producer, and to the voting weight of its votee
+func (ws *workingSet) creditGasFees(fee *big.Int) error {
+	if ws.feeRecipient == "" || fee.Sign() == 0 {
+		return nil
+	}
+	producer, err := ws.LoadOrCreateState(ws.feeRecipient, 0)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load or create the state of producer %s", ws.feeRecipient)
+	}
+	// save state before modifying
+	ws.saveState(ws.feeRecipient, producer)
+	producer.Balance.Add(producer.Balance, fee)
+	if len(producer.Votee) > 0 && producer.Votee != ws.feeRecipient {
+		votee, err := ws.LoadOrCreateState(producer.Votee, 0)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load or create the state of votee %s", producer.Votee)
 		}
+		// save state before modifying
+		ws.saveState(producer.Votee, votee)
+		votee.VotingWeight.Add(votee.VotingWeight, fee)
+	}
+	return nil
+}
 
-		if v.Votee() == "" {
-			// unvote operation
-			voteFrom.IsCandidate = false
-			continue
+// intrinsicGasFee returns the fee of the intrinsic gas of the action
+func intrinsicGasFee(act intrinsicGasAction) (*big.Int, error) {
+	gas, err := act.IntrinsicGas()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get intrinsic gas of action %x", act.Hash())
+	}
+	return new(big.Int).Mul(act.GasPrice(), new(big.Int).SetUint64(gas)), nil
+}
+
+// handleVote runs the votes one by one. A vote failing to run does not abort the block, but is recorded in the failed
+// actions instead, with only its nonce and gas fee consumed
+func (ws *workingSet) handleVote(blockHeight uint64, vote []*action.Vote, fee *big.Int) error {
+	for _, v := range vote {
+		if err := ws.runVote(blockHeight, v, fee); err != nil {
+			ws.failedActions[v.Hash()] = err
 		}
+	}
+	return nil
+}
 
-		voteTo, err := ws.LoadOrCreateState(v.Votee(), 0)
+// runVote runs the vote. The states touched by the vote are loaded before changing any of them, so that a failed vote
+// leaves the voting weights unchanged
+func (ws *workingSet) runVote(blockHeight uint64, v *action.Vote, fee *big.Int) error {
+	voteFrom, err := ws.LoadOrCreateState(v.Voter(), 0)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load or create the state of voter %s", v.Voter())
+	}
+	// save state before modifying
+	ws.saveState(v.Voter(), voteFrom)
+	// update voteFrom Nonce
+	if v.Nonce() > voteFrom.Nonce {
+		voteFrom.Nonce = v.Nonce()
+	}
+	if ws.feeRecipient != "" {
+		enough, err := ws.chargeGasFee(v, v.Voter(), voteFrom, fee)
 		if err != nil {
+			return err
+		}
+		if !enough {
+			return nil
+		}
+	}
+	var oldVotee, voteTo *State
+	if len(voteFrom.Votee) > 0 && voteFrom.Votee != v.Voter() {
+		// voter already voted
+		if oldVotee, err = ws.LoadOrCreateState(voteFrom.Votee, 0); err != nil {
+			return errors.Wrapf(err, "failed to load or create the state of voter's old votee %s", voteFrom.Votee)
+		}
+	}
+	if v.Votee() != "" {
+		if voteTo, err = ws.LoadOrCreateState(v.Votee(), 0); err != nil {
 			return errors.Wrapf(err, "failed to load or create the state of votee %s", v.Votee())
 		}
+	}
+	var pkHash []byte
+	if v.Voter() == v.Votee() {
+		if pkHash, err = iotxaddress.GetPubkeyHash(v.Voter()); err != nil {
+			return errors.Wrap(err, "cannot get the hash of the address")
+		}
+	}
+
+	// Update old votee's weight
+	if oldVotee != nil {
 		// save state before modifying
-		ws.saveState(v.Votee(), voteTo)
-		if v.Voter() != v.Votee() {
-			// Voter votes to a different person
-			voteTo.VotingWeight.Add(voteTo.VotingWeight, voteFrom.Balance)
-			voteFrom.Votee = v.Votee()
-		} else {
-			// Vote to self: self-nomination or cancel the previous vote case
-			voteFrom.Votee = v.Voter()
-			voteFrom.IsCandidate = true
-			pkHash, err := iotxaddress.GetPubkeyHash(v.Voter())
-			if err != nil {
-				return errors.Wrap(err, "cannot get the hash of the address")
-			}
-			pkHashAddress := byteutil.BytesTo20B(pkHash)
-			votePubkey := v.VoterPublicKey()
-			if _, ok := ws.cachedCandidates[pkHashAddress]; !ok {
-				ws.cachedCandidates[pkHashAddress] = &Candidate{
-					Address:        v.Voter(),
-					PublicKey:      votePubkey,
-					CreationHeight: blockHeight,
-				}
+		ws.saveState(voteFrom.Votee, oldVotee)
+		oldVotee.VotingWeight.Sub(oldVotee.VotingWeight, voteFrom.Balance)
+		voteFrom.Votee = ""
+	}
+
+	if voteTo == nil {
+		// unvote operation
+		voteFrom.IsCandidate = false
+		return nil
+	}
+
+	// save state before modifying
+	ws.saveState(v.Votee(), voteTo)
+	if v.Voter() != v.Votee() {
+		// Voter votes to a different person
+		voteTo.VotingWeight.Add(voteTo.VotingWeight, voteFrom.Balance)
+		voteFrom.Votee = v.Votee()
+	} else {
+		// Vote to self: self-nomination or cancel the previous vote case
+		voteFrom.Votee = v.Voter()
+		voteFrom.IsCandidate = true
+		pkHashAddress := byteutil.BytesTo20B(pkHash)
+		votePubkey := v.VoterPublicKey()
+		if _, ok := ws.cachedCandidates[pkHashAddress]; !ok {
+			ws.cachedCandidates[pkHashAddress] = &Candidate{
+				Address:        v.Voter(),
+				PublicKey:      votePubkey,
+				CreationHeight: blockHeight,
 			}
 		}
 	}
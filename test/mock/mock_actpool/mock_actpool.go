@@ -5,6 +5,7 @@
 package mock_actpool
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,30 @@ func (m *MockActPool) EXPECT() *MockActPoolMockRecorder {
 	return m.recorder
 }
 
+// Start mocks base method
+func (m *MockActPool) Start(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "Start", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start
+func (mr *MockActPoolMockRecorder) Start(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockActPool)(nil).Start), arg0)
+}
+
+// Stop mocks base method
+func (m *MockActPool) Stop(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "Stop", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop
+func (mr *MockActPoolMockRecorder) Stop(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockActPool)(nil).Stop), arg0)
+}
+
 // Reset mocks base method
 func (m *MockActPool) Reset() {
 	m.ctrl.Call(m, "Reset")
@@ -170,6 +195,28 @@ func (mr *MockActPoolMockRecorder) GetCapacity() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapacity", reflect.TypeOf((*MockActPool)(nil).GetCapacity))
 }
 
+// MarkLocal mocks base method
+func (m *MockActPool) MarkLocal(hash hash.Hash32B) {
+	m.ctrl.Call(m, "MarkLocal", hash)
+}
+
+// MarkLocal indicates an expected call of MarkLocal
+func (mr *MockActPoolMockRecorder) MarkLocal(hash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLocal", reflect.TypeOf((*MockActPool)(nil).MarkLocal), hash)
+}
+
+// LocalActsToRebroadcast mocks base method
+func (m *MockActPool) LocalActsToRebroadcast() []action.Action {
+	ret := m.ctrl.Call(m, "LocalActsToRebroadcast")
+	ret0, _ := ret[0].([]action.Action)
+	return ret0
+}
+
+// LocalActsToRebroadcast indicates an expected call of LocalActsToRebroadcast
+func (mr *MockActPoolMockRecorder) LocalActsToRebroadcast() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LocalActsToRebroadcast", reflect.TypeOf((*MockActPool)(nil).LocalActsToRebroadcast))
+}
+
 // MockActionValidator is a mock of ActionValidator interface
 type MockActionValidator struct {
 	ctrl     *gomock.Controller
@@ -233,6 +233,20 @@ func (mr *MockBlockchainMockRecorder) GetTransfersToAddress(address interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfersToAddress", reflect.TypeOf((*MockBlockchain)(nil).GetTransfersToAddress), address)
 }
 
+// GetTransfersByAddress mocks base method
+func (m *MockBlockchain) GetTransfersByAddress(arg0 string, arg1 *blockchain.HistoryQuery) ([]hash.Hash32B, string, error) {
+	ret := m.ctrl.Call(m, "GetTransfersByAddress", arg0, arg1)
+	ret0, _ := ret[0].([]hash.Hash32B)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTransfersByAddress indicates an expected call of GetTransfersByAddress
+func (mr *MockBlockchainMockRecorder) GetTransfersByAddress(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfersByAddress", reflect.TypeOf((*MockBlockchain)(nil).GetTransfersByAddress), arg0, arg1)
+}
+
 // GetTransferByTransferHash mocks base method
 func (m *MockBlockchain) GetTransferByTransferHash(h hash.Hash32B) (*action.Transfer, error) {
 	ret := m.ctrl.Call(m, "GetTransferByTransferHash", h)
@@ -285,6 +299,20 @@ func (mr *MockBlockchainMockRecorder) GetVotesToAddress(address interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVotesToAddress", reflect.TypeOf((*MockBlockchain)(nil).GetVotesToAddress), address)
 }
 
+// GetVotesByAddress mocks base method
+func (m *MockBlockchain) GetVotesByAddress(arg0 string, arg1 *blockchain.HistoryQuery) ([]hash.Hash32B, string, error) {
+	ret := m.ctrl.Call(m, "GetVotesByAddress", arg0, arg1)
+	ret0, _ := ret[0].([]hash.Hash32B)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVotesByAddress indicates an expected call of GetVotesByAddress
+func (mr *MockBlockchainMockRecorder) GetVotesByAddress(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVotesByAddress", reflect.TypeOf((*MockBlockchain)(nil).GetVotesByAddress), arg0, arg1)
+}
+
 // GetVoteByVoteHash mocks base method
 func (m *MockBlockchain) GetVoteByVoteHash(h hash.Hash32B) (*action.Vote, error) {
 	ret := m.ctrl.Call(m, "GetVoteByVoteHash", h)
@@ -337,6 +365,20 @@ func (mr *MockBlockchainMockRecorder) GetExecutionsToAddress(address interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutionsToAddress", reflect.TypeOf((*MockBlockchain)(nil).GetExecutionsToAddress), address)
 }
 
+// GetExecutionsByAddress mocks base method
+func (m *MockBlockchain) GetExecutionsByAddress(arg0 string, arg1 *blockchain.HistoryQuery) ([]hash.Hash32B, string, error) {
+	ret := m.ctrl.Call(m, "GetExecutionsByAddress", arg0, arg1)
+	ret0, _ := ret[0].([]hash.Hash32B)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetExecutionsByAddress indicates an expected call of GetExecutionsByAddress
+func (mr *MockBlockchainMockRecorder) GetExecutionsByAddress(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutionsByAddress", reflect.TypeOf((*MockBlockchain)(nil).GetExecutionsByAddress), arg0, arg1)
+}
+
 // GetExecutionByExecutionHash mocks base method
 func (m *MockBlockchain) GetExecutionByExecutionHash(h hash.Hash32B) (*action.Execution, error) {
 	ret := m.ctrl.Call(m, "GetExecutionByExecutionHash", h)
@@ -376,6 +418,98 @@ func (mr *MockBlockchainMockRecorder) GetReceiptByExecutionHash(h interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiptByExecutionHash", reflect.TypeOf((*MockBlockchain)(nil).GetReceiptByExecutionHash), h)
 }
 
+// GetReceiptByActionHash mocks base method
+func (m *MockBlockchain) GetReceiptByActionHash(h hash.Hash32B) (*blockchain.Receipt, error) {
+	ret := m.ctrl.Call(m, "GetReceiptByActionHash", h)
+	ret0, _ := ret[0].(*blockchain.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceiptByActionHash indicates an expected call of GetReceiptByActionHash
+func (mr *MockBlockchainMockRecorder) GetReceiptByActionHash(h interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiptByActionHash", reflect.TypeOf((*MockBlockchain)(nil).GetReceiptByActionHash), h)
+}
+
+// GetActionByHash mocks base method
+func (m *MockBlockchain) GetActionByHash(arg0 hash.Hash32B) (action.Action, error) {
+	ret := m.ctrl.Call(m, "GetActionByHash", arg0)
+	ret0, _ := ret[0].(action.Action)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActionByHash indicates an expected call of GetActionByHash
+func (mr *MockBlockchainMockRecorder) GetActionByHash(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionByHash", reflect.TypeOf((*MockBlockchain)(nil).GetActionByHash), arg0)
+}
+
+// GetBlockHashByActionHash mocks base method
+func (m *MockBlockchain) GetBlockHashByActionHash(arg0 hash.Hash32B) (hash.Hash32B, error) {
+	ret := m.ctrl.Call(m, "GetBlockHashByActionHash", arg0)
+	ret0, _ := ret[0].(hash.Hash32B)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockHashByActionHash indicates an expected call of GetBlockHashByActionHash
+func (mr *MockBlockchainMockRecorder) GetBlockHashByActionHash(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHashByActionHash", reflect.TypeOf((*MockBlockchain)(nil).GetBlockHashByActionHash), arg0)
+}
+
+// GetActionsByAddress mocks base method
+func (m *MockBlockchain) GetActionsByAddress(arg0 string, arg1 *blockchain.HistoryQuery) ([]hash.Hash32B, string, error) {
+	ret := m.ctrl.Call(m, "GetActionsByAddress", arg0, arg1)
+	ret0, _ := ret[0].([]hash.Hash32B)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetActionsByAddress indicates an expected call of GetActionsByAddress
+func (mr *MockBlockchainMockRecorder) GetActionsByAddress(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionsByAddress", reflect.TypeOf((*MockBlockchain)(nil).GetActionsByAddress), arg0, arg1)
+}
+
+// GetActionProof mocks base method
+func (m *MockBlockchain) GetActionProof(h hash.Hash32B) (*blockchain.ActionProof, error) {
+	ret := m.ctrl.Call(m, "GetActionProof", h)
+	ret0, _ := ret[0].(*blockchain.ActionProof)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActionProof indicates an expected call of GetActionProof
+func (mr *MockBlockchainMockRecorder) GetActionProof(h interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionProof", reflect.TypeOf((*MockBlockchain)(nil).GetActionProof), h)
+}
+
+// GetReceiptProof mocks base method
+func (m *MockBlockchain) GetReceiptProof(arg0 hash.Hash32B) (*blockchain.ReceiptProof, error) {
+	ret := m.ctrl.Call(m, "GetReceiptProof", arg0)
+	ret0, _ := ret[0].(*blockchain.ReceiptProof)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceiptProof indicates an expected call of GetReceiptProof
+func (mr *MockBlockchainMockRecorder) GetReceiptProof(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiptProof", reflect.TypeOf((*MockBlockchain)(nil).GetReceiptProof), arg0)
+}
+
+// GetLogs mocks base method
+func (m *MockBlockchain) GetLogs(arg0 *blockchain.LogFilter) ([]*blockchain.Log, error) {
+	ret := m.ctrl.Call(m, "GetLogs", arg0)
+	ret0, _ := ret[0].([]*blockchain.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogs indicates an expected call of GetLogs
+func (mr *MockBlockchainMockRecorder) GetLogs(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockBlockchain)(nil).GetLogs), arg0)
+}
+
 // GetFactory mocks base method
 func (m *MockBlockchain) GetFactory() state.Factory {
 	ret := m.ctrl.Call(m, "GetFactory")
@@ -437,6 +571,32 @@ func (mr *MockBlockchainMockRecorder) StateByAddr(address interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateByAddr", reflect.TypeOf((*MockBlockchain)(nil).StateByAddr), address)
 }
 
+// StateAtHeight mocks base method
+func (m *MockBlockchain) StateAtHeight(address string, height uint64) (*state.State, error) {
+	ret := m.ctrl.Call(m, "StateAtHeight", address, height)
+	ret0, _ := ret[0].(*state.State)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateAtHeight indicates an expected call of StateAtHeight
+func (mr *MockBlockchainMockRecorder) StateAtHeight(address, height interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateAtHeight", reflect.TypeOf((*MockBlockchain)(nil).StateAtHeight), address, height)
+}
+
+// BalanceAtHeight mocks base method
+func (m *MockBlockchain) BalanceAtHeight(address string, height uint64) (*big.Int, error) {
+	ret := m.ctrl.Call(m, "BalanceAtHeight", address, height)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceAtHeight indicates an expected call of BalanceAtHeight
+func (mr *MockBlockchainMockRecorder) BalanceAtHeight(address, height interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAtHeight", reflect.TypeOf((*MockBlockchain)(nil).BalanceAtHeight), address, height)
+}
+
 // MintNewBlock mocks base method
 func (m *MockBlockchain) MintNewBlock(tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution, actions []action.Action, address *iotxaddress.Address, data string) (*blockchain.Block, error) {
 	ret := m.ctrl.Call(m, "MintNewBlock", tsf, vote, executions, actions, address, data)
@@ -512,6 +672,18 @@ func (mr *MockBlockchainMockRecorder) ValidateBlock(blk, containCoinbase interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateBlock", reflect.TypeOf((*MockBlockchain)(nil).ValidateBlock), blk, containCoinbase)
 }
 
+// RollbackTo mocks base method
+func (m *MockBlockchain) RollbackTo(height uint64) error {
+	ret := m.ctrl.Call(m, "RollbackTo", height)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackTo indicates an expected call of RollbackTo
+func (mr *MockBlockchainMockRecorder) RollbackTo(height interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackTo", reflect.TypeOf((*MockBlockchain)(nil).RollbackTo), height)
+}
+
 // Validator mocks base method
 func (m *MockBlockchain) Validator() blockchain.Validator {
 	ret := m.ctrl.Call(m, "Validator")
@@ -547,6 +719,32 @@ func (mr *MockBlockchainMockRecorder) ExecuteContractRead(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteContractRead", reflect.TypeOf((*MockBlockchain)(nil).ExecuteContractRead), arg0)
 }
 
+// EstimateGas mocks base method
+func (m *MockBlockchain) EstimateGas(arg0 *action.Execution) (uint64, error) {
+	ret := m.ctrl.Call(m, "EstimateGas", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateGas indicates an expected call of EstimateGas
+func (mr *MockBlockchainMockRecorder) EstimateGas(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateGas", reflect.TypeOf((*MockBlockchain)(nil).EstimateGas), arg0)
+}
+
+// TraceExecution mocks base method
+func (m *MockBlockchain) TraceExecution(arg0 hash.Hash32B) (*blockchain.ExecutionTrace, error) {
+	ret := m.ctrl.Call(m, "TraceExecution", arg0)
+	ret0, _ := ret[0].(*blockchain.ExecutionTrace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceExecution indicates an expected call of TraceExecution
+func (mr *MockBlockchainMockRecorder) TraceExecution(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceExecution", reflect.TypeOf((*MockBlockchain)(nil).TraceExecution), arg0)
+}
+
 // SubscribeBlockCreation mocks base method
 func (m *MockBlockchain) SubscribeBlockCreation(ch chan *blockchain.Block) error {
 	ret := m.ctrl.Call(m, "SubscribeBlockCreation", ch)
@@ -6,6 +6,7 @@ package mock_state
 
 import (
 	context "context"
+	io "io"
 	big "math/big"
 	reflect "reflect"
 
@@ -114,6 +115,19 @@ func (mr *MockFactoryMockRecorder) State(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockFactory)(nil).State), arg0)
 }
 
+// StateAtHeight mocks base method
+func (m *MockFactory) StateAtHeight(arg0 string, arg1 uint64) (*state.State, error) {
+	ret := m.ctrl.Call(m, "StateAtHeight", arg0, arg1)
+	ret0, _ := ret[0].(*state.State)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateAtHeight indicates an expected call of StateAtHeight
+func (mr *MockFactoryMockRecorder) StateAtHeight(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateAtHeight", reflect.TypeOf((*MockFactory)(nil).StateAtHeight), arg0, arg1)
+}
+
 // CachedState mocks base method
 func (m *MockFactory) CachedState(arg0 string) (*state.State, error) {
 	ret := m.ctrl.Call(m, "CachedState", arg0)
@@ -127,6 +141,20 @@ func (mr *MockFactoryMockRecorder) CachedState(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CachedState", reflect.TypeOf((*MockFactory)(nil).CachedState), arg0)
 }
 
+// StateProof mocks base method
+func (m *MockFactory) StateProof(arg0 string, arg1 uint64) (hash.Hash32B, [][]byte, error) {
+	ret := m.ctrl.Call(m, "StateProof", arg0, arg1)
+	ret0, _ := ret[0].(hash.Hash32B)
+	ret1, _ := ret[1].([][]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// StateProof indicates an expected call of StateProof
+func (mr *MockFactoryMockRecorder) StateProof(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateProof", reflect.TypeOf((*MockFactory)(nil).StateProof), arg0, arg1)
+}
+
 // RootHash mocks base method
 func (m *MockFactory) RootHash() hash.Hash32B {
 	ret := m.ctrl.Call(m, "RootHash")
@@ -165,6 +193,19 @@ func (mr *MockFactoryMockRecorder) NewWorkingSet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewWorkingSet", reflect.TypeOf((*MockFactory)(nil).NewWorkingSet))
 }
 
+// NewWorkingSetAtHeight mocks base method
+func (m *MockFactory) NewWorkingSetAtHeight(arg0 uint64) (state.WorkingSet, error) {
+	ret := m.ctrl.Call(m, "NewWorkingSetAtHeight", arg0)
+	ret0, _ := ret[0].(state.WorkingSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewWorkingSetAtHeight indicates an expected call of NewWorkingSetAtHeight
+func (mr *MockFactoryMockRecorder) NewWorkingSetAtHeight(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewWorkingSetAtHeight", reflect.TypeOf((*MockFactory)(nil).NewWorkingSetAtHeight), arg0)
+}
+
 // RunActions mocks base method
 func (m *MockFactory) RunActions(arg0 uint64, arg1 []*action.Transfer, arg2 []*action.Vote, arg3 []*action.Execution, arg4 []action.Action) (hash.Hash32B, error) {
 	ret := m.ctrl.Call(m, "RunActions", arg0, arg1, arg2, arg3, arg4)
@@ -190,6 +231,42 @@ func (mr *MockFactoryMockRecorder) Commit(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockFactory)(nil).Commit), arg0)
 }
 
+// RollbackTo mocks base method
+func (m *MockFactory) RollbackTo(arg0 uint64) error {
+	ret := m.ctrl.Call(m, "RollbackTo", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackTo indicates an expected call of RollbackTo
+func (mr *MockFactoryMockRecorder) RollbackTo(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackTo", reflect.TypeOf((*MockFactory)(nil).RollbackTo), arg0)
+}
+
+// ExportSnapshot mocks base method
+func (m *MockFactory) ExportSnapshot(arg0 io.Writer, arg1 uint64) error {
+	ret := m.ctrl.Call(m, "ExportSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportSnapshot indicates an expected call of ExportSnapshot
+func (mr *MockFactoryMockRecorder) ExportSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportSnapshot", reflect.TypeOf((*MockFactory)(nil).ExportSnapshot), arg0, arg1)
+}
+
+// ImportSnapshot mocks base method
+func (m *MockFactory) ImportSnapshot(arg0 io.Reader, arg1 uint64, arg2 hash.Hash32B) error {
+	ret := m.ctrl.Call(m, "ImportSnapshot", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportSnapshot indicates an expected call of ImportSnapshot
+func (mr *MockFactoryMockRecorder) ImportSnapshot(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSnapshot", reflect.TypeOf((*MockFactory)(nil).ImportSnapshot), arg0, arg1, arg2)
+}
+
 // GetCodeHash mocks base method
 func (m *MockFactory) GetCodeHash(arg0 hash.PKHash) (hash.Hash32B, error) {
 	ret := m.ctrl.Call(m, "GetCodeHash", arg0)
@@ -9,6 +9,7 @@ import (
 	action "github.com/iotexproject/iotex-core/action"
 	hash "github.com/iotexproject/iotex-core/pkg/hash"
 	state "github.com/iotexproject/iotex-core/state"
+	trie "github.com/iotexproject/iotex-core/trie"
 	reflect "reflect"
 )
 
@@ -74,6 +75,30 @@ func (mr *MockWorkingSetMockRecorder) CachedState(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CachedState", reflect.TypeOf((*MockWorkingSet)(nil).CachedState), arg0)
 }
 
+// DeleteCachedState mocks base method
+func (m *MockWorkingSet) DeleteCachedState(arg0 string) error {
+	ret := m.ctrl.Call(m, "DeleteCachedState", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCachedState indicates an expected call of DeleteCachedState
+func (mr *MockWorkingSetMockRecorder) DeleteCachedState(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCachedState", reflect.TypeOf((*MockWorkingSet)(nil).DeleteCachedState), arg0)
+}
+
+// DeleteAccount mocks base method
+func (m *MockWorkingSet) DeleteAccount(arg0 string) error {
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount
+func (mr *MockWorkingSetMockRecorder) DeleteAccount(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockWorkingSet)(nil).DeleteAccount), arg0)
+}
+
 // RunActions mocks base method
 func (m *MockWorkingSet) RunActions(arg0 uint64, arg1 []*action.Transfer, arg2 []*action.Vote, arg3 []*action.Execution, arg4 []action.Action) (hash.Hash32B, error) {
 	ret := m.ctrl.Call(m, "RunActions", arg0, arg1, arg2, arg3, arg4)
@@ -87,6 +112,28 @@ func (mr *MockWorkingSetMockRecorder) RunActions(arg0, arg1, arg2, arg3, arg4 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunActions", reflect.TypeOf((*MockWorkingSet)(nil).RunActions), arg0, arg1, arg2, arg3, arg4)
 }
 
+// FailedActions mocks base method
+func (m *MockWorkingSet) FailedActions() map[hash.Hash32B]error {
+	ret := m.ctrl.Call(m, "FailedActions")
+	ret0, _ := ret[0].(map[hash.Hash32B]error)
+	return ret0
+}
+
+// FailedActions indicates an expected call of FailedActions
+func (mr *MockWorkingSetMockRecorder) FailedActions() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailedActions", reflect.TypeOf((*MockWorkingSet)(nil).FailedActions))
+}
+
+// EnableGasFees mocks base method
+func (m *MockWorkingSet) EnableGasFees(arg0 string) {
+	m.ctrl.Call(m, "EnableGasFees", arg0)
+}
+
+// EnableGasFees indicates an expected call of EnableGasFees
+func (mr *MockWorkingSetMockRecorder) EnableGasFees(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableGasFees", reflect.TypeOf((*MockWorkingSet)(nil).EnableGasFees), arg0)
+}
+
 // commit mocks base method
 func (m *MockWorkingSet) commit() error {
 	ret := m.ctrl.Call(m, "commit")
@@ -137,6 +184,18 @@ func (mr *MockWorkingSetMockRecorder) SetCode(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCode", reflect.TypeOf((*MockWorkingSet)(nil).SetCode), arg0, arg1)
 }
 
+// DeleteCode mocks base method
+func (m *MockWorkingSet) DeleteCode(arg0 hash.PKHash) error {
+	ret := m.ctrl.Call(m, "DeleteCode", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCode indicates an expected call of DeleteCode
+func (mr *MockWorkingSetMockRecorder) DeleteCode(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCode", reflect.TypeOf((*MockWorkingSet)(nil).DeleteCode), arg0)
+}
+
 // GetContractState mocks base method
 func (m *MockWorkingSet) GetContractState(arg0 hash.PKHash, arg1 hash.Hash32B) (hash.Hash32B, error) {
 	ret := m.ctrl.Call(m, "GetContractState", arg0, arg1)
@@ -161,3 +220,27 @@ func (m *MockWorkingSet) SetContractState(arg0 hash.PKHash, arg1, arg2 hash.Hash
 func (mr *MockWorkingSetMockRecorder) SetContractState(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContractState", reflect.TypeOf((*MockWorkingSet)(nil).SetContractState), arg0, arg1, arg2)
 }
+
+// DeleteContractState mocks base method
+func (m *MockWorkingSet) DeleteContractState(arg0 hash.PKHash, arg1 hash.Hash32B) error {
+	ret := m.ctrl.Call(m, "DeleteContractState", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteContractState indicates an expected call of DeleteContractState
+func (mr *MockWorkingSetMockRecorder) DeleteContractState(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContractState", reflect.TypeOf((*MockWorkingSet)(nil).DeleteContractState), arg0, arg1)
+}
+
+// IterateContractState mocks base method
+func (m *MockWorkingSet) IterateContractState(arg0 hash.PKHash, arg1 trie.Visitor) error {
+	ret := m.ctrl.Call(m, "IterateContractState", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateContractState indicates an expected call of IterateContractState
+func (mr *MockWorkingSetMockRecorder) IterateContractState(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateContractState", reflect.TypeOf((*MockWorkingSet)(nil).IterateContractState), arg0, arg1)
+}
@@ -9,6 +9,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	db "github.com/iotexproject/iotex-core/db"
 	hash "github.com/iotexproject/iotex-core/pkg/hash"
+	trie "github.com/iotexproject/iotex-core/trie"
 	reflect "reflect"
 )
 
@@ -131,3 +132,28 @@ func (m *MockTrie) RootHash() hash.Hash32B {
 func (mr *MockTrieMockRecorder) RootHash() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RootHash", reflect.TypeOf((*MockTrie)(nil).RootHash))
 }
+
+// Prove mocks base method
+func (m *MockTrie) Prove(arg0 []byte) ([][]byte, error) {
+	ret := m.ctrl.Call(m, "Prove", arg0)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prove indicates an expected call of Prove
+func (mr *MockTrieMockRecorder) Prove(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prove", reflect.TypeOf((*MockTrie)(nil).Prove), arg0)
+}
+
+// Iterate mocks base method
+func (m *MockTrie) Iterate(arg0 trie.Visitor) error {
+	ret := m.ctrl.Call(m, "Iterate", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Iterate indicates an expected call of Iterate
+func (mr *MockTrieMockRecorder) Iterate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockTrie)(nil).Iterate), arg0)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// This is an operator tool to export the blockchain of a stopped node into a portable chain file, or to import a
+// chain file into it. Importing the same file again resumes an interrupted import
+// To use, run "make build" and
+//   ./bin/chainfile -config-path=[string] -export=[string] -start=[int] -end=[int]
+//   ./bin/chainfile -config-path=[string] -import=[string]
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+var (
+	// exportPath is the path of the chain file being exported to
+	exportPath string
+	// importPath is the path of the chain file being imported from
+	importPath string
+	// startHeight is the height of the first block being exported
+	startHeight int
+	// endHeight is the height of the last block being exported, default is the tip height
+	endHeight int
+)
+
+func init() {
+	flag.StringVar(&exportPath, "export", "", "Path of the chain file being exported to")
+	flag.StringVar(&importPath, "import", "", "Path of the chain file being imported from")
+	flag.IntVar(&startHeight, "start", 1, "Height of the first block being exported")
+	flag.IntVar(&endHeight, "end", -1, "Height of the last block being exported")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"usage: chainfile -config-path=[string] -export=[string] -start=[int] -end=[int]\n"+
+				"       chainfile -config-path=[string] -import=[string]\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+}
+
+func main() {
+	if (exportPath == "") == (importPath == "") || startHeight < 0 {
+		flag.Usage()
+	}
+	cfg, err := config.New()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to new config.")
+	}
+
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(cfg, blockchain.DefaultStateFactoryOption(), blockchain.BoltDBDaoOption())
+	if bc == nil {
+		logger.Fatal().Msg("Failed to create blockchain.")
+	}
+	if err := bc.Start(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to start blockchain.")
+	}
+	defer func() {
+		if err := bc.Stop(ctx); err != nil {
+			logger.Error().Err(err).Msg("Failed to stop blockchain.")
+		}
+	}()
+
+	if exportPath != "" {
+		if err := exportChain(bc, exportPath); err != nil {
+			logger.Error().Err(err).Msgf("Failed to export blockchain to %s.", exportPath)
+		}
+		return
+	}
+	if err := importChain(bc, importPath); err != nil {
+		logger.Error().Err(err).Msgf("Failed to import blockchain from %s.", importPath)
+	}
+}
+
+func exportChain(bc blockchain.Blockchain, path string) error {
+	end := bc.TipHeight()
+	if endHeight >= 0 {
+		end = uint64(endHeight)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	if err := blockchain.ExportChain(bc, w, uint64(startHeight), end); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	logger.Info().Uint64("start", uint64(startHeight)).Uint64("end", end).Msg("Exported blockchain.")
+	return nil
+}
+
+func importChain(bc blockchain.Blockchain, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = blockchain.ImportChain(bc, bufio.NewReader(file))
+	return err
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// This is an operator tool to roll the blockchain and the states of a stopped node back to a given height
+// To use, run "make build" and " ./bin/rollback -config-path=[string] -height=[int]"
+// The node must have been running with chain.enableHistoryState set in the config, as the states are restored from
+// the ones kept for the past heights. If the rollback is interrupted, it is resumed when the node or the tool starts
+// next time
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+// height is the blockchain height being rolled back to
+var height int
+
+func init() {
+	flag.IntVar(&height, "height", -1, "Height being rolled back to")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"usage: rollback -config-path=[string] -height=[int]\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+}
+
+func main() {
+	if height < 0 {
+		flag.Usage()
+	}
+	cfg, err := config.New()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to new config.")
+	}
+	if !cfg.Chain.EnableHistoryState {
+		logger.Fatal().Msg("Cannot roll back the states without chain.enableHistoryState in the config.")
+	}
+
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(cfg, blockchain.DefaultStateFactoryOption(), blockchain.BoltDBDaoOption())
+	if bc == nil {
+		logger.Fatal().Msg("Failed to create blockchain.")
+	}
+	if err := bc.Start(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to start blockchain.")
+	}
+	defer func() {
+		if err := bc.Stop(ctx); err != nil {
+			logger.Error().Err(err).Msg("Failed to stop blockchain.")
+		}
+	}()
+
+	tipHeight := bc.TipHeight()
+	if err := bc.RollbackTo(uint64(height)); err != nil {
+		logger.Error().Err(err).Msgf("Failed to roll back blockchain from height %d to %d.", tipHeight, height)
+		return
+	}
+	logger.Info().
+		Uint64("from", tipHeight).
+		Uint64("to", bc.TipHeight()).
+		Msg("Rolled back blockchain.")
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// This is an operator tool to export the state snapshot of a stopped node on a given height. Exporting a height lower
+// than the tip requires chain.enableHistoryState. Another node could be bootstrapped from the snapshot by setting
+// chain.stateSnapshotPath
+// To use, run "make build" and "./bin/snapshot -config-path=[string] -path=[string] -height=[int]"
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+var (
+	// snapshotPath is the path of the snapshot being exported to
+	snapshotPath string
+	// snapshotHeight is the height of the snapshot, default is the tip height
+	snapshotHeight int
+)
+
+func init() {
+	flag.StringVar(&snapshotPath, "path", "", "Path of the snapshot being exported to")
+	flag.IntVar(&snapshotHeight, "height", -1, "Height of the snapshot")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "usage: snapshot -config-path=[string] -path=[string] -height=[int]\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+}
+
+func main() {
+	if snapshotPath == "" {
+		flag.Usage()
+	}
+	cfg, err := config.New()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to new config.")
+	}
+
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(cfg, blockchain.DefaultStateFactoryOption(), blockchain.BoltDBDaoOption())
+	if bc == nil {
+		logger.Fatal().Msg("Failed to create blockchain.")
+	}
+	if err := bc.Start(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to start blockchain.")
+	}
+	defer func() {
+		if err := bc.Stop(ctx); err != nil {
+			logger.Error().Err(err).Msg("Failed to stop blockchain.")
+		}
+	}()
+
+	height := bc.TipHeight()
+	if snapshotHeight >= 0 {
+		height = uint64(snapshotHeight)
+	}
+	if err := exportSnapshot(bc, snapshotPath, height); err != nil {
+		logger.Error().Err(err).Msgf("Failed to export snapshot to %s.", snapshotPath)
+	}
+}
+
+func exportSnapshot(bc blockchain.Blockchain, path string, height uint64) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	if err := blockchain.ExportSnapshot(bc, w, height); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	logger.Info().Uint64("height", height).Msg("Exported snapshot.")
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// This is an operator tool to verify that the current version re-executes the blockchain deterministically. It opens
+// the chain DB and trie DB of a node read-only, re-executes every block from genesis into a fresh in-memory state
+// factory, and reports the first height where the state root, receipts or candidates diverge from the ones stored
+// To use, run "make build" and "./bin/verifychain -config-path=[string] -candidates=[bool]"
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+// verifyCandidates indicates whether to compare the candidates with the ones stored in trie DB
+var verifyCandidates bool
+
+func init() {
+	flag.BoolVar(&verifyCandidates, "candidates", true, "Compare the candidates with the ones stored in trie DB")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "usage: verifychain -config-path=[string] -candidates=[bool]\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+}
+
+func main() {
+	cfg, err := config.New()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to new config.")
+	}
+
+	chainDB := db.NewReadOnlyBoltDB(cfg.Chain.ChainDBPath, &cfg.DB)
+	var trieDB db.KVStore
+	if verifyCandidates {
+		trieDB = db.NewReadOnlyBoltDB(cfg.Chain.TrieDBPath, &cfg.DB)
+	}
+	divergence, err := blockchain.VerifyChain(context.Background(), cfg, chainDB, trieDB)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to verify blockchain.")
+	}
+	if divergence != nil {
+		logger.Error().
+			Uint64("height", divergence.Height).
+			Str("reason", divergence.Reason).
+			Msg("Blockchain diverges.")
+		os.Exit(1)
+	}
+	logger.Info().Msg("Verified blockchain.")
+}
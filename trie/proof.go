@@ -0,0 +1,125 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package trie
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// Prove returns the merkle proof of an entry, which is the serialized patricia nodes on the path from root to the
+// node holding the entry. If the entry does not exist, the proof ends at the node where the path diverges, and can be
+// used to prove the non-existence of the entry
+func (t *trie) Prove(key []byte) ([][]byte, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.root == nil {
+		return nil, errors.Wrap(ErrNotExist, "failed to load root")
+	}
+	proof := [][]byte{}
+	ptr := t.root
+	for {
+		node, err := ptr.serialize()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode patricia node")
+		}
+		proof = append(proof, node)
+		next, remain, ok := proofDescend(ptr, key)
+		if !ok || next == nil {
+			return proof, nil
+		}
+		if ptr, err = t.getPatricia(next); err != nil {
+			return nil, errors.Wrap(err, "failed to get patricia node on the path")
+		}
+		key = remain
+	}
+}
+
+// VerifyProof verifies the merkle proof of an entry against the root hash. It returns the value of the entry if the
+// proof shows the entry exists, or ErrNotExist if the proof shows the entry does not exist
+func VerifyProof(root hash.Hash32B, key []byte, proof [][]byte) ([]byte, error) {
+	expected := root[:]
+	for i, node := range proof {
+		ptr, err := decodePatricia(node)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidProof, "failed to decode node %d: %v", i, err)
+		}
+		h := ptr.hash()
+		if !bytes.Equal(h[:], expected) {
+			return nil, errors.Wrapf(ErrInvalidProof, "hash of node %d = %x does not match %x", i, h, expected)
+		}
+		next, remain, ok := proofDescend(ptr, key)
+		if !ok {
+			if i != len(proof)-1 {
+				return nil, errors.Wrapf(ErrInvalidProof, "path diverges at node %d before the end of proof", i)
+			}
+			return nil, errors.Wrapf(ErrNotExist, "key = %x", key)
+		}
+		if next == nil {
+			// reach the leaf holding the value
+			if i != len(proof)-1 {
+				return nil, errors.Wrapf(ErrInvalidProof, "proof has extra nodes after leaf %d", i)
+			}
+			_, v, err := ptr.blob()
+			return v, err
+		}
+		expected = next
+		key = remain
+	}
+	return nil, errors.Wrap(ErrInvalidProof, "proof ends before reaching the entry")
+}
+
+// proofDescend follows the key down one level. It returns the hash of the next node and the remaining key, or nil
+// hash if the node is the leaf holding the entry. ok is false if the path of the node diverges from the key
+func proofDescend(ptr patricia, key []byte) ([]byte, []byte, bool) {
+	switch node := ptr.(type) {
+	case *branch:
+		if len(key) == 0 || node.Path[key[0]] == nil {
+			return nil, nil, false
+		}
+		return node.Path[key[0]], key[1:], true
+	case *leaf:
+		if node.Ext == 1 {
+			if len(key) <= len(node.Path) || !bytes.Equal(node.Path, key[:len(node.Path)]) {
+				return nil, nil, false
+			}
+			return node.Value, key[len(node.Path):], true
+		}
+		if !bytes.Equal(node.Path, key) {
+			return nil, nil, false
+		}
+		return nil, nil, true
+	}
+	return nil, nil, false
+}
+
+// decodePatricia decodes the serialized patricia node
+func decodePatricia(node []byte) (patricia, error) {
+	if len(node) == 0 {
+		return nil, errors.Wrap(ErrInvalidPatricia, "empty node")
+	}
+	var ptr patricia
+	// first byte of serialized data is type
+	switch node[0] {
+	case 2:
+		ptr = &branch{}
+	case 1:
+		ptr = &leaf{}
+	case 0:
+		ptr = &leaf{}
+	default:
+		return nil, errors.Wrapf(ErrInvalidPatricia, "invalid node type = %v", node[0])
+	}
+	if err := ptr.deserialize(node); err != nil {
+		return nil, err
+	}
+	return ptr, nil
+}
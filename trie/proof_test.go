@@ -0,0 +1,69 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package trie
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/db"
+)
+
+func TestProve(t *testing.T) {
+	require := require.New(t)
+
+	tr, err := NewTrie(db.NewMemKVStore(), "test", EmptyRoot)
+	require.Nil(err)
+	require.Nil(tr.Start(context.Background()))
+
+	// proof of non-existence in empty trie
+	proof, err := tr.Prove(cat)
+	require.Nil(err)
+	require.Equal(1, len(proof))
+	_, err = VerifyProof(EmptyRoot, cat, proof)
+	require.Equal(ErrNotExist, errors.Cause(err))
+
+	keys := [][]byte{ham, car, cat, egg, dog, fox, cow}
+	for i, k := range keys {
+		require.Nil(tr.Upsert(k, testV[i]))
+	}
+	root := tr.RootHash()
+	for i, k := range keys {
+		proof, err := tr.Prove(k)
+		require.Nil(err)
+		v, err := VerifyProof(root, k, proof)
+		require.Nil(err)
+		require.Equal(testV[i], v)
+		// proof does not match another key or root
+		_, err = VerifyProof(EmptyRoot, k, proof)
+		require.Equal(ErrInvalidProof, errors.Cause(err))
+	}
+
+	// proof of non-existence
+	for _, k := range [][]byte{rat, ant} {
+		proof, err := tr.Prove(k)
+		require.Nil(err)
+		_, err = VerifyProof(root, k, proof)
+		require.Equal(ErrNotExist, errors.Cause(err))
+	}
+
+	// tampered proof
+	proof, err = tr.Prove(cat)
+	require.Nil(err)
+	_, err = VerifyProof(root, cat, proof[:len(proof)-1])
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+	tampered := make([]byte, len(proof[len(proof)-1]))
+	copy(tampered, proof[len(proof)-1])
+	tampered[len(tampered)-1]++
+	proof[len(proof)-1] = tampered
+	_, err = VerifyProof(root, cat, proof)
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+	require.Nil(tr.Stop(context.Background()))
+}
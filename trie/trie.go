@@ -38,23 +38,34 @@ var (
 	// ErrNotExist indicates entry does not exist
 	ErrNotExist = errors.New("not exist in trie")
 
+	// ErrInvalidProof indicates the merkle proof does not match the root hash or the key
+	ErrInvalidProof = errors.New("invalid merkle proof")
+
 	// EmptyRoot is the root hash of an empty trie
 	EmptyRoot = hash.Hash32B{0xe, 0x57, 0x51, 0xc0, 0x26, 0xe5, 0x43, 0xb2, 0xe8, 0xab, 0x2e, 0xb0, 0x60, 0x99,
 		0xda, 0xa1, 0xd1, 0xe5, 0xdf, 0x47, 0x77, 0x8f, 0x77, 0x87, 0xfa, 0xab, 0x45, 0xcd, 0xf1, 0x2f, 0xe3, 0xa8}
+
+	// errStopCount stops iterating the trie when enough entries are counted
+	errStopCount = errors.New("stop counting entries")
 )
 
 type (
 	// Trie is the interface of Merkle Patricia Trie
 	Trie interface {
 		lifecycle.StartStopper
-		TrieDB() db.KVStore          // return the underlying DB instance
-		Upsert([]byte, []byte) error // insert a new entry
-		Get([]byte) ([]byte, error)  // retrieve an existing entry
-		Delete([]byte) error         // delete an entry
-		Commit() error               // commit the state changes in a batch
-		RootHash() hash.Hash32B      // returns trie's root hash
+		TrieDB() db.KVStore             // return the underlying DB instance
+		Upsert([]byte, []byte) error    // insert a new entry
+		Get([]byte) ([]byte, error)     // retrieve an existing entry
+		Delete([]byte) error            // delete an entry
+		Commit() error                  // commit the state changes in a batch
+		RootHash() hash.Hash32B         // returns trie's root hash
+		Prove([]byte) ([][]byte, error) // returns the merkle proof of an entry
+		Iterate(Visitor) error          // visits all entries in the order of key
 	}
 
+	// Visitor is called with the key and value of each entry when iterating the trie
+	Visitor func([]byte, []byte) error
+
 	// trie implements the Trie interface
 	trie struct {
 		lifecycle lifecycle.Lifecycle
@@ -65,28 +76,53 @@ type (
 		bucket    string     // bucket name to store the nodes
 		clpsK     []byte     // path if the node can collapse after deleting an entry
 		clpsV     []byte     // value if the node can collapse after deleting an entry
-		numEntry  uint64     // number of entries added to the trie
 		numBranch uint64
 		numExt    uint64
 		numLeaf   uint64
 		dao       db.CachedKVStore
+		keepNodes bool // keep the nodes of previous versions in DB instead of deleting them
 	}
 )
 
+// Option sets Trie construction parameter
+type Option func(*trie) error
+
+// KeepHistoryOption keeps the patricia nodes replaced by updates and deletes in DB, so that the trie at an earlier
+// root hash can still be opened and queried. The history starts from the first update with the option, and the nodes
+// replaced before it, e.g., by a node running without the option or before the option was introduced, are gone
+func KeepHistoryOption() Option {
+	return func(t *trie) error {
+		t.keepNodes = true
+		return nil
+	}
+}
+
 // NewTrie creates a trie with DB filename
-func NewTrie(kvStore db.KVStore, name string, root hash.Hash32B) (Trie, error) {
+func NewTrie(kvStore db.KVStore, name string, root hash.Hash32B, opts ...Option) (Trie, error) {
 	if kvStore == nil {
 		return nil, errors.New("Failed to create KV store for Trie")
 	}
-	return newTrie(kvStore, name, root), nil
+	t := newTrie(kvStore, name, root)
+	for _, opt := range opts {
+		if err := opt(t); err != nil {
+			return nil, errors.Wrap(err, "failed to execute trie creation option")
+		}
+	}
+	return t, nil
 }
 
 // NewTrieSharedDB creates a trie with the shared DB instance
-func NewTrieSharedDB(kvStore db.CachedKVStore, name string, root hash.Hash32B) (Trie, error) {
+func NewTrieSharedDB(kvStore db.CachedKVStore, name string, root hash.Hash32B, opts ...Option) (Trie, error) {
 	if kvStore == nil {
 		return nil, errors.New("Failed to create KV store for Trie")
 	}
-	return newTrieSharedDB(kvStore, name, root), nil
+	t := newTrieSharedDB(kvStore, name, root)
+	for _, opt := range opts {
+		if err := opt(t); err != nil {
+			return nil, errors.Wrap(err, "failed to execute trie creation option")
+		}
+	}
+	return t, nil
 }
 
 func (t *trie) Start(ctx context.Context) error {
@@ -133,9 +169,14 @@ func (t *trie) Delete(key []byte) error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 
+	// the remaining entry collapses into leaf if there are only 2 entries, which are counted without visiting the
+	// whole trie
+	numEntry, err := t.countEntry(2)
+	if err != nil {
+		return errors.Wrap(err, "failed to count entries")
+	}
 	var ptr patricia
 	var size int
-	var err error
 	ptr, size, err = t.query(key)
 	if size != len(key) {
 		return errors.Wrapf(ErrNotExist, "key = %x not exist", key)
@@ -161,12 +202,8 @@ func (t *trie) Delete(key []byte) error {
 	if childClps, clpsType, err = t.delete(ptr, index); err != nil {
 		return errors.Wrap(err, "failed to delete")
 	}
-	if t.numEntry == 1 {
-		return errors.Wrapf(ErrInvalidTrie, "trie has more entries than ever added")
-	}
-	t.numEntry--
-	if t.numEntry == 2 {
-		// only 1 entry left (the other being the root), collapse into leaf
+	if numEntry == 2 {
+		// only 1 entry left, collapse into leaf
 		clpsType = 0
 	}
 	// update upstream nodes on path ascending to root
@@ -189,27 +226,38 @@ func (t *trie) RootHash() hash.Hash32B {
 	return t.rootHash
 }
 
+// Iterate visits all entries in the trie in the order of key, and stops at the first error returned by visitor
+func (t *trie) Iterate(visitor Visitor) error {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.root == nil {
+		return errors.Wrap(ErrNotExist, "failed to load root")
+	}
+	return t.iterate(t.root, nil, visitor)
+}
+
 //======================================
 // private functions
 //======================================
 // newTrie creates a trie
 func newTrie(dao db.KVStore, name string, root hash.Hash32B) *trie {
-	t := &trie{dao: db.NewCachedKVStore(dao), rootHash: root, toRoot: list.New(), bucket: name, numEntry: 1, numBranch: 1}
+	t := &trie{dao: db.NewCachedKVStore(dao), rootHash: root, toRoot: list.New(), bucket: name, numBranch: 1}
 	t.lifecycle.Add(dao)
 	return t
 }
 
 // newTrieSharedDB creates a trie with shared DB
 func newTrieSharedDB(dao db.CachedKVStore, name string, root hash.Hash32B) *trie {
-	t := &trie{dao: dao, rootHash: root, toRoot: list.New(), bucket: name, numEntry: 1, numBranch: 1}
+	t := &trie{dao: dao, rootHash: root, toRoot: list.New(), bucket: name, numBranch: 1}
 	t.lifecycle.Add(dao)
 	return t
 }
 
 // loadRoot loads the root patricia from DB
 func (t *trie) loadRoot() error {
-	t.mutex.RLock()
-	defer t.mutex.RUnlock()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
 
 	if t.rootHash != EmptyRoot {
 		var err error
@@ -251,7 +299,6 @@ func (t *trie) upsert(key, value []byte) error {
 		t.numBranch += uint64(nb)
 		t.numExt += uint64(ne)
 		t.numLeaf += uint64(nl)
-		t.numEntry++
 		// if the diverging node is leaf, delete it
 		n := t.toRoot.Back()
 		if _, ok := n.Value.(patricia).(*leaf); ok {
@@ -330,6 +377,56 @@ func (t *trie) query(key []byte) (patricia, int, error) {
 	return ptr, size, nil
 }
 
+// iterate visits all entries under the patricia node, prefix is the path from root to the node
+func (t *trie) iterate(ptr patricia, prefix []byte, visitor Visitor) error {
+	switch node := ptr.(type) {
+	case *branch:
+		for i := 0; i < RADIX; i++ {
+			if len(node.Path[i]) == 0 {
+				continue
+			}
+			child, err := t.getPatricia(node.Path[i])
+			if err != nil {
+				return err
+			}
+			path := make([]byte, len(prefix), len(prefix)+1)
+			copy(path, prefix)
+			if err := t.iterate(child, append(path, byte(i)), visitor); err != nil {
+				return err
+			}
+		}
+	case *leaf:
+		path := make([]byte, len(prefix), len(prefix)+len(node.Path))
+		copy(path, prefix)
+		path = append(path, node.Path...)
+		if node.Ext == 1 {
+			child, err := t.getPatricia(node.Value)
+			if err != nil {
+				return err
+			}
+			return t.iterate(child, path, visitor)
+		}
+		return visitor(path, node.Value)
+	}
+	return nil
+}
+
+// countEntry counts the entries in the trie, and stops once there are more than limit entries, so that only the paths
+// to the first entries are visited
+func (t *trie) countEntry(limit int) (int, error) {
+	numEntry := 0
+	if err := t.iterate(t.root, nil, func([]byte, []byte) error {
+		numEntry++
+		if numEntry > limit {
+			return errStopCount
+		}
+		return nil
+	}); err != nil && err != errStopCount {
+		return 0, err
+	}
+	return numEntry, nil
+}
+
 // delete removes the entry stored in patricia node, and returns if the node can collapse
 func (t *trie) delete(ptr patricia, index byte) (bool, byte, error) {
 	var childClps bool
@@ -461,22 +558,7 @@ func (t *trie) getPatricia(key []byte) (patricia, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get key %x", key[:8])
 	}
-	var ptr patricia
-	// first byte of serialized data is type
-	switch node[0] {
-	case 2:
-		ptr = &branch{}
-	case 1:
-		ptr = &leaf{}
-	case 0:
-		ptr = &leaf{}
-	default:
-		return nil, errors.Wrapf(ErrInvalidPatricia, "invalid node type = %v", node[0])
-	}
-	if err := ptr.deserialize(node); err != nil {
-		return nil, err
-	}
-	return ptr, nil
+	return decodePatricia(node)
 }
 
 // putPatricia stores the patricia node into DB
@@ -504,7 +586,11 @@ func (t *trie) putPatriciaNew(ptr patricia) error {
 }
 
 // delPatricia deletes the patricia node from DB
+// the node is kept if the trie is configured to keep history
 func (t *trie) delPatricia(ptr patricia) error {
+	if t.keepNodes {
+		return nil
+	}
 	key := ptr.hash()
 	logger.Debug().Hex("key", key[:8]).Msg("del")
 	return t.dao.Delete(t.bucket, key[:])
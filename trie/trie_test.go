@@ -7,6 +7,7 @@
 package trie
 
 import (
+	"bytes"
 	"context"
 	"testing"
 	"time"
@@ -310,7 +311,9 @@ func TestInsert(t *testing.T) {
 	err = tr.Delete(cat)
 	require.Nil(err)
 	require.Equal(EmptyRoot, tr.RootHash())
-	require.Equal(uint64(1), tr.numEntry)
+	numEntry, err := tr.countEntry(2)
+	require.Nil(err)
+	require.Equal(0, numEntry)
 	require.Nil(tr.Stop(context.Background()))
 }
 
@@ -531,3 +534,140 @@ func TestQuery(t *testing.T) {
 	require.Nil(err)
 	require.Nil(tr.Stop(context.Background()))
 }
+
+func TestKeepHistory(t *testing.T) {
+	require := require.New(t)
+
+	kv := db.NewMemKVStore()
+	tr, err := NewTrie(kv, "test", EmptyRoot, KeepHistoryOption())
+	require.Nil(err)
+	require.Nil(tr.Start(context.Background()))
+	require.Nil(tr.Upsert(cat, testV[2]))
+	require.Nil(tr.Upsert(car, testV[1]))
+	require.Nil(tr.Commit())
+	root := tr.RootHash()
+	// update and add entries, the nodes of the old root should be kept
+	require.Nil(tr.Upsert(cat, testV[6]))
+	require.Nil(tr.Upsert(egg, testV[4]))
+	require.Nil(tr.Commit())
+	root1 := tr.RootHash()
+	require.NotEqual(root, root1)
+	require.Nil(tr.Stop(context.Background()))
+
+	// open the trie at the old root
+	tr1, err := NewTrie(kv, "test", root)
+	require.Nil(err)
+	require.Nil(tr1.Start(context.Background()))
+	v, err := tr1.Get(cat)
+	require.Nil(err)
+	require.Equal(testV[2], v)
+	v, err = tr1.Get(car)
+	require.Nil(err)
+	require.Equal(testV[1], v)
+	_, err = tr1.Get(egg)
+	require.Equal(ErrNotExist, errors.Cause(err))
+	require.Nil(tr1.Stop(context.Background()))
+
+	// open the trie at the new root
+	tr2, err := NewTrie(kv, "test", root1)
+	require.Nil(err)
+	require.Nil(tr2.Start(context.Background()))
+	v, err = tr2.Get(cat)
+	require.Nil(err)
+	require.Equal(testV[6], v)
+	v, err = tr2.Get(car)
+	require.Nil(err)
+	require.Equal(testV[1], v)
+	v, err = tr2.Get(egg)
+	require.Nil(err)
+	require.Equal(testV[4], v)
+	require.Nil(tr2.Stop(context.Background()))
+}
+
+func TestIterate(t *testing.T) {
+	require := require.New(t)
+
+	tr, err := NewTrie(db.NewMemKVStore(), "test", EmptyRoot)
+	require.Nil(err)
+	require.Nil(tr.Start(context.Background()))
+	require.Nil(tr.Iterate(func(k, v []byte) error {
+		return errors.New("empty trie should not have any entry")
+	}))
+
+	keys := [][]byte{ham, car, cat, rat, egg, dog, fox, cow, ant}
+	values := map[string][]byte{}
+	for i, k := range keys {
+		v := []byte{byte(i)}
+		require.Nil(tr.Upsert(k, v))
+		values[string(k)] = v
+	}
+	var last []byte
+	visited := 0
+	require.Nil(tr.Iterate(func(k, v []byte) error {
+		require.True(bytes.Compare(last, k) < 0)
+		require.Equal(values[string(k)], v)
+		last = k
+		visited++
+		return nil
+	}))
+	require.Equal(len(keys), visited)
+
+	// stop at the first error
+	visited = 0
+	err = tr.Iterate(func(k, v []byte) error {
+		visited++
+		return ErrInvalidTrie
+	})
+	require.Equal(ErrInvalidTrie, errors.Cause(err))
+	require.Equal(1, visited)
+	require.Nil(tr.Stop(context.Background()))
+}
+
+func TestDeleteAfterReload(t *testing.T) {
+	require := require.New(t)
+
+	kv := db.NewMemKVStore()
+	tr, err := NewTrie(kv, "test", EmptyRoot)
+	require.Nil(err)
+	require.Nil(tr.Start(context.Background()))
+	require.Nil(tr.Upsert(cat, testV[2]))
+	require.Nil(tr.Upsert(car, testV[1]))
+	require.Nil(tr.Upsert(egg, testV[4]))
+	require.Nil(tr.Upsert(ham, testV[0]))
+	require.Nil(tr.Commit())
+	root := tr.RootHash()
+
+	// entries of the reloaded trie are counted before deleting, up to the limit
+	tr1, err := NewTrie(kv, "test", root)
+	require.Nil(err)
+	require.Nil(tr1.Start(context.Background()))
+	require.Nil(tr1.Upsert(dog, testV[3]))
+	require.Nil(tr1.Delete(dog))
+	numEntry, err := tr1.(*trie).countEntry(2)
+	require.Nil(err)
+	require.Equal(3, numEntry)
+	numEntry, err = tr1.(*trie).countEntry(10)
+	require.Nil(err)
+	require.Equal(4, numEntry)
+	require.Equal(root, tr1.RootHash())
+	// deleting from the reloaded trie is the same as deleting from the original one
+	require.Nil(tr.Delete(egg))
+	require.Nil(tr1.Delete(egg))
+	require.Equal(tr.RootHash(), tr1.RootHash())
+	_, err = tr1.Get(egg)
+	require.Equal(ErrNotExist, errors.Cause(err))
+	v, err := tr1.Get(cat)
+	require.Nil(err)
+	require.Equal(testV[2], v)
+	v, err = tr1.Get(ham)
+	require.Nil(err)
+	require.Equal(testV[0], v)
+	// the last entry of the reloaded trie collapses the same as the one of the original trie
+	for _, key := range [][]byte{car, ham} {
+		require.Nil(tr.Delete(key))
+		require.Nil(tr1.Delete(key))
+		require.Equal(tr.RootHash(), tr1.RootHash())
+	}
+	require.Nil(tr1.Stop(context.Background()))
+	require.Nil(tr.Stop(context.Background()))
+}